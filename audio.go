@@ -45,6 +45,16 @@ func (t *AudioPlayerNode) AudioPlayer() *components.AudioPlayer {
 	return t.wap.Data()
 }
 
+func (t *AudioPlayerNode) SetParent(parent ObjectContainer) {
+	if t.parent != nil {
+		t.parent.RemoveChild(t)
+	}
+	t.mObject.SetParent(parent)
+	if parent != nil {
+		parent.AddChild(t)
+	}
+}
+
 func (t *AudioPlayerNode) Destroy() {
 	t.wap.Data().Pause()
 	t.wap = nil
@@ -3,6 +3,7 @@ package audio
 import (
 	"sync"
 
+	"github.com/gabstv/primen/core/logger"
 	"github.com/hajimehoshi/ebiten/audio"
 )
 
@@ -34,6 +35,9 @@ func Context() *audio.Context {
 	if actx != nil {
 		return actx
 	}
-	actx, _ = audio.NewContext(int(DefaultRate))
+	var err error
+	if actx, err = audio.NewContext(int(DefaultRate)); err != nil {
+		logger.Default.Tag("audio").Error("creating the audio context failed", logger.F("rate", int(DefaultRate)), logger.Err(err))
+	}
 	return actx
 }
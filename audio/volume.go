@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"sync"
+)
+
+// MasterBus is the volume bus applied to all the players
+const MasterBus = "master"
+
+var (
+	volumes   = make(map[string]float64)
+	volumesRv uint64
+	vm        sync.RWMutex
+)
+
+// SetBusVolume sets the volume (0 to 1) of a bus ("master", "music", "sfx",
+// ...). The volume of a player is multiplied by the volume of its bus and by
+// the volume of the MasterBus.
+func SetBusVolume(bus string, volume float64) {
+	if volume < 0 {
+		volume = 0
+	}
+	vm.Lock()
+	defer vm.Unlock()
+	if v, ok := volumes[bus]; ok && v == volume {
+		return
+	}
+	volumes[bus] = volume
+	volumesRv++
+}
+
+// BusVolume returns the volume of a bus (1 if it was never set)
+func BusVolume(bus string) float64 {
+	vm.RLock()
+	defer vm.RUnlock()
+	if v, ok := volumes[bus]; ok {
+		return v
+	}
+	return 1
+}
+
+// BusVolumes returns the volumes of all the buses that were set
+func BusVolumes() map[string]float64 {
+	vm.RLock()
+	defer vm.RUnlock()
+	out := make(map[string]float64, len(volumes))
+	for k, v := range volumes {
+		out[k] = v
+	}
+	return out
+}
+
+// EffectiveVolume returns the volume of a bus multiplied by the volume of
+// the MasterBus
+func EffectiveVolume(bus string) float64 {
+	vm.RLock()
+	defer vm.RUnlock()
+	v := 1.0
+	if mv, ok := volumes[MasterBus]; ok {
+		v = mv
+	}
+	if bus != "" && bus != MasterBus {
+		if bv, ok := volumes[bus]; ok {
+			v *= bv
+		}
+	}
+	return v
+}
+
+// VolumesRevision is incremented every time a bus volume changes
+func VolumesRevision() uint64 {
+	vm.RLock()
+	defer vm.RUnlock()
+	return volumesRv
+}
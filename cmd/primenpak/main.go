@@ -0,0 +1,172 @@
+package main
+
+import (
+	"compress/flate"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gabstv/primen/io/pak"
+	"github.com/urfave/cli"
+)
+
+const (
+	flagOutput = "output"
+	flagKey    = "key"
+	flagLevel  = "level"
+	flagStore  = "store"
+	flagSign   = "sign-only"
+)
+
+var keyFlag = cli.StringFlag{
+	Name:   flagKey + ", k",
+	Usage:  "Signature and encryption key (passphrase)",
+	EnvVar: "PRIMEN_PAK_KEY",
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "primenpak"
+	app.Description = "Build and inspect pak archives (io/pak)."
+
+	app.Commands = []cli.Command{
+		{
+			Name:      "build",
+			ShortName: "b",
+			Usage:     "build a pak from an asset directory",
+			ArgsUsage: "<dir>",
+			Action:    cmdBuild(),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  flagOutput + ", o",
+					Usage: "Output file",
+					Value: "assets.pak",
+				},
+				keyFlag,
+				cli.IntFlag{
+					Name:  flagLevel,
+					Usage: "Deflate level (1-9, -1 = default)",
+					Value: flate.DefaultCompression,
+				},
+				cli.StringSliceFlag{
+					Name:  flagStore,
+					Usage: "Extension of the files that are not compressed (eg: .png); replaces the defaults",
+				},
+				cli.BoolFlag{
+					Name:  flagSign,
+					Usage: "Sign the pak with the key without encrypting the files",
+				},
+			},
+		},
+		{
+			Name:      "verify",
+			ShortName: "v",
+			Usage:     "verify the checksums of a pak",
+			ArgsUsage: "<file.pak>",
+			Action:    cmdVerify(),
+			Flags: []cli.Flag{
+				keyFlag,
+			},
+		},
+		{
+			Name:      "list",
+			ShortName: "l",
+			Usage:     "list the files of a pak",
+			ArgsUsage: "<file.pak>",
+			Action:    cmdList(),
+			Flags: []cli.Flag{
+				keyFlag,
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func getKey(c *cli.Context) []byte {
+	if v := c.String(flagKey); v != "" {
+		return pak.KeyFromString(v)
+	}
+	return nil
+}
+
+func cmdBuild() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		dir := c.Args().First()
+		if dir == "" {
+			return errors.New("no asset directory specified")
+		}
+		opt := pak.WriterOptions{
+			Key:          getKey(c),
+			Level:        c.Int(flagLevel),
+			NoEncryption: c.Bool(flagSign),
+		}
+		if exts := c.StringSlice(flagStore); len(exts) > 0 {
+			store := make(map[string]bool)
+			for _, v := range exts {
+				store["."+strings.TrimPrefix(strings.ToLower(v), ".")] = true
+			}
+			opt.Compress = func(name string) bool {
+				return !store[strings.ToLower(path.Ext(name))]
+			}
+		}
+		n, err := pak.BuildDir(dir, c.String(flagOutput), opt)
+		if err != nil {
+			return fmt.Errorf("error building pak %w", err)
+		}
+		fmt.Printf("%v: %d files (signed: %v, encrypted: %v)\n", c.String(flagOutput), n, opt.Key != nil, opt.Key != nil && !opt.NoEncryption)
+		return nil
+	}
+}
+
+func openPak(c *cli.Context) (*pak.FS, error) {
+	name := c.Args().First()
+	if name == "" {
+		return nil, errors.New("no pak file specified")
+	}
+	fs, err := pak.Open(name, getKey(c))
+	if err != nil {
+		return nil, fmt.Errorf("error opening pak %w", err)
+	}
+	return fs, nil
+}
+
+func cmdVerify() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		fs, err := openPak(c)
+		if err != nil {
+			return err
+		}
+		defer fs.Close()
+		if err := fs.Verify(); err != nil {
+			return err
+		}
+		fmt.Printf("%v: %d files OK\n", c.Args().First(), len(fs.Names()))
+		return nil
+	}
+}
+
+func cmdList() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		fs, err := openPak(c)
+		if err != nil {
+			return err
+		}
+		defer fs.Close()
+		m := fs.Manifest()
+		for _, name := range fs.Names() {
+			e := m.Entries[name]
+			sum := e.SHA256
+			if len(sum) > 12 {
+				sum = sum[:12]
+			}
+			fmt.Printf("%10d  %v  %v\n", e.Size, sum, name)
+		}
+		return nil
+	}
+}
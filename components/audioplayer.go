@@ -3,7 +3,9 @@ package components
 import (
 	"time"
 
+	"github.com/gabstv/ecs/v2"
 	paudio "github.com/gabstv/primen/audio"
+	"github.com/gabstv/primen/core"
 	"github.com/hajimehoshi/ebiten/audio"
 )
 
@@ -11,6 +13,12 @@ type AudioPlayer struct {
 	ebiplayer    *audio.Player
 	panctrl      paudio.PanStream
 	pitchshifter *paudio.PitchShiftStream
+	pitch        float64
+	timeScale    float64
+	scalePaused  bool
+	bus          string
+	volume       float64
+	volumesRv    uint64
 	//channels
 }
 
@@ -23,6 +31,7 @@ type NewAudioPlayerInput struct {
 	Infinite      bool
 	IntroLength   int64
 	LoopLength    int64
+	Bus           string // volume bus (see audio.SetBusVolume); the master bus is always applied
 }
 
 func mustEbiPlayer(p *audio.Player, e error) *audio.Player {
@@ -59,11 +68,17 @@ func NewAudioPlayer(input NewAudioPlayerInput) AudioPlayer {
 	if input.Infinite {
 		lsrk = audio.NewInfiniteLoopWithIntro(lsrk, input.IntroLength, input.LoopLength)
 	}
-	return AudioPlayer{
+	p := AudioPlayer{
 		ebiplayer:    mustEbiPlayer(audio.NewPlayer(paudio.Context(), lsrk)),
 		panctrl:      pan,
 		pitchshifter: pshift,
+		pitch:        1,
+		timeScale:    1,
+		bus:          input.Bus,
+		volume:       1,
 	}
+	p.applyVolume()
+	return p
 }
 
 func (p *AudioPlayer) Play() {
@@ -71,6 +86,7 @@ func (p *AudioPlayer) Play() {
 }
 
 func (p *AudioPlayer) Pause() {
+	p.scalePaused = false
 	_ = p.ebiplayer.Pause()
 }
 
@@ -90,12 +106,31 @@ func (p *AudioPlayer) Seek(offset time.Duration) error {
 	return p.ebiplayer.Seek(offset)
 }
 
+// SetVolume sets the volume of the player. The volume of the bus of the
+// player is applied on top of it.
 func (p *AudioPlayer) SetVolume(volume float64) {
-	p.ebiplayer.SetVolume(volume)
+	p.volume = volume
+	p.applyVolume()
 }
 
 func (p *AudioPlayer) Volume() float64 {
-	return p.ebiplayer.Volume()
+	return p.volume
+}
+
+// Bus returns the volume bus of the player
+func (p *AudioPlayer) Bus() string {
+	return p.bus
+}
+
+// SetBus changes the volume bus of the player
+func (p *AudioPlayer) SetBus(bus string) {
+	p.bus = bus
+	p.applyVolume()
+}
+
+func (p *AudioPlayer) applyVolume() {
+	p.volumesRv = paudio.VolumesRevision()
+	p.ebiplayer.SetVolume(p.volume * paudio.EffectiveVolume(p.bus))
 }
 
 func (p *AudioPlayer) SetPan(pan float64) {
@@ -111,17 +146,74 @@ func (p *AudioPlayer) Pan() float64 {
 	return 0
 }
 
-func (p *AudioPlayer) SetPitch(pan float64) {
+// SetPitch sets the pitch (if PitchShift is enabled). The world time scale
+// is applied on top of it.
+func (p *AudioPlayer) SetPitch(pitch float64) {
+	p.pitch = pitch
 	if p.pitchshifter != nil {
-		p.pitchshifter.SetPitch(pan)
+		p.pitchshifter.SetPitch(pitch * p.timeScale)
 	}
 }
 
 func (p *AudioPlayer) Pitch() float64 {
 	if p.pitchshifter != nil {
-		return p.pitchshifter.Pitch()
+		return p.pitch
 	}
 	return 1
 }
 
+// applyTimeScale pauses the player while the world is frozen and shifts the
+// pitch (if PitchShift is enabled) by the time scale.
+func (p *AudioPlayer) applyTimeScale(scale float64) {
+	if scale <= 0 {
+		if p.ebiplayer.IsPlaying() {
+			_ = p.ebiplayer.Pause()
+			p.scalePaused = true
+		}
+		return
+	}
+	if p.scalePaused {
+		p.scalePaused = false
+		_ = p.ebiplayer.Play()
+	}
+	if scale != p.timeScale {
+		p.timeScale = scale
+		if p.pitchshifter != nil {
+			p.pitchshifter.SetPitch(p.pitch * scale)
+		}
+	}
+}
+
 //go:generate ecsgen -n AudioPlayer -p components -o audioplayer_component.go --component-tpl --vars "UUID=9C7DB259-6A3E-4DD3-B277-4B35DA5709AF"
+
+//go:generate ecsgen -n AudioPlayer -p components -o audioplayer_system.go --system-tpl --vars "Priority=10" --vars "UUID=9B745D04-1FFF-4245-B05C-71CF0C26C725" --components "AudioPlayer"
+
+var matchAudioPlayerSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetAudioPlayerComponent(w).Flag())
+}
+
+var resizematchAudioPlayerSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetAudioPlayerComponent(w).Flag())
+}
+
+// DrawPriority noop
+func (s *AudioPlayerSystem) DrawPriority(ctx core.DrawCtx) {}
+
+// Draw noop
+func (s *AudioPlayerSystem) Draw(ctx core.DrawCtx) {}
+
+// UpdatePriority noop
+func (s *AudioPlayerSystem) UpdatePriority(ctx core.UpdateCtx) {}
+
+// Update applies the world time scale and the bus volumes to the audio
+// players
+func (s *AudioPlayerSystem) Update(ctx core.UpdateCtx) {
+	scale := ctx.TimeScale()
+	rv := paudio.VolumesRevision()
+	for _, v := range s.V().Matches() {
+		v.AudioPlayer.applyTimeScale(scale)
+		if v.AudioPlayer.volumesRv != rv {
+			v.AudioPlayer.applyVolume()
+		}
+	}
+}
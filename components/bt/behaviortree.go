@@ -0,0 +1,212 @@
+package bt
+
+import (
+	"strings"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/core"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+// PathEntry is a node visited on the last tick
+type PathEntry struct {
+	Node   Node
+	Depth  int
+	Status Status
+}
+
+// BehaviorTree is the component data of an entity behavior tree
+type BehaviorTree struct {
+	tree       *Tree
+	blackboard Blackboard
+	mem        []memory
+	ticks      uint64
+	time       float64
+	status     Status
+	path       []PathEntry
+	debug      bool
+}
+
+// New returns a new BehaviorTree component data
+func New(tree *Tree) BehaviorTree {
+	return BehaviorTree{
+		tree:       tree,
+		blackboard: make(Blackboard),
+	}
+}
+
+//go:generate ecsgen -n BehaviorTree -p bt -o behaviortree_component.go --component-tpl --vars "UUID=EFC95B83-F063-48B6-AD29-1D476AA7E434"
+
+//go:generate ecsgen -n BehaviorTree -p bt -o behaviortree_system.go --system-tpl --vars "Priority=30" --vars "UUID=DDADDE0C-25AF-4AA5-8CA9-9FE942E09B22" --components "BehaviorTree" --members "debug=bool"
+
+// Tree returns the tree definition
+func (b *BehaviorTree) Tree() *Tree {
+	return b.tree
+}
+
+// Blackboard returns the blackboard of the entity
+func (b *BehaviorTree) Blackboard() Blackboard {
+	if b.blackboard == nil {
+		b.blackboard = make(Blackboard)
+	}
+	return b.blackboard
+}
+
+// Status returns the status of the root node on the last tick
+func (b *BehaviorTree) Status() Status {
+	return b.status
+}
+
+// SetDebug shows the active path of the tree (see BehaviorTreeSystem.Draw)
+func (b *BehaviorTree) SetDebug(v bool) {
+	b.debug = v
+}
+
+// Debug returns true if the active path of the tree is drawn
+func (b *BehaviorTree) Debug() bool {
+	return b.debug
+}
+
+// Reset resets the state of all the nodes (the blackboard is kept)
+func (b *BehaviorTree) Reset() {
+	b.mem = nil
+	b.status = Invalid
+	b.path = b.path[:0]
+}
+
+// ActivePath returns the nodes visited on the last tick (in the order they
+// were visited)
+func (b *BehaviorTree) ActivePath() []PathEntry {
+	out := make([]PathEntry, len(b.path))
+	copy(out, b.path)
+	return out
+}
+
+// DebugString returns the active path as an indented list
+func (b *BehaviorTree) DebugString() string {
+	sb := new(strings.Builder)
+	if b.tree != nil && b.tree.name != "" {
+		sb.WriteString(b.tree.name)
+		sb.WriteString("\n")
+	}
+	for _, v := range b.path {
+		sb.WriteString(strings.Repeat("  ", v.Depth))
+		sb.WriteString(v.Node.Label())
+		sb.WriteString(" [")
+		sb.WriteString(v.Status.String())
+		sb.WriteString("]\n")
+	}
+	return sb.String()
+}
+
+// Tick runs the tree once
+func (b *BehaviorTree) Tick(uctx core.UpdateCtx, w ecs.BaseWorld, e ecs.Entity, dt float64) Status {
+	if b.tree == nil {
+		return Invalid
+	}
+	if len(b.mem) != len(b.tree.nodes) {
+		b.mem = make([]memory, len(b.tree.nodes))
+	}
+	b.ticks++
+	b.time += dt
+	b.path = b.path[:0]
+	ctx := &Ctx{
+		Ctx:        uctx,
+		World:      w,
+		Entity:     e,
+		Blackboard: b.Blackboard(),
+		DT:         dt,
+		b:          b,
+	}
+	b.status = b.tickNode(ctx, b.tree.root)
+	return b.status
+}
+
+func (b *BehaviorTree) tickNode(ctx *Ctx, n Node) Status {
+	nb := n.base()
+	i := len(b.path)
+	b.path = append(b.path, PathEntry{
+		Node:  n,
+		Depth: ctx.depth,
+	})
+	mem := &b.mem[nb.id]
+	if mem.ticked+1 < b.ticks {
+		// the node wasn't ticked on the previous tick, so it's entered
+		// fresh: drop the state of an abandoned run (the cooldowns are kept)
+		*mem = memory{
+			until: mem.until,
+		}
+	}
+	mem.ticked = b.ticks
+	ctx.depth++
+	st := n.tick(ctx, mem)
+	ctx.depth--
+	b.path[i].Status = st
+	return st
+}
+
+// resetChildren resets the state of the descendants of a node (the
+// cooldowns are kept)
+func (b *BehaviorTree) resetChildren(n Node) {
+	nb := n.base()
+	for i := nb.id + 1; i < nb.id+nb.size; i++ {
+		b.mem[i] = memory{
+			until: b.mem[i].until,
+		}
+	}
+}
+
+var matchBehaviorTreeSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetBehaviorTreeComponent(w).Flag())
+}
+
+var resizematchBehaviorTreeSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetBehaviorTreeComponent(w).Flag())
+}
+
+// SetDebug shows the active path of all the trees
+func (s *BehaviorTreeSystem) SetDebug(v bool) {
+	s.debug = v
+}
+
+// DrawPriority noop
+func (s *BehaviorTreeSystem) DrawPriority(ctx core.DrawCtx) {}
+
+// Draw prints the active path of the trees with debug enabled (next to the
+// entity if it has a Transform)
+func (s *BehaviorTreeSystem) Draw(ctx core.DrawCtx) {
+	screen := ctx.Renderer().Screen()
+	y := 4
+	for _, v := range s.V().Matches() {
+		if !s.debug && !v.BehaviorTree.debug {
+			continue
+		}
+		text := v.BehaviorTree.DebugString()
+		if tr := components.GetTransformComponentData(s.world, v.Entity); tr != nil {
+			m := tr.GeoM()
+			x, ty := m.Apply(0, 0)
+			ebitenutil.DebugPrintAt(screen, text, int(x), int(ty))
+			continue
+		}
+		ebitenutil.DebugPrintAt(screen, text, 4, y)
+		y += 16 * (strings.Count(text, "\n") + 1)
+	}
+}
+
+// UpdatePriority noop
+func (s *BehaviorTreeSystem) UpdatePriority(ctx core.UpdateCtx) {}
+
+// Update ticks the trees (the trees of a paused world are not ticked)
+func (s *BehaviorTreeSystem) Update(ctx core.UpdateCtx) {
+	if ctx.TimeScale() == 0 {
+		return
+	}
+	dt := ctx.DT()
+	for _, v := range s.V().Matches() {
+		if !components.IsActive(s.world, v.Entity) {
+			continue
+		}
+		v.BehaviorTree.Tick(ctx, s.world, v.Entity, dt)
+	}
+}
@@ -0,0 +1,227 @@
+// Code generated by ecs https://github.com/gabstv/ecs; DO NOT EDIT.
+
+package bt
+
+import (
+    "sort"
+    
+
+    "github.com/gabstv/ecs/v2"
+)
+
+
+
+
+
+
+
+
+const uuidBehaviorTreeComponent = "EFC95B83-F063-48B6-AD29-1D476AA7E434"
+const capBehaviorTreeComponent = 256
+
+type drawerBehaviorTreeComponent struct {
+    Entity ecs.Entity
+    Data   BehaviorTree
+}
+
+// WatchBehaviorTree is a helper struct to access a valid pointer of BehaviorTree
+type WatchBehaviorTree interface {
+    Entity() ecs.Entity
+    Data() *BehaviorTree
+}
+
+type slcdrawerBehaviorTreeComponent []drawerBehaviorTreeComponent
+func (a slcdrawerBehaviorTreeComponent) Len() int           { return len(a) }
+func (a slcdrawerBehaviorTreeComponent) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a slcdrawerBehaviorTreeComponent) Less(i, j int) bool { return a[i].Entity < a[j].Entity }
+
+
+type mWatchBehaviorTree struct {
+    c *BehaviorTreeComponent
+    entity ecs.Entity
+}
+
+func (w *mWatchBehaviorTree) Entity() ecs.Entity {
+    return w.entity
+}
+
+func (w *mWatchBehaviorTree) Data() *BehaviorTree {
+    
+    
+    id := w.c.indexof(w.entity)
+    if id == -1 {
+        return nil
+    }
+    return &w.c.data[id].Data
+}
+
+// BehaviorTreeComponent implements ecs.BaseComponent
+type BehaviorTreeComponent struct {
+    initialized bool
+    flag        ecs.Flag
+    world       ecs.BaseWorld
+    wkey        [4]byte
+    data        []drawerBehaviorTreeComponent
+    
+}
+
+// GetBehaviorTreeComponent returns the instance of the component in a World
+func GetBehaviorTreeComponent(w ecs.BaseWorld) *BehaviorTreeComponent {
+    return w.C(uuidBehaviorTreeComponent).(*BehaviorTreeComponent)
+}
+
+// SetBehaviorTreeComponentData updates/adds a BehaviorTree to Entity e
+func SetBehaviorTreeComponentData(w ecs.BaseWorld, e ecs.Entity, data BehaviorTree) {
+    GetBehaviorTreeComponent(w).Upsert(e, data)
+}
+
+// GetBehaviorTreeComponentData gets the *BehaviorTree of Entity e
+func GetBehaviorTreeComponentData(w ecs.BaseWorld, e ecs.Entity) *BehaviorTree {
+    return GetBehaviorTreeComponent(w).Data(e)
+}
+
+// WatchBehaviorTreeComponentData gets a pointer getter of an entity's BehaviorTree.
+//
+// The pointer must not be stored because it may become invalid overtime.
+func WatchBehaviorTreeComponentData(w ecs.BaseWorld, e ecs.Entity) WatchBehaviorTree {
+    return &mWatchBehaviorTree{
+        c: GetBehaviorTreeComponent(w),
+        entity: e,
+    }
+}
+
+// UUID implements ecs.BaseComponent
+func (BehaviorTreeComponent) UUID() string {
+    return "EFC95B83-F063-48B6-AD29-1D476AA7E434"
+}
+
+// Name implements ecs.BaseComponent
+func (BehaviorTreeComponent) Name() string {
+    return "BehaviorTreeComponent"
+}
+
+func (c *BehaviorTreeComponent) indexof(e ecs.Entity) int {
+    i := sort.Search(len(c.data), func(i int) bool { return c.data[i].Entity >= e })
+    if i < len(c.data) && c.data[i].Entity == e {
+        return i
+    }
+    return -1
+}
+
+// Upsert creates or updates a component data of an entity.
+// Not recommended to be used directly. Use SetBehaviorTreeComponentData to change component
+// data outside of a system loop.
+func (c *BehaviorTreeComponent) Upsert(e ecs.Entity, data interface{}) {
+    v, ok := data.(BehaviorTree)
+    if !ok {
+        panic("data must be BehaviorTree")
+    }
+    
+    id := c.indexof(e)
+    
+    if id > -1 {
+        
+        dwr := &c.data[id]
+        dwr.Data = v
+        
+        return
+    }
+    
+    rsz := false
+    if cap(c.data) == len(c.data) {
+        rsz = true
+        c.world.CWillResize(c, c.wkey)
+        
+    }
+    newindex := len(c.data)
+    c.data = append(c.data, drawerBehaviorTreeComponent{
+        Entity: e,
+        Data:   v,
+    })
+    if len(c.data) > 1 {
+        if c.data[newindex].Entity < c.data[newindex-1].Entity {
+            c.world.CWillResize(c, c.wkey)
+            
+            sort.Sort(slcdrawerBehaviorTreeComponent(c.data))
+            rsz = true
+        }
+    }
+    
+    if rsz {
+        
+        c.world.CResized(c, c.wkey)
+        c.world.Dispatch(ecs.Event{
+            Type: ecs.EvtComponentsResized,
+            ComponentName: "BehaviorTreeComponent",
+            ComponentID: "EFC95B83-F063-48B6-AD29-1D476AA7E434",
+        })
+    }
+    
+    c.world.CAdded(e, c, c.wkey)
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentAdded,
+        ComponentName: "BehaviorTreeComponent",
+        ComponentID: "EFC95B83-F063-48B6-AD29-1D476AA7E434",
+        Entity: e,
+    })
+}
+
+// Remove a BehaviorTree data from entity e
+//
+// Warning: DO NOT call remove inside the system entities loop
+func (c *BehaviorTreeComponent) Remove(e ecs.Entity) {
+    
+    
+    i := c.indexof(e)
+    if i == -1 {
+        return
+    }
+    
+    //c.data = append(c.data[:i], c.data[i+1:]...)
+    c.data = c.data[:i+copy(c.data[i:], c.data[i+1:])]
+    c.world.CRemoved(e, c, c.wkey)
+    
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentRemoved,
+        ComponentName: "BehaviorTreeComponent",
+        ComponentID: "EFC95B83-F063-48B6-AD29-1D476AA7E434",
+        Entity: e,
+    })
+}
+
+func (c *BehaviorTreeComponent) Data(e ecs.Entity) *BehaviorTree {
+    
+    
+    index := c.indexof(e)
+    if index > -1 {
+        return &c.data[index].Data
+    }
+    return nil
+}
+
+// Flag returns the 
+func (c *BehaviorTreeComponent) Flag() ecs.Flag {
+    return c.flag
+}
+
+// Setup is called by ecs.BaseWorld
+//
+// Do not call this directly
+func (c *BehaviorTreeComponent) Setup(w ecs.BaseWorld, f ecs.Flag, key [4]byte) {
+    if c.initialized {
+        panic("BehaviorTreeComponent called Setup() more than once")
+    }
+    c.flag = f
+    c.world = w
+    c.wkey = key
+    c.data = make([]drawerBehaviorTreeComponent, 0, 256)
+    c.initialized = true
+    
+}
+
+
+func init() {
+    ecs.RegisterComponent(func() ecs.BaseComponent {
+        return &BehaviorTreeComponent{}
+    })
+}
@@ -0,0 +1,59 @@
+package bt
+
+// Blackboard is the per entity memory shared by the nodes of a tree
+type Blackboard map[string]interface{}
+
+// Get returns a value (or nil)
+func (b Blackboard) Get(key string) interface{} {
+	return b[key]
+}
+
+// Set sets a value
+func (b Blackboard) Set(key string, value interface{}) {
+	b[key] = value
+}
+
+// Has returns true if the key is set
+func (b Blackboard) Has(key string) bool {
+	_, ok := b[key]
+	return ok
+}
+
+// Delete removes a value
+func (b Blackboard) Delete(key string) {
+	delete(b, key)
+}
+
+// Bool returns a bool value (false if the key is not set or is not a bool)
+func (b Blackboard) Bool(key string) bool {
+	v, _ := b[key].(bool)
+	return v
+}
+
+// Int returns an int value (0 if the key is not set or is not an int)
+func (b Blackboard) Int(key string) int {
+	v, _ := b[key].(int)
+	return v
+}
+
+// Float returns a float64 value (0 if the key is not set or is not a number)
+func (b Blackboard) Float(key string) float64 {
+	switch v := b[key].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// String returns a string value ("" if the key is not set or is not a
+// string)
+func (b Blackboard) String(key string) string {
+	v, _ := b[key].(string)
+	return v
+}
@@ -0,0 +1,427 @@
+// Package bt implements behavior trees.
+//
+// A Tree is an immutable definition (built in Go or loaded from XML) that
+// can be shared by many entities. The per entity state (the running nodes,
+// the decorator timers and the Blackboard) is stored in the BehaviorTree
+// component.
+package bt
+
+import (
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/dom"
+)
+
+// Status is the result of a node tick
+type Status int
+
+// Node statuses
+const (
+	Invalid Status = iota
+	Success
+	Failure
+	Running
+)
+
+func (s Status) String() string {
+	switch s {
+	case Success:
+		return "success"
+	case Failure:
+		return "failure"
+	case Running:
+		return "running"
+	}
+	return "invalid"
+}
+
+// Ctx is passed to the actions and conditions
+type Ctx struct {
+	Ctx        core.UpdateCtx
+	World      ecs.BaseWorld
+	Entity     ecs.Entity
+	Blackboard Blackboard
+	DT         float64
+	// Params are the attributes of the current leaf (when the tree is
+	// loaded from XML)
+	Params dom.Attributes
+	b      *BehaviorTree
+	depth  int
+}
+
+// ActionFn is the function of an action leaf
+type ActionFn func(ctx *Ctx) Status
+
+// ConditionFn is the function of a condition leaf
+type ConditionFn func(ctx *Ctx) bool
+
+// ParallelPolicy defines when a parallel node ends
+type ParallelPolicy int
+
+const (
+	// RequireAll succeeds when all the children succeed and fails when one
+	// of them fails
+	RequireAll ParallelPolicy = iota
+	// RequireOne succeeds when one of the children succeeds and fails when
+	// all of them fail
+	RequireOne
+)
+
+// Node is a node of a behavior tree
+type Node interface {
+	// Kind is the type of the node (selector, sequence, action, ...)
+	Kind() string
+	// Label is the name of the node (or the kind if it's not named)
+	Label() string
+	Children() []Node
+	base() *nodeBase
+	tick(ctx *Ctx, mem *memory) Status
+}
+
+// memory is the per entity state of a node
+type memory struct {
+	index    int
+	count    int
+	elapsed  float64
+	statuses []Status
+	// until is the end of a cooldown (in tree time); it survives resets
+	until float64
+	// ticked is the last tree tick that ran the node
+	ticked uint64
+}
+
+type nodeBase struct {
+	id       int
+	size     int
+	kind     string
+	name     string
+	params   dom.Attributes
+	children []Node
+}
+
+func (n *nodeBase) Kind() string {
+	return n.kind
+}
+
+func (n *nodeBase) Label() string {
+	if n.name != "" {
+		return n.name
+	}
+	return n.kind
+}
+
+func (n *nodeBase) Children() []Node {
+	return n.children
+}
+
+func (n *nodeBase) base() *nodeBase {
+	return n
+}
+
+// Named sets the name of a node (used by the debug view)
+func Named(name string, n Node) Node {
+	n.base().name = name
+	return n
+}
+
+type actionNode struct {
+	nodeBase
+	fn ActionFn
+}
+
+// Action creates an action leaf
+func Action(name string, fn ActionFn) Node {
+	return &actionNode{
+		nodeBase: nodeBase{kind: "action", name: name},
+		fn:       fn,
+	}
+}
+
+func (n *actionNode) tick(ctx *Ctx, mem *memory) Status {
+	ctx.Params = n.params
+	st := n.fn(ctx)
+	ctx.Params = nil
+	return st
+}
+
+type conditionNode struct {
+	nodeBase
+	fn ConditionFn
+}
+
+// Condition creates a condition leaf. It succeeds if fn returns true.
+func Condition(name string, fn ConditionFn) Node {
+	return &conditionNode{
+		nodeBase: nodeBase{kind: "condition", name: name},
+		fn:       fn,
+	}
+}
+
+func (n *conditionNode) tick(ctx *Ctx, mem *memory) Status {
+	ctx.Params = n.params
+	ok := n.fn(ctx)
+	ctx.Params = nil
+	if ok {
+		return Success
+	}
+	return Failure
+}
+
+type selectorNode struct {
+	nodeBase
+}
+
+// Selector ticks the children in order until one of them succeeds (or is
+// running). It fails if all the children fail. A running child is resumed
+// on the next tick.
+func Selector(children ...Node) Node {
+	return &selectorNode{
+		nodeBase: nodeBase{kind: "selector", children: children},
+	}
+}
+
+func (n *selectorNode) tick(ctx *Ctx, mem *memory) Status {
+	for mem.index < len(n.children) {
+		st := ctx.b.tickNode(ctx, n.children[mem.index])
+		switch st {
+		case Running:
+			return Running
+		case Success:
+			mem.index = 0
+			return Success
+		}
+		mem.index++
+	}
+	mem.index = 0
+	return Failure
+}
+
+type sequenceNode struct {
+	nodeBase
+}
+
+// Sequence ticks the children in order until one of them fails (or is
+// running). It succeeds if all the children succeed. A running child is
+// resumed on the next tick.
+func Sequence(children ...Node) Node {
+	return &sequenceNode{
+		nodeBase: nodeBase{kind: "sequence", children: children},
+	}
+}
+
+func (n *sequenceNode) tick(ctx *Ctx, mem *memory) Status {
+	for mem.index < len(n.children) {
+		st := ctx.b.tickNode(ctx, n.children[mem.index])
+		switch st {
+		case Running:
+			return Running
+		case Failure:
+			mem.index = 0
+			return Failure
+		}
+		mem.index++
+	}
+	mem.index = 0
+	return Success
+}
+
+type parallelNode struct {
+	nodeBase
+	policy ParallelPolicy
+}
+
+// Parallel ticks all the children on every tick (see ParallelPolicy). The
+// children that are still running are reset when the node ends.
+func Parallel(policy ParallelPolicy, children ...Node) Node {
+	return &parallelNode{
+		nodeBase: nodeBase{kind: "parallel", children: children},
+		policy:   policy,
+	}
+}
+
+func (n *parallelNode) tick(ctx *Ctx, mem *memory) Status {
+	if len(mem.statuses) != len(n.children) {
+		mem.statuses = make([]Status, len(n.children))
+	}
+	succeeded, failed := 0, 0
+	for i, child := range n.children {
+		if mem.statuses[i] == Invalid || mem.statuses[i] == Running {
+			mem.statuses[i] = ctx.b.tickNode(ctx, child)
+		}
+		switch mem.statuses[i] {
+		case Success:
+			succeeded++
+		case Failure:
+			failed++
+		}
+	}
+	result := Running
+	switch n.policy {
+	case RequireAll:
+		if failed > 0 {
+			result = Failure
+		} else if succeeded == len(n.children) {
+			result = Success
+		}
+	case RequireOne:
+		if succeeded > 0 {
+			result = Success
+		} else if failed == len(n.children) {
+			result = Failure
+		}
+	}
+	if result != Running {
+		ctx.b.resetChildren(n)
+		for i := range mem.statuses {
+			mem.statuses[i] = Invalid
+		}
+	}
+	return result
+}
+
+type inverterNode struct {
+	nodeBase
+}
+
+// Inverter swaps the Success and Failure results of the child
+func Inverter(child Node) Node {
+	return &inverterNode{
+		nodeBase: nodeBase{kind: "inverter", children: []Node{child}},
+	}
+}
+
+func (n *inverterNode) tick(ctx *Ctx, mem *memory) Status {
+	switch st := ctx.b.tickNode(ctx, n.children[0]); st {
+	case Success:
+		return Failure
+	case Failure:
+		return Success
+	default:
+		return st
+	}
+}
+
+type repeatNode struct {
+	nodeBase
+	times int
+}
+
+// Repeat runs the child n times (one run per tick at most). It fails if the
+// child fails. If n <= 0 it repeats forever.
+func Repeat(n int, child Node) Node {
+	return &repeatNode{
+		nodeBase: nodeBase{kind: "repeat", children: []Node{child}},
+		times:    n,
+	}
+}
+
+func (n *repeatNode) tick(ctx *Ctx, mem *memory) Status {
+	switch ctx.b.tickNode(ctx, n.children[0]) {
+	case Running:
+		return Running
+	case Failure:
+		mem.count = 0
+		return Failure
+	}
+	mem.count++
+	if n.times > 0 && mem.count >= n.times {
+		mem.count = 0
+		return Success
+	}
+	return Running
+}
+
+type cooldownNode struct {
+	nodeBase
+	seconds float64
+}
+
+// Cooldown fails (without ticking the child) for d seconds after the child
+// ends
+func Cooldown(d float64, child Node) Node {
+	return &cooldownNode{
+		nodeBase: nodeBase{kind: "cooldown", children: []Node{child}},
+		seconds:  d,
+	}
+}
+
+func (n *cooldownNode) tick(ctx *Ctx, mem *memory) Status {
+	if ctx.b.time < mem.until {
+		return Failure
+	}
+	st := ctx.b.tickNode(ctx, n.children[0])
+	if st == Success || st == Failure {
+		mem.until = ctx.b.time + n.seconds
+	}
+	return st
+}
+
+type timeoutNode struct {
+	nodeBase
+	seconds float64
+}
+
+// Timeout fails (and resets the child) if the child is running for more
+// than d seconds
+func Timeout(d float64, child Node) Node {
+	return &timeoutNode{
+		nodeBase: nodeBase{kind: "timeout", children: []Node{child}},
+		seconds:  d,
+	}
+}
+
+func (n *timeoutNode) tick(ctx *Ctx, mem *memory) Status {
+	mem.elapsed += ctx.DT
+	if mem.elapsed > n.seconds {
+		mem.elapsed = 0
+		ctx.b.resetChildren(n)
+		return Failure
+	}
+	st := ctx.b.tickNode(ctx, n.children[0])
+	if st != Running {
+		mem.elapsed = 0
+	}
+	return st
+}
+
+// Tree is a behavior tree definition
+type Tree struct {
+	name  string
+	root  Node
+	nodes []Node
+}
+
+// NewTree creates a tree. The nodes can't be shared with other trees.
+func NewTree(name string, root Node) *Tree {
+	t := &Tree{
+		name: name,
+		root: root,
+	}
+	t.index(root)
+	return t
+}
+
+func (t *Tree) index(n Node) {
+	b := n.base()
+	b.id = len(t.nodes)
+	t.nodes = append(t.nodes, n)
+	for _, c := range b.children {
+		t.index(c)
+	}
+	b.size = len(t.nodes) - b.id
+}
+
+// Name returns the name of the tree
+func (t *Tree) Name() string {
+	return t.name
+}
+
+// Root returns the root node
+func (t *Tree) Root() Node {
+	return t.root
+}
+
+// Len returns the number of nodes
+func (t *Tree) Len() int {
+	return len(t.nodes)
+}
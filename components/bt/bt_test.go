@@ -0,0 +1,126 @@
+package bt
+
+import (
+	"testing"
+
+	"github.com/gabstv/primen/dom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDOM(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterCondition("sees_player", func(ctx *Ctx) bool {
+		return ctx.Blackboard.Bool("sees")
+	})
+	chased := ""
+	reg.RegisterAction("chase", func(ctx *Ctx) Status {
+		chased = ctx.Params.String("speed")
+		return Running
+	})
+	reg.RegisterAction("idle", func(ctx *Ctx) Status {
+		return Success
+	})
+	nodes, err := dom.ParseXMLString(`<ai>
+	<behaviortree name="guard">
+		<selector name="root">
+			<sequence>
+				<condition name="sees_player" />
+				<action name="chase" speed="fast" />
+			</sequence>
+			<cooldown seconds="1"><idle /></cooldown>
+		</selector>
+	</behaviortree>
+</ai>`)
+	assert.NoError(t, err)
+	trees, err := ParseDOM(nodes, reg)
+	assert.NoError(t, err)
+	tree := trees["guard"]
+	assert.NotNil(t, tree)
+	assert.Equal(t, 6, tree.Len())
+
+	b := New(tree)
+	assert.Equal(t, Success, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Failure, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Success, b.Tick(nil, nil, 0, 0.5))
+
+	b.Blackboard().Set("sees", true)
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, "fast", chased)
+	path := b.ActivePath()
+	assert.Equal(t, 4, len(path))
+	assert.Equal(t, "root", path[0].Node.Label())
+	assert.Equal(t, "chase", path[3].Node.Label())
+	assert.Equal(t, 2, path[3].Depth)
+	assert.Equal(t, Running, path[3].Status)
+
+	_, err = ParseDOM([]dom.Node{dom.Element("behaviortree", map[string]string{"name": "x"},
+		dom.Element("sequence", nil, dom.Element("dance", nil)))}, reg)
+	assert.Error(t, err)
+}
+
+func TestDecorators(t *testing.T) {
+	n := 0
+	b := New(NewTree("repeat", Repeat(3, Action("count", func(ctx *Ctx) Status {
+		n++
+		return Success
+	}))))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Success, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, 3, n)
+
+	b = New(NewTree("timeout", Timeout(1, Action("forever", func(ctx *Ctx) Status {
+		return Running
+	}))))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Failure, b.Tick(nil, nil, 0, 0.5))
+
+	ticks := 0
+	b = New(NewTree("parallel", Parallel(RequireAll,
+		Action("twice", func(ctx *Ctx) Status {
+			ticks++
+			if ticks < 2 {
+				return Running
+			}
+			return Success
+		}),
+		Inverter(Condition("false", func(ctx *Ctx) bool {
+			return false
+		})))))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Success, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, 2, ticks)
+}
+
+// gateNode ticks the child only while open (like a reactive parent that
+// abandons a running branch without resetting it)
+type gateNode struct {
+	nodeBase
+	open *bool
+}
+
+func (n *gateNode) tick(ctx *Ctx, mem *memory) Status {
+	if !*n.open {
+		return Failure
+	}
+	return ctx.b.tickNode(ctx, n.children[0])
+}
+
+func TestTimeoutReentry(t *testing.T) {
+	open := true
+	b := New(NewTree("gate", &gateNode{
+		nodeBase: nodeBase{kind: "gate", children: []Node{Timeout(1, Action("forever", func(ctx *Ctx) Status {
+			return Running
+		}))}},
+		open: &open,
+	}))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	open = false
+	assert.Equal(t, Failure, b.Tick(nil, nil, 0, 0.5))
+	open = true
+	// the abandoned run doesn't count
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Running, b.Tick(nil, nil, 0, 0.5))
+	assert.Equal(t, Failure, b.Tick(nil, nil, 0, 0.5))
+}
@@ -0,0 +1,246 @@
+package bt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gabstv/primen/dom"
+	"github.com/gabstv/primen/io"
+)
+
+// Registry maps the action and condition names used by the XML trees to Go
+// functions
+type Registry struct {
+	l          sync.RWMutex
+	actions    map[string]ActionFn
+	conditions map[string]ConditionFn
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		actions:    make(map[string]ActionFn),
+		conditions: make(map[string]ConditionFn),
+	}
+}
+
+// DefaultRegistry is used when a nil registry is passed to ParseDOM or
+// LoadXML
+var DefaultRegistry = NewRegistry()
+
+// RegisterAction registers an action
+func (r *Registry) RegisterAction(name string, fn ActionFn) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.actions[name] = fn
+}
+
+// RegisterCondition registers a condition
+func (r *Registry) RegisterCondition(name string, fn ConditionFn) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.conditions[name] = fn
+}
+
+func (r *Registry) action(name string) ActionFn {
+	r.l.RLock()
+	defer r.l.RUnlock()
+	return r.actions[name]
+}
+
+func (r *Registry) condition(name string) ConditionFn {
+	r.l.RLock()
+	defer r.l.RUnlock()
+	return r.conditions[name]
+}
+
+// RegisterAction registers an action on the DefaultRegistry
+func RegisterAction(name string, fn ActionFn) {
+	DefaultRegistry.RegisterAction(name, fn)
+}
+
+// RegisterCondition registers a condition on the DefaultRegistry
+func RegisterCondition(name string, fn ConditionFn) {
+	DefaultRegistry.RegisterCondition(name, fn)
+}
+
+// LoadXML loads the trees of a XML file of a container (see ParseDOM)
+func LoadXML(c io.Container, name string, reg *Registry) (map[string]*Tree, error) {
+	nodes, err := c.GetXMLDOM(name)
+	if err != nil {
+		return nil, err
+	}
+	trees, err := ParseDOM(nodes, reg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return trees, nil
+}
+
+// ParseDOM parses the <behaviortree name="..."> elements of a document:
+//
+//	<behaviortree name="guard">
+//	  <selector>
+//	    <sequence>
+//	      <condition name="sees_player" />
+//	      <timeout seconds="5"><action name="chase" /></timeout>
+//	    </sequence>
+//	    <cooldown seconds="2"><action name="look_around" /></cooldown>
+//	    <parallel policy="one">
+//	      <inverter><condition name="at_home" /></inverter>
+//	      <repeat count="3"><action name="walk" to="home" /></repeat>
+//	    </parallel>
+//	  </selector>
+//	</behaviortree>
+//
+// The attributes of the leaves are available at Ctx.Params. Registered
+// actions and conditions can also be used as tags (<chase />).
+func ParseDOM(nodes []dom.Node, reg *Registry) (map[string]*Tree, error) {
+	if reg == nil {
+		reg = DefaultRegistry
+	}
+	trees := make(map[string]*Tree)
+	if err := parseTrees(nodes, reg, trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+func parseTrees(nodes []dom.Node, reg *Registry, trees map[string]*Tree) error {
+	for _, n := range nodes {
+		el, ok := n.(dom.ElementNode)
+		if !ok {
+			continue
+		}
+		if el.TagName() != "behaviortree" {
+			if err := parseTrees(el.Children(), reg, trees); err != nil {
+				return err
+			}
+			continue
+		}
+		name := el.Attributes().FirstAttr("name", "id")
+		if name == "" {
+			return fmt.Errorf("behaviortree without a name")
+		}
+		if _, ok := trees[name]; ok {
+			return fmt.Errorf("behaviortree %s: duplicated name", name)
+		}
+		children := elements(el.Children())
+		if len(children) != 1 {
+			return fmt.Errorf("behaviortree %s: must have one root node", name)
+		}
+		root, err := parseNode(children[0], reg)
+		if err != nil {
+			return fmt.Errorf("behaviortree %s: %w", name, err)
+		}
+		trees[name] = NewTree(name, root)
+	}
+	return nil
+}
+
+func parseNode(el dom.ElementNode, reg *Registry) (Node, error) {
+	attrs := el.Attributes()
+	children := make([]Node, 0)
+	for _, c := range elements(el.Children()) {
+		n, err := parseNode(c, reg)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+	one := func() (Node, error) {
+		if len(children) != 1 {
+			return nil, fmt.Errorf("<%s> must have one child", el.TagName())
+		}
+		return children[0], nil
+	}
+	var n Node
+	switch el.TagName() {
+	case "selector", "fallback":
+		n = Selector(children...)
+	case "sequence":
+		n = Sequence(children...)
+	case "parallel":
+		policy := RequireAll
+		switch attrs.String("policy") {
+		case "", "all":
+		case "one":
+			policy = RequireOne
+		default:
+			return nil, fmt.Errorf("<parallel> invalid policy %q", attrs.String("policy"))
+		}
+		n = Parallel(policy, children...)
+	case "inverter", "not":
+		c, err := one()
+		if err != nil {
+			return nil, err
+		}
+		n = Inverter(c)
+	case "repeat":
+		c, err := one()
+		if err != nil {
+			return nil, err
+		}
+		n = Repeat(attrs.IntD("count", 0), c)
+	case "cooldown":
+		c, err := one()
+		if err != nil {
+			return nil, err
+		}
+		n = Cooldown(attrs.FloatD("seconds", 0), c)
+	case "timeout":
+		c, err := one()
+		if err != nil {
+			return nil, err
+		}
+		n = Timeout(attrs.FloatD("seconds", 0), c)
+	case "action":
+		return leaf(reg, attrs.String("name"), attrs, true)
+	case "condition":
+		return leaf(reg, attrs.String("name"), attrs, false)
+	default:
+		if len(children) > 0 {
+			return nil, fmt.Errorf("unknown node <%s>", el.TagName())
+		}
+		if reg.action(el.TagName()) != nil {
+			return leaf(reg, el.TagName(), attrs, true)
+		}
+		if reg.condition(el.TagName()) != nil {
+			return leaf(reg, el.TagName(), attrs, false)
+		}
+		return nil, fmt.Errorf("unknown node <%s>", el.TagName())
+	}
+	if label := attrs.String("name"); label != "" {
+		Named(label, n)
+	}
+	return n, nil
+}
+
+func leaf(reg *Registry, name string, attrs dom.Attributes, action bool) (Node, error) {
+	var n Node
+	if action {
+		fn := reg.action(name)
+		if fn == nil {
+			return nil, fmt.Errorf("action %q is not registered", name)
+		}
+		n = Action(name, fn)
+	} else {
+		fn := reg.condition(name)
+		if fn == nil {
+			return nil, fmt.Errorf("condition %q is not registered", name)
+		}
+		n = Condition(name, fn)
+	}
+	n.base().params = attrs
+	return n, nil
+}
+
+func elements(nodes []dom.Node) []dom.ElementNode {
+	out := make([]dom.ElementNode, 0, len(nodes))
+	for _, n := range nodes {
+		if el, ok := n.(dom.ElementNode); ok {
+			out = append(out, el)
+		}
+	}
+	return out
+}
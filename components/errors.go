@@ -0,0 +1,17 @@
+package components
+
+// Error is a components error
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrTransformNotFound is returned when the parent entity doesn't have
+	// a Transform
+	ErrTransformNotFound Error = "transform not found"
+	// ErrCyclicTransform is returned when a transform would become an
+	// ancestor of itself
+	ErrCyclicTransform Error = "cyclic transform parenting"
+)
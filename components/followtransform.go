@@ -108,6 +108,9 @@ func (s *FollowTransformSystem) Update(ctx core.UpdateCtx) {
 	dt := ctx.DT()
 
 	for _, v := range s.V().Matches() {
+		if !v.Transform.ActiveInHierarchy() {
+			continue
+		}
 		targetTr := GetTransformComponentData(s.world, v.FollowTransform.targetE)
 		if targetTr == nil {
 			continue
@@ -0,0 +1,417 @@
+// Package fsm implements a finite state machine component.
+//
+// A Definition declares the states (with OnEnter/OnUpdate/OnExit hooks and
+// an optional sprite animation clip) and the transitions between them.
+// Transitions are triggered by events (StateMachine.Trigger or engine
+// events) and/or guarded by conditions. The same Definition can be used by
+// many entities.
+package fsm
+
+import (
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/components/graphics"
+	"github.com/gabstv/primen/core"
+)
+
+// AnyState matches every state on Transition.From
+const AnyState = "*"
+
+// DefaultHistorySize is the number of state changes kept by a StateMachine
+const DefaultHistorySize = 32
+
+// Ctx is passed to the hooks and guards
+type Ctx struct {
+	Ctx     core.UpdateCtx
+	World   ecs.BaseWorld
+	Entity  ecs.Entity
+	Machine *StateMachine
+	// From and To are set on OnEnter, OnExit and on guards
+	From string
+	To   string
+	// Event is the event that caused the transition (if any)
+	Event string
+	Data  interface{}
+	// StateTime is the time (of world time) spent on the current state
+	StateTime float64
+}
+
+// HookFn is a state hook
+type HookFn func(ctx *Ctx)
+
+// GuardFn returns true if a transition can happen
+type GuardFn func(ctx *Ctx) bool
+
+// State is a state of a Definition
+type State struct {
+	Name string
+	// Clip is the sprite animation clip played (on the same entity) when
+	// the state is entered
+	Clip     string
+	OnEnter  HookFn
+	OnUpdate HookFn
+	OnExit   HookFn
+}
+
+// Transition is an edge between two states
+type Transition struct {
+	// From is the source state (or AnyState)
+	From string
+	To   string
+	// Event triggers the transition. Transitions without an event are
+	// checked every frame.
+	Event string
+	// EngineEvent is true if Event is an engine event (it's dispatched
+	// with Engine.DispatchEvent instead of StateMachine.Trigger)
+	EngineEvent bool
+	// When is an optional guard
+	When GuardFn
+}
+
+// Definition is a set of states and transitions
+type Definition struct {
+	initial     string
+	states      map[string]*State
+	transitions []Transition
+}
+
+// NewDefinition creates a definition that starts on the initial state
+func NewDefinition(initial string) *Definition {
+	return &Definition{
+		initial: initial,
+		states:  make(map[string]*State),
+	}
+}
+
+// Initial returns the initial state
+func (d *Definition) Initial() string {
+	return d.initial
+}
+
+// AddState adds (or replaces) a state
+func (d *Definition) AddState(s State) *Definition {
+	v := s
+	d.states[s.Name] = &v
+	return d
+}
+
+// State returns a state by name (or nil)
+func (d *Definition) State(name string) *State {
+	return d.states[name]
+}
+
+// AddTransition adds a transition. Transitions are checked in the order
+// they are added.
+func (d *Definition) AddTransition(t Transition) *Definition {
+	d.transitions = append(d.transitions, t)
+	return d
+}
+
+// On adds a transition triggered by StateMachine.Trigger
+func (d *Definition) On(from, event, to string) *Definition {
+	return d.AddTransition(Transition{From: from, To: to, Event: event})
+}
+
+// OnEngineEvent adds a transition triggered by an engine event
+func (d *Definition) OnEngineEvent(from, event, to string) *Definition {
+	return d.AddTransition(Transition{From: from, To: to, Event: event, EngineEvent: true})
+}
+
+// When adds a transition that happens when cond returns true
+func (d *Definition) When(from, to string, cond GuardFn) *Definition {
+	return d.AddTransition(Transition{From: from, To: to, When: cond})
+}
+
+// HistoryEntry is a state change
+type HistoryEntry struct {
+	From  string
+	To    string
+	Event string
+	// Frame is the engine update frame of the change
+	Frame int64
+}
+
+type trigger struct {
+	event  string
+	data   interface{}
+	engine bool
+	force  string
+}
+
+// eventQueue is shared by the copies of the component data (and by the
+// engine event listeners)
+type eventQueue struct {
+	list []trigger
+}
+
+// StateMachine is the component data of an entity state machine
+type StateMachine struct {
+	def         *Definition
+	current     string
+	started     bool
+	stateTime   float64
+	history     []HistoryEntry
+	historySize int
+	queue       *eventQueue
+}
+
+// New returns a new StateMachine component data
+func New(def *Definition) StateMachine {
+	return StateMachine{
+		def:         def,
+		historySize: DefaultHistorySize,
+		queue:       &eventQueue{},
+	}
+}
+
+//go:generate ecsgen -n StateMachine -p fsm -o statemachine_component.go --component-tpl --vars "UUID=5EDFA92C-A20F-4E5C-95A7-0B2A346E6C6F"
+
+//go:generate ecsgen -n StateMachine -p fsm -o statemachine_system.go --system-tpl --vars "EntityRemoved=s.onEntityRemoved(e)" --vars "Setup=s.setupStateMachines()" --vars "Priority=20" --vars "UUID=430C22E1-2F8F-4E58-8955-5FAE30A21481" --components "StateMachine" --members "listeners=map[ecs.Entity]*eventQueue"
+
+// Definition returns the definition of the machine
+func (m *StateMachine) Definition() *Definition {
+	return m.def
+}
+
+// Current returns the name of the current state ("" if it didn't start)
+func (m *StateMachine) Current() string {
+	return m.current
+}
+
+// StateTime returns the time (of world time) spent on the current state
+func (m *StateMachine) StateTime() float64 {
+	return m.stateTime
+}
+
+// Trigger queues an event. The events are processed (in order) on the next
+// update of the StateMachineSystem.
+func (m *StateMachine) Trigger(event string, data interface{}) {
+	m.queue.list = append(m.queue.list, trigger{event: event, data: data})
+}
+
+// SetState queues a forced state change (the transitions and guards are
+// ignored)
+func (m *StateMachine) SetState(name string) {
+	m.queue.list = append(m.queue.list, trigger{force: name})
+}
+
+// History returns the last state changes (the oldest first)
+func (m *StateMachine) History() []HistoryEntry {
+	out := make([]HistoryEntry, len(m.history))
+	copy(out, m.history)
+	return out
+}
+
+// SetHistorySize sets how many state changes are kept (0 disables the
+// history)
+func (m *StateMachine) SetHistorySize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	m.historySize = n
+	if len(m.history) > n {
+		m.history = append([]HistoryEntry(nil), m.history[len(m.history)-n:]...)
+	}
+}
+
+func (m *StateMachine) record(h HistoryEntry) {
+	if m.historySize == 0 {
+		return
+	}
+	if len(m.history) >= m.historySize {
+		copy(m.history, m.history[1:])
+		m.history = m.history[:len(m.history)-1]
+	}
+	m.history = append(m.history, h)
+}
+
+// changeState exits the current state and enters the next one
+func (m *StateMachine) changeState(ctx *Ctx, to, event string, data interface{}) {
+	from := m.current
+	ctx.From = from
+	ctx.To = to
+	ctx.Event = event
+	ctx.Data = data
+	if st := m.def.states[from]; st != nil && m.started && st.OnExit != nil {
+		st.OnExit(ctx)
+	}
+	m.current = to
+	m.started = true
+	m.stateTime = 0
+	ctx.StateTime = 0
+	var frame int64
+	if ctx.Ctx != nil {
+		frame = ctx.Ctx.Frame()
+	}
+	m.record(HistoryEntry{
+		From:  from,
+		To:    to,
+		Event: event,
+		Frame: frame,
+	})
+	st := m.def.states[to]
+	if st == nil {
+		return
+	}
+	if st.Clip != "" {
+		if anim := graphics.GetSpriteAnimationComponentData(ctx.World, ctx.Entity); anim != nil {
+			anim.PlayClip(st.Clip)
+		}
+	}
+	if st.OnEnter != nil {
+		st.OnEnter(ctx)
+	}
+}
+
+// findTransition returns the first transition that matches the event (""
+// for the guard-only transitions) and passes the guard
+func (m *StateMachine) findTransition(ctx *Ctx, event string, engine bool, data interface{}) (Transition, bool) {
+	for _, t := range m.def.transitions {
+		if t.From != AnyState && t.From != m.current {
+			continue
+		}
+		if t.Event != event || (event != "" && t.EngineEvent != engine) {
+			continue
+		}
+		if t.From == AnyState && t.To == m.current && event == "" {
+			// don't re-enter the same state every frame
+			continue
+		}
+		if t.When != nil {
+			ctx.From = m.current
+			ctx.To = t.To
+			ctx.Event = event
+			ctx.Data = data
+			if !t.When(ctx) {
+				continue
+			}
+		}
+		return t, true
+	}
+	return Transition{}, false
+}
+
+// engineEvents returns the engine events used by the transitions
+func (d *Definition) engineEvents() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, t := range d.transitions {
+		if t.EngineEvent && t.Event != "" && !seen[t.Event] {
+			seen[t.Event] = true
+			names = append(names, t.Event)
+		}
+	}
+	return names
+}
+
+var matchStateMachineSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetStateMachineComponent(w).Flag())
+}
+
+var resizematchStateMachineSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetStateMachineComponent(w).Flag())
+}
+
+func (s *StateMachineSystem) setupStateMachines() {
+	s.listeners = make(map[ecs.Entity]*eventQueue)
+}
+
+func (s *StateMachineSystem) onEntityRemoved(e ecs.Entity) {
+	q, ok := s.listeners[e]
+	if !ok {
+		return
+	}
+	delete(s.listeners, e)
+	if w, ok := s.world.(core.World); ok {
+		w.Engine().RemoveEventListenersByOwner(q)
+	}
+}
+
+// DrawPriority noop
+func (s *StateMachineSystem) DrawPriority(ctx core.DrawCtx) {}
+
+// Draw noop
+func (s *StateMachineSystem) Draw(ctx core.DrawCtx) {}
+
+// UpdatePriority noop
+func (s *StateMachineSystem) UpdatePriority(ctx core.UpdateCtx) {}
+
+// Update starts the new machines, processes the queued events, checks the
+// guarded transitions and calls OnUpdate of the current states.
+func (s *StateMachineSystem) Update(ctx core.UpdateCtx) {
+	dt := ctx.DT()
+	for _, v := range s.V().Matches() {
+		m := v.StateMachine
+		if m.def == nil || !components.IsActive(s.world, v.Entity) {
+			continue
+		}
+		if m.queue == nil {
+			m.queue = &eventQueue{}
+		}
+		hctx := &Ctx{
+			Ctx:       ctx,
+			World:     s.world,
+			Entity:    v.Entity,
+			Machine:   m,
+			StateTime: m.stateTime,
+		}
+		if !m.started {
+			s.listen(ctx.Engine(), v.Entity, m)
+			m.changeState(hctx, m.def.initial, "", nil)
+		}
+		queue := m.queue.list
+		m.queue.list = nil
+		for _, tr := range queue {
+			if tr.force != "" {
+				m.changeState(hctx, tr.force, "", nil)
+				continue
+			}
+			if t, ok := m.findTransition(hctx, tr.event, tr.engine, tr.data); ok {
+				m.changeState(hctx, t.To, tr.event, tr.data)
+			}
+		}
+		// one guarded transition per frame (prevents infinite loops)
+		if t, ok := m.findTransition(hctx, "", false, nil); ok {
+			m.changeState(hctx, t.To, "", nil)
+		}
+		m.stateTime += dt
+		hctx.StateTime = m.stateTime
+		if st := m.def.states[m.current]; st != nil && st.OnUpdate != nil {
+			hctx.From, hctx.To, hctx.Event, hctx.Data = "", "", "", nil
+			st.OnUpdate(hctx)
+		}
+	}
+}
+
+// listen registers the engine event listeners of a machine
+func (s *StateMachineSystem) listen(engine core.Engine, e ecs.Entity, m *StateMachine) {
+	events := m.def.engineEvents()
+	if len(events) == 0 || engine == nil {
+		return
+	}
+	q := m.queue
+	if old, ok := s.listeners[e]; ok {
+		engine.RemoveEventListenersByOwner(old)
+	}
+	s.listeners[e] = q
+	for _, name := range events {
+		engine.AddEventListenerWithOptions(name, func(eventName string, ev core.Event) {
+			q.list = append(q.list, trigger{event: eventName, data: ev.Data, engine: true})
+		}, core.ListenerOptions{
+			Owner: q,
+			// the queue is read by the system (main thread)
+			MainThread: true,
+		})
+	}
+}
+
+// Trigger queues an event on the state machine of an entity. It returns
+// false if the entity doesn't have a StateMachine.
+func Trigger(w ecs.BaseWorld, e ecs.Entity, event string, data interface{}) bool {
+	m := GetStateMachineComponentData(w, e)
+	if m == nil {
+		return false
+	}
+	m.Trigger(event, data)
+	return true
+}
@@ -0,0 +1,227 @@
+// Code generated by ecs https://github.com/gabstv/ecs; DO NOT EDIT.
+
+package fsm
+
+import (
+    "sort"
+    
+
+    "github.com/gabstv/ecs/v2"
+)
+
+
+
+
+
+
+
+
+const uuidStateMachineComponent = "5EDFA92C-A20F-4E5C-95A7-0B2A346E6C6F"
+const capStateMachineComponent = 256
+
+type drawerStateMachineComponent struct {
+    Entity ecs.Entity
+    Data   StateMachine
+}
+
+// WatchStateMachine is a helper struct to access a valid pointer of StateMachine
+type WatchStateMachine interface {
+    Entity() ecs.Entity
+    Data() *StateMachine
+}
+
+type slcdrawerStateMachineComponent []drawerStateMachineComponent
+func (a slcdrawerStateMachineComponent) Len() int           { return len(a) }
+func (a slcdrawerStateMachineComponent) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a slcdrawerStateMachineComponent) Less(i, j int) bool { return a[i].Entity < a[j].Entity }
+
+
+type mWatchStateMachine struct {
+    c *StateMachineComponent
+    entity ecs.Entity
+}
+
+func (w *mWatchStateMachine) Entity() ecs.Entity {
+    return w.entity
+}
+
+func (w *mWatchStateMachine) Data() *StateMachine {
+    
+    
+    id := w.c.indexof(w.entity)
+    if id == -1 {
+        return nil
+    }
+    return &w.c.data[id].Data
+}
+
+// StateMachineComponent implements ecs.BaseComponent
+type StateMachineComponent struct {
+    initialized bool
+    flag        ecs.Flag
+    world       ecs.BaseWorld
+    wkey        [4]byte
+    data        []drawerStateMachineComponent
+    
+}
+
+// GetStateMachineComponent returns the instance of the component in a World
+func GetStateMachineComponent(w ecs.BaseWorld) *StateMachineComponent {
+    return w.C(uuidStateMachineComponent).(*StateMachineComponent)
+}
+
+// SetStateMachineComponentData updates/adds a StateMachine to Entity e
+func SetStateMachineComponentData(w ecs.BaseWorld, e ecs.Entity, data StateMachine) {
+    GetStateMachineComponent(w).Upsert(e, data)
+}
+
+// GetStateMachineComponentData gets the *StateMachine of Entity e
+func GetStateMachineComponentData(w ecs.BaseWorld, e ecs.Entity) *StateMachine {
+    return GetStateMachineComponent(w).Data(e)
+}
+
+// WatchStateMachineComponentData gets a pointer getter of an entity's StateMachine.
+//
+// The pointer must not be stored because it may become invalid overtime.
+func WatchStateMachineComponentData(w ecs.BaseWorld, e ecs.Entity) WatchStateMachine {
+    return &mWatchStateMachine{
+        c: GetStateMachineComponent(w),
+        entity: e,
+    }
+}
+
+// UUID implements ecs.BaseComponent
+func (StateMachineComponent) UUID() string {
+    return "5EDFA92C-A20F-4E5C-95A7-0B2A346E6C6F"
+}
+
+// Name implements ecs.BaseComponent
+func (StateMachineComponent) Name() string {
+    return "StateMachineComponent"
+}
+
+func (c *StateMachineComponent) indexof(e ecs.Entity) int {
+    i := sort.Search(len(c.data), func(i int) bool { return c.data[i].Entity >= e })
+    if i < len(c.data) && c.data[i].Entity == e {
+        return i
+    }
+    return -1
+}
+
+// Upsert creates or updates a component data of an entity.
+// Not recommended to be used directly. Use SetStateMachineComponentData to change component
+// data outside of a system loop.
+func (c *StateMachineComponent) Upsert(e ecs.Entity, data interface{}) {
+    v, ok := data.(StateMachine)
+    if !ok {
+        panic("data must be StateMachine")
+    }
+    
+    id := c.indexof(e)
+    
+    if id > -1 {
+        
+        dwr := &c.data[id]
+        dwr.Data = v
+        
+        return
+    }
+    
+    rsz := false
+    if cap(c.data) == len(c.data) {
+        rsz = true
+        c.world.CWillResize(c, c.wkey)
+        
+    }
+    newindex := len(c.data)
+    c.data = append(c.data, drawerStateMachineComponent{
+        Entity: e,
+        Data:   v,
+    })
+    if len(c.data) > 1 {
+        if c.data[newindex].Entity < c.data[newindex-1].Entity {
+            c.world.CWillResize(c, c.wkey)
+            
+            sort.Sort(slcdrawerStateMachineComponent(c.data))
+            rsz = true
+        }
+    }
+    
+    if rsz {
+        
+        c.world.CResized(c, c.wkey)
+        c.world.Dispatch(ecs.Event{
+            Type: ecs.EvtComponentsResized,
+            ComponentName: "StateMachineComponent",
+            ComponentID: "5EDFA92C-A20F-4E5C-95A7-0B2A346E6C6F",
+        })
+    }
+    
+    c.world.CAdded(e, c, c.wkey)
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentAdded,
+        ComponentName: "StateMachineComponent",
+        ComponentID: "5EDFA92C-A20F-4E5C-95A7-0B2A346E6C6F",
+        Entity: e,
+    })
+}
+
+// Remove a StateMachine data from entity e
+//
+// Warning: DO NOT call remove inside the system entities loop
+func (c *StateMachineComponent) Remove(e ecs.Entity) {
+    
+    
+    i := c.indexof(e)
+    if i == -1 {
+        return
+    }
+    
+    //c.data = append(c.data[:i], c.data[i+1:]...)
+    c.data = c.data[:i+copy(c.data[i:], c.data[i+1:])]
+    c.world.CRemoved(e, c, c.wkey)
+    
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentRemoved,
+        ComponentName: "StateMachineComponent",
+        ComponentID: "5EDFA92C-A20F-4E5C-95A7-0B2A346E6C6F",
+        Entity: e,
+    })
+}
+
+func (c *StateMachineComponent) Data(e ecs.Entity) *StateMachine {
+    
+    
+    index := c.indexof(e)
+    if index > -1 {
+        return &c.data[index].Data
+    }
+    return nil
+}
+
+// Flag returns the 
+func (c *StateMachineComponent) Flag() ecs.Flag {
+    return c.flag
+}
+
+// Setup is called by ecs.BaseWorld
+//
+// Do not call this directly
+func (c *StateMachineComponent) Setup(w ecs.BaseWorld, f ecs.Flag, key [4]byte) {
+    if c.initialized {
+        panic("StateMachineComponent called Setup() more than once")
+    }
+    c.flag = f
+    c.world = w
+    c.wkey = key
+    c.data = make([]drawerStateMachineComponent, 0, 256)
+    c.initialized = true
+    
+}
+
+
+func init() {
+    ecs.RegisterComponent(func() ecs.BaseComponent {
+        return &StateMachineComponent{}
+    })
+}
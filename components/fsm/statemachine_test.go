@@ -0,0 +1,52 @@
+package fsm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateMachine(t *testing.T) {
+	e, w := newTestWorld()
+	var log []string
+	def := NewDefinition("idle").
+		AddState(State{
+			Name:    "idle",
+			OnEnter: func(ctx *Ctx) { log = append(log, "enter idle") },
+			OnExit:  func(ctx *Ctx) { log = append(log, "exit idle") },
+		}).
+		AddState(State{
+			Name:    "run",
+			OnEnter: func(ctx *Ctx) { log = append(log, "enter run:"+ctx.Event) },
+		}).
+		AddState(State{Name: "dead"}).
+		On("idle", "move", "run").
+		When("run", "idle", func(ctx *Ctx) bool { return ctx.StateTime >= 1 }).
+		OnEngineEvent(AnyState, "kill", "dead")
+	ent := w.NewEntity()
+	SetStateMachineComponentData(w, ent, New(def))
+
+	e.step(w, 0.25)
+	m := GetStateMachineComponentData(w, ent)
+	assert.Equal(t, "idle", m.Current())
+	m.Trigger("jump", nil)
+	m.Trigger("move", nil)
+	e.step(w, 0.25)
+	assert.Equal(t, "run", m.Current())
+	e.stepN(w, 4, 0.25)
+	assert.Equal(t, "idle", m.Current())
+	assert.Equal(t, []string{"enter idle", "exit idle", "enter run:move", "enter idle"}, log)
+
+	e.DispatchEvent("kill", nil)
+	e.stepN(w, 2, 0.25)
+	assert.Equal(t, "dead", m.Current())
+	h := m.History()
+	assert.Equal(t, 4, len(h))
+	assert.Equal(t, HistoryEntry{From: "idle", To: "dead", Event: "kill", Frame: h[3].Frame}, h[3])
+
+	// the engine listeners are removed with the entity
+	q := m.queue
+	w.RemoveEntity(ent)
+	assert.Equal(t, 0, e.RemoveEventListenersByOwner(q))
+	e.step(w, 0.25)
+}
@@ -31,7 +31,7 @@ var resizematchFunctionSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
 
 func (s *FunctionSystem) DrawPriority(ctx core.DrawCtx) {
 	for _, v := range s.V().Matches() {
-		if v.Function.DrawPriority != nil {
+		if v.Function.DrawPriority != nil && IsVisible(s.world, v.Entity) {
 			v.Function.DrawPriority(ctx, v.Entity)
 		}
 	}
@@ -39,7 +39,7 @@ func (s *FunctionSystem) DrawPriority(ctx core.DrawCtx) {
 
 func (s *FunctionSystem) Draw(ctx core.DrawCtx) {
 	for _, v := range s.V().Matches() {
-		if v.Function.Draw != nil {
+		if v.Function.Draw != nil && IsVisible(s.world, v.Entity) {
 			v.Function.Draw(ctx, v.Entity)
 		}
 	}
@@ -47,7 +47,7 @@ func (s *FunctionSystem) Draw(ctx core.DrawCtx) {
 
 func (s *FunctionSystem) UpdatePriority(ctx core.UpdateCtx) {
 	for _, v := range s.V().Matches() {
-		if v.Function.UpdatePriority != nil {
+		if v.Function.UpdatePriority != nil && IsActive(s.world, v.Entity) {
 			v.Function.UpdatePriority(ctx, v.Entity)
 		}
 	}
@@ -55,7 +55,7 @@ func (s *FunctionSystem) UpdatePriority(ctx core.UpdateCtx) {
 
 func (s *FunctionSystem) Update(ctx core.UpdateCtx) {
 	for _, v := range s.V().Matches() {
-		if v.Function.Update != nil {
+		if v.Function.Update != nil && IsActive(s.world, v.Entity) {
 			v.Function.Update(ctx, v.Entity)
 		}
 	}
@@ -76,6 +76,9 @@ func (s *SoloDrawableSystem) DrawPriority(ctx core.DrawCtx) {}
 // Draw all solo drawables ordered by entity ID
 func (s *SoloDrawableSystem) Draw(ctx core.DrawCtx) {
 	for _, v := range s.V().Matches() {
+		if !v.Transform.VisibleInHierarchy() {
+			continue
+		}
 		v.Drawable.Draw(ctx, v.Transform)
 	}
 }
@@ -86,6 +89,9 @@ func (s *SoloDrawableSystem) UpdatePriority(ctx core.UpdateCtx) {}
 // Update calls drawable.Update()
 func (s *SoloDrawableSystem) Update(ctx core.UpdateCtx) {
 	for _, v := range s.V().Matches() {
+		if !v.Transform.ActiveInHierarchy() {
+			continue
+		}
 		v.Drawable.Update(ctx, v.Transform)
 	}
 }
@@ -130,6 +136,9 @@ func (s *DrawLayerDrawableSystem) Draw(ctx core.DrawCtx) {
 	for _, l := range s.layers.All() {
 		l.Items.Each(func(key ecs.Entity, value core.SLVal) bool {
 			cache := value.(*drawLayerItemCache)
+			if cache.Transform != nil && !cache.Transform.VisibleInHierarchy() {
+				return true
+			}
 			cache.Drawable.Draw(ctx, cache.Transform)
 			return true
 		})
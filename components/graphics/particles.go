@@ -22,6 +22,12 @@ var (
 	emitterRNG = rand.New(rand.NewSource(1337))
 )
 
+// SeedEmitterRNG resets the random number generator shared by the particle
+// emitters (the emitters without their own generator; see SetRand).
+func SeedEmitterRNG(seed int64) {
+	emitterRNG.Seed(seed)
+}
+
 type ParticleEmitter struct {
 	max             int // max active particles
 	props           ParticleProps
@@ -86,11 +92,23 @@ func (e *ParticleEmitter) SetParentLevel(level uint) *ParticleEmitter {
 	return e
 }
 
+func (e *ParticleEmitter) ParentLevel() uint {
+	return e.parentlevel
+}
+
 func (e *ParticleEmitter) SetLockedParticles(locked bool) *ParticleEmitter {
 	e.lockedparticles = locked
 	return e
 }
 
+func (e *ParticleEmitter) LockedParticles() bool {
+	return e.lockedparticles
+}
+
+func (e *ParticleEmitter) Strategy() SpawnStrategy {
+	return e.strategy
+}
+
 func (e *ParticleEmitter) MaxParticles() int {
 	return e.max
 }
@@ -136,6 +154,13 @@ func (e *ParticleEmitter) CompositeMode() ebiten.CompositeMode {
 	return e.compositeMode
 }
 
+// SetRand sets the random number generator of the emitter (nil uses the
+// shared generator)
+func (e *ParticleEmitter) SetRand(rng *rand.Rand) *ParticleEmitter {
+	e.rand = rng
+	return e
+}
+
 func (e *ParticleEmitter) SetCompositeMode(m ebiten.CompositeMode) *ParticleEmitter {
 	e.compositeMode = m
 	return e
@@ -425,7 +450,7 @@ func (s *ParticleEmitterSystem) UpdatePriority(ctx core.UpdateCtx) {}
 func (s *ParticleEmitterSystem) Update(ctx core.UpdateCtx) {
 	dt := ctx.DT()
 	for _, v := range s.V().Matches() {
-		if v.ParticleEmitter.disabled {
+		if v.ParticleEmitter.disabled || !v.Transform.ActiveInHierarchy() {
 			continue
 		}
 		var del []int
@@ -61,6 +61,10 @@ func (s *Sprite) SetEnabled(enabled bool) *Sprite {
 	return s
 }
 
+func (s *Sprite) Enabled() bool {
+	return !s.disabled
+}
+
 func (s *Sprite) Origin() (ox, oy float64) {
 	return s.originX, s.originY
 }
@@ -75,6 +79,10 @@ func (s *Sprite) SetOffset(x, y float64) *Sprite {
 	return s
 }
 
+func (s *Sprite) Offset() (x, y float64) {
+	return s.offsetX, s.offsetY
+}
+
 func (s *Sprite) ResetColorMatrix() {
 	s.opt.ColorM.Reset()
 }
@@ -103,7 +111,7 @@ func (s *Sprite) Draw(ctx core.DrawCtx, t *components.Transform) {
 	if s.disabled {
 		return
 	}
-	g := t.GeoM()
+	g := t.InterpolatedGeoM(ctx.Alpha())
 	o := &s.opt
 	o.GeoM.Reset()
 	o.GeoM.Translate(core.ApplyOrigin(s.imageWidth, s.originX)+s.offsetX, core.ApplyOrigin(s.imageHeight, s.originY)+s.offsetY)
@@ -2,6 +2,7 @@ package graphics
 
 import (
 	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
 	"github.com/gabstv/primen/core"
 )
 
@@ -53,6 +54,46 @@ func (a *SpriteAnimation) SetAnimation(anim Animation) {
 	a.reset()
 }
 
+// FPS returns the default fps of the clips
+func (a *SpriteAnimation) FPS() float64 {
+	return a.fps
+}
+
+// ActiveClip returns the clip being played (or nil)
+func (a *SpriteAnimation) ActiveClip() AnimationClip {
+	return a.activeClip
+}
+
+// ActiveFrame returns the current frame of the active clip (-1 if there's
+// no active clip)
+func (a *SpriteAnimation) ActiveFrame() int {
+	return a.activeFrame
+}
+
+// ClipTime returns the elapsed time of the current frame
+func (a *SpriteAnimation) ClipTime() float64 {
+	return a.t
+}
+
+// RestoreState sets the playback state (used to restore saved games). It
+// returns false if the clip or the frame is not valid.
+func (a *SpriteAnimation) RestoreState(clip string, frame int, t float64, playing, reversed bool) bool {
+	if clip == "" {
+		a.reset()
+		return true
+	}
+	c := a.clipMap[clip]
+	if c == nil || frame < 0 || frame >= c.GetFrameCount() {
+		return false
+	}
+	a.activeClip = c
+	a.activeFrame = frame
+	a.t = t
+	a.playing = playing
+	a.reversed = reversed
+	return true
+}
+
 func (a *SpriteAnimation) Playing() bool {
 	return a.playing
 }
@@ -211,7 +252,7 @@ func (s *SpriteAnimationSystem) Update(ctx core.UpdateCtx) {
 	dt := ctx.DT()
 	globalfps := core.Nonzeroval(ctx.TPS(), 60)
 	for _, v := range s.V().Matches() {
-		if !v.SpriteAnimation.playing {
+		if !v.SpriteAnimation.playing || !components.IsActive(s.world, v.Entity) {
 			continue
 		}
 		frame := v.SpriteAnimation.activeFrame
@@ -290,3 +331,13 @@ func (s *SpriteAnimationSystem) Update(ctx core.UpdateCtx) {
 		}
 	}
 }
+
+// WaitAnimationEndStep is a sequence step that waits until the sprite
+// animation of an entity stops playing (or until the entity loses its
+// SpriteAnimation component).
+func WaitAnimationEndStep(e ecs.Entity) components.SequenceStep {
+	return func(ctx *components.SequenceCtx) bool {
+		anim := GetSpriteAnimationComponentData(ctx.World, e)
+		return anim == nil || !anim.Playing()
+	}
+}
@@ -186,7 +186,7 @@ func (l *TextLabel) Draw(ctx core.DrawCtx, tr *components.Transform) {
 	if l.base == nil {
 		return
 	}
-	g := tr.GeoM()
+	g := tr.InterpolatedGeoM(ctx.Alpha())
 	o := &l.opt
 	o.GeoM.Reset()
 	o.GeoM.Translate(core.ApplyOrigin(float64(l.realSize.X), l.originX)+l.offsetX, core.ApplyOrigin(float64(l.realSize.Y), l.originY)+l.offsetY)
@@ -122,7 +122,7 @@ func (t *TileSet) Draw(ctx core.DrawCtx, tr *components.Transform) {
 	if t.disabled {
 		return
 	}
-	g := tr.GeoM()
+	g := tr.InterpolatedGeoM(ctx.Alpha())
 	o := &t.opt
 	o.GeoM.Reset()
 	o.GeoM.Translate(core.ApplyOrigin(t.cellWidth*float64(t.cSize.X), t.originX)+t.offsetX, core.ApplyOrigin(t.cellHeight*float64(t.cSize.Y), t.originY)+t.offsetY)
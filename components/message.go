@@ -0,0 +1,174 @@
+package components
+
+import (
+	"github.com/gabstv/ecs/v2"
+)
+
+// MessageHandlerID is the id of a message handler of an entity
+type MessageHandlerID int64
+
+// MessageFn is a valid entity message handler
+type MessageFn func(msg *Message)
+
+// MessageCatchAll is the message name used by handlers that receive every
+// message sent to an entity
+const MessageCatchAll = "*"
+
+// Message is an entity-targeted message
+type Message struct {
+	Name string
+	Data interface{}
+	// Target is the entity that received the message first
+	Target ecs.Entity
+	// Current is the entity whose handlers are being called
+	Current   ecs.Entity
+	World     ecs.BaseWorld
+	cancelled bool
+}
+
+// Cancel stops the message. The remaining handlers (of the current entity
+// and of the parent entities) are not called.
+func (m *Message) Cancel() {
+	m.cancelled = true
+}
+
+// Cancelled returns true if a handler called Cancel
+func (m *Message) Cancelled() bool {
+	return m.cancelled
+}
+
+type messageHandler struct {
+	id MessageHandlerID
+	fn MessageFn
+}
+
+// MessageHandlers holds the message handlers of an entity
+type MessageHandlers struct {
+	nextid   MessageHandlerID
+	handlers map[string][]messageHandler
+}
+
+// NewMessageHandlers returns a new MessageHandlers component data
+func NewMessageHandlers() MessageHandlers {
+	return MessageHandlers{
+		handlers: make(map[string][]messageHandler),
+	}
+}
+
+// Add a message handler. Use MessageCatchAll to receive every message.
+func (h *MessageHandlers) Add(name string, fn MessageFn) MessageHandlerID {
+	if h.handlers == nil {
+		h.handlers = make(map[string][]messageHandler)
+	}
+	h.nextid++
+	h.handlers[name] = append(h.handlers[name], messageHandler{
+		id: h.nextid,
+		fn: fn,
+	})
+	return h.nextid
+}
+
+// Remove a message handler by ID
+func (h *MessageHandlers) Remove(id MessageHandlerID) bool {
+	for name, list := range h.handlers {
+		for i, v := range list {
+			if v.id == id {
+				h.handlers[name] = list[:i+copy(list[i:], list[i+1:])]
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Clear removes all message handlers
+func (h *MessageHandlers) Clear() {
+	h.handlers = make(map[string][]messageHandler)
+}
+
+// Handle calls the handlers of the message (in registration order) and
+// then the catch-all handlers.
+func (h *MessageHandlers) Handle(msg *Message) {
+	for _, name := range [2]string{msg.Name, MessageCatchAll} {
+		list := h.handlers[name]
+		if len(list) == 0 {
+			continue
+		}
+		// copy, a handler may remove itself
+		clone := make([]messageHandler, len(list))
+		copy(clone, list)
+		for _, v := range clone {
+			v.fn(msg)
+			if msg.cancelled {
+				return
+			}
+		}
+	}
+}
+
+//go:generate ecsgen -n MessageHandlers -p components -o messagehandlers_component.go --component-tpl --vars "UUID=29435637-32ED-411D-A7C8-7AC0A79A05CE"
+
+// AddMessageHandler adds a message handler to an entity (the
+// MessageHandlers component is created if needed).
+func AddMessageHandler(w ecs.BaseWorld, e ecs.Entity, name string, fn MessageFn) MessageHandlerID {
+	c := GetMessageHandlersComponent(w)
+	if c.Data(e) == nil {
+		c.Upsert(e, NewMessageHandlers())
+	}
+	return c.Data(e).Add(name, fn)
+}
+
+// RemoveMessageHandler removes a message handler of an entity.
+func RemoveMessageHandler(w ecs.BaseWorld, e ecs.Entity, id MessageHandlerID) bool {
+	if d := GetMessageHandlersComponentData(w, e); d != nil {
+		return d.Remove(id)
+	}
+	return false
+}
+
+// SendMessage delivers a message to the handlers of an entity. If bubble is
+// true, the message is also delivered to the entity's Transform parents
+// (from the closest to the root) until a handler cancels it.
+//
+// It returns false if the message was cancelled.
+func SendMessage(w ecs.BaseWorld, e ecs.Entity, name string, data interface{}, bubble bool) bool {
+	msg := &Message{
+		Name:   name,
+		Data:   data,
+		Target: e,
+		World:  w,
+	}
+	return SendMessageChain(msg, func(cur ecs.Entity) ecs.Entity {
+		if !bubble {
+			return 0
+		}
+		if t := GetTransformComponentData(w, cur); t != nil {
+			return t.Parent()
+		}
+		return 0
+	})
+}
+
+// SendMessageChain delivers a message to msg.Target and then to each entity
+// returned by next, until next returns 0 or the message is cancelled.
+//
+// It returns false if the message was cancelled.
+func SendMessageChain(msg *Message, next func(cur ecs.Entity) ecs.Entity) bool {
+	c := GetMessageHandlersComponent(msg.World)
+	visited := make(map[ecs.Entity]struct{})
+	for cur := msg.Target; cur != 0; cur = next(cur) {
+		if _, ok := visited[cur]; ok {
+			// cyclic hierarchy
+			break
+		}
+		visited[cur] = struct{}{}
+		msg.Current = cur
+		if h := c.Data(cur); h != nil {
+			h.Handle(msg)
+			if msg.cancelled {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+// Code generated by ecs https://github.com/gabstv/ecs; DO NOT EDIT.
+
+package components
+
+import (
+    "sort"
+    
+
+    "github.com/gabstv/ecs/v2"
+)
+
+
+
+
+
+
+
+
+const uuidMessageHandlersComponent = "29435637-32ED-411D-A7C8-7AC0A79A05CE"
+const capMessageHandlersComponent = 256
+
+type drawerMessageHandlersComponent struct {
+    Entity ecs.Entity
+    Data   MessageHandlers
+}
+
+// WatchMessageHandlers is a helper struct to access a valid pointer of MessageHandlers
+type WatchMessageHandlers interface {
+    Entity() ecs.Entity
+    Data() *MessageHandlers
+}
+
+type slcdrawerMessageHandlersComponent []drawerMessageHandlersComponent
+func (a slcdrawerMessageHandlersComponent) Len() int           { return len(a) }
+func (a slcdrawerMessageHandlersComponent) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a slcdrawerMessageHandlersComponent) Less(i, j int) bool { return a[i].Entity < a[j].Entity }
+
+
+type mWatchMessageHandlers struct {
+    c *MessageHandlersComponent
+    entity ecs.Entity
+}
+
+func (w *mWatchMessageHandlers) Entity() ecs.Entity {
+    return w.entity
+}
+
+func (w *mWatchMessageHandlers) Data() *MessageHandlers {
+    
+    
+    id := w.c.indexof(w.entity)
+    if id == -1 {
+        return nil
+    }
+    return &w.c.data[id].Data
+}
+
+// MessageHandlersComponent implements ecs.BaseComponent
+type MessageHandlersComponent struct {
+    initialized bool
+    flag        ecs.Flag
+    world       ecs.BaseWorld
+    wkey        [4]byte
+    data        []drawerMessageHandlersComponent
+    
+}
+
+// GetMessageHandlersComponent returns the instance of the component in a World
+func GetMessageHandlersComponent(w ecs.BaseWorld) *MessageHandlersComponent {
+    return w.C(uuidMessageHandlersComponent).(*MessageHandlersComponent)
+}
+
+// SetMessageHandlersComponentData updates/adds a MessageHandlers to Entity e
+func SetMessageHandlersComponentData(w ecs.BaseWorld, e ecs.Entity, data MessageHandlers) {
+    GetMessageHandlersComponent(w).Upsert(e, data)
+}
+
+// GetMessageHandlersComponentData gets the *MessageHandlers of Entity e
+func GetMessageHandlersComponentData(w ecs.BaseWorld, e ecs.Entity) *MessageHandlers {
+    return GetMessageHandlersComponent(w).Data(e)
+}
+
+// WatchMessageHandlersComponentData gets a pointer getter of an entity's MessageHandlers.
+//
+// The pointer must not be stored because it may become invalid overtime.
+func WatchMessageHandlersComponentData(w ecs.BaseWorld, e ecs.Entity) WatchMessageHandlers {
+    return &mWatchMessageHandlers{
+        c: GetMessageHandlersComponent(w),
+        entity: e,
+    }
+}
+
+// UUID implements ecs.BaseComponent
+func (MessageHandlersComponent) UUID() string {
+    return "29435637-32ED-411D-A7C8-7AC0A79A05CE"
+}
+
+// Name implements ecs.BaseComponent
+func (MessageHandlersComponent) Name() string {
+    return "MessageHandlersComponent"
+}
+
+func (c *MessageHandlersComponent) indexof(e ecs.Entity) int {
+    i := sort.Search(len(c.data), func(i int) bool { return c.data[i].Entity >= e })
+    if i < len(c.data) && c.data[i].Entity == e {
+        return i
+    }
+    return -1
+}
+
+// Upsert creates or updates a component data of an entity.
+// Not recommended to be used directly. Use SetMessageHandlersComponentData to change component
+// data outside of a system loop.
+func (c *MessageHandlersComponent) Upsert(e ecs.Entity, data interface{}) {
+    v, ok := data.(MessageHandlers)
+    if !ok {
+        panic("data must be MessageHandlers")
+    }
+    
+    id := c.indexof(e)
+    
+    if id > -1 {
+        
+        dwr := &c.data[id]
+        dwr.Data = v
+        
+        return
+    }
+    
+    rsz := false
+    if cap(c.data) == len(c.data) {
+        rsz = true
+        c.world.CWillResize(c, c.wkey)
+        
+    }
+    newindex := len(c.data)
+    c.data = append(c.data, drawerMessageHandlersComponent{
+        Entity: e,
+        Data:   v,
+    })
+    if len(c.data) > 1 {
+        if c.data[newindex].Entity < c.data[newindex-1].Entity {
+            c.world.CWillResize(c, c.wkey)
+            
+            sort.Sort(slcdrawerMessageHandlersComponent(c.data))
+            rsz = true
+        }
+    }
+    
+    if rsz {
+        
+        c.world.CResized(c, c.wkey)
+        c.world.Dispatch(ecs.Event{
+            Type: ecs.EvtComponentsResized,
+            ComponentName: "MessageHandlersComponent",
+            ComponentID: "29435637-32ED-411D-A7C8-7AC0A79A05CE",
+        })
+    }
+    
+    c.world.CAdded(e, c, c.wkey)
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentAdded,
+        ComponentName: "MessageHandlersComponent",
+        ComponentID: "29435637-32ED-411D-A7C8-7AC0A79A05CE",
+        Entity: e,
+    })
+}
+
+// Remove a MessageHandlers data from entity e
+//
+// Warning: DO NOT call remove inside the system entities loop
+func (c *MessageHandlersComponent) Remove(e ecs.Entity) {
+    
+    
+    i := c.indexof(e)
+    if i == -1 {
+        return
+    }
+    
+    //c.data = append(c.data[:i], c.data[i+1:]...)
+    c.data = c.data[:i+copy(c.data[i:], c.data[i+1:])]
+    c.world.CRemoved(e, c, c.wkey)
+    
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentRemoved,
+        ComponentName: "MessageHandlersComponent",
+        ComponentID: "29435637-32ED-411D-A7C8-7AC0A79A05CE",
+        Entity: e,
+    })
+}
+
+func (c *MessageHandlersComponent) Data(e ecs.Entity) *MessageHandlers {
+    
+    
+    index := c.indexof(e)
+    if index > -1 {
+        return &c.data[index].Data
+    }
+    return nil
+}
+
+// Flag returns the 
+func (c *MessageHandlersComponent) Flag() ecs.Flag {
+    return c.flag
+}
+
+// Setup is called by ecs.BaseWorld
+//
+// Do not call this directly
+func (c *MessageHandlersComponent) Setup(w ecs.BaseWorld, f ecs.Flag, key [4]byte) {
+    if c.initialized {
+        panic("MessageHandlersComponent called Setup() more than once")
+    }
+    c.flag = f
+    c.world = w
+    c.wkey = key
+    c.data = make([]drawerMessageHandlersComponent, 0, 256)
+    c.initialized = true
+    
+}
+
+
+func init() {
+    ecs.RegisterComponent(func() ecs.BaseComponent {
+        return &MessageHandlersComponent{}
+    })
+}
@@ -0,0 +1,331 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core"
+)
+
+// Name is the name and the tags of an entity.
+//
+// Use SetName, AddTags and RemoveTags to change the name and the tags of
+// an entity that already has a Name (the indexes of the NameSystem are
+// not updated if the component data is replaced).
+type Name struct {
+	name string
+	tags []string
+}
+
+// NewName returns a new Name component data
+func NewName(name string, tags ...string) Name {
+	n := Name{
+		name: name,
+	}
+	for _, t := range tags {
+		n.addTag(t)
+	}
+	return n
+}
+
+// Name returns the name of the entity
+func (n *Name) Name() string {
+	return n.name
+}
+
+// Tags returns the tags of the entity (sorted)
+func (n *Name) Tags() []string {
+	return append([]string(nil), n.tags...)
+}
+
+// HasTag returns true if the entity has the tag
+func (n *Name) HasTag(tag string) bool {
+	i := sort.SearchStrings(n.tags, tag)
+	return i < len(n.tags) && n.tags[i] == tag
+}
+
+func (n *Name) addTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	i := sort.SearchStrings(n.tags, tag)
+	if i < len(n.tags) && n.tags[i] == tag {
+		return false
+	}
+	n.tags = append(n.tags, "")
+	copy(n.tags[i+1:], n.tags[i:])
+	n.tags[i] = tag
+	return true
+}
+
+func (n *Name) removeTag(tag string) bool {
+	i := sort.SearchStrings(n.tags, tag)
+	if i < len(n.tags) && n.tags[i] == tag {
+		n.tags = n.tags[:i+copy(n.tags[i:], n.tags[i+1:])]
+		return true
+	}
+	return false
+}
+
+//go:generate ecsgen -n Name -p components -o name_component.go --component-tpl --vars "UUID=40B5DF6E-50CA-4E39-92F1-BD8622A2D972"
+
+//go:generate ecsgen -n Name -p components -o name_system.go --system-tpl --vars "EntityAdded=s.onEntityAdded(e)" --vars "EntityRemoved=s.onEntityRemoved(e)" --vars "Setup=s.setupNames()" --vars "Priority=90" --vars "UUID=711B86E7-8641-4A18-9B9A-8713204D364E" --components "Name" --members "names=map[ecs.Entity]Name" --members "byname=map[string][]ecs.Entity" --members "bytag=map[string][]ecs.Entity"
+
+var matchNameSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetNameComponent(w).Flag())
+}
+
+var resizematchNameSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetNameComponent(w).Flag())
+}
+
+func (s *NameSystem) setupNames() {
+	s.names = make(map[ecs.Entity]Name)
+	s.byname = make(map[string][]ecs.Entity)
+	s.bytag = make(map[string][]ecs.Entity)
+}
+
+func (s *NameSystem) onEntityAdded(e ecs.Entity) {
+	if d := GetNameComponentData(s.world, e); d != nil {
+		s.index(e, *d)
+	}
+}
+
+func (s *NameSystem) onEntityRemoved(e ecs.Entity) {
+	s.unindex(e)
+}
+
+func (s *NameSystem) index(e ecs.Entity, n Name) {
+	n.tags = append([]string(nil), n.tags...)
+	s.names[e] = n
+	if n.name != "" {
+		s.byname[n.name] = insertEntity(s.byname[n.name], e)
+	}
+	for _, t := range n.tags {
+		s.bytag[t] = insertEntity(s.bytag[t], e)
+	}
+}
+
+func (s *NameSystem) unindex(e ecs.Entity) {
+	n, ok := s.names[e]
+	if !ok {
+		return
+	}
+	delete(s.names, e)
+	if n.name != "" {
+		if l := removeEntity(s.byname[n.name], e); len(l) > 0 {
+			s.byname[n.name] = l
+		} else {
+			delete(s.byname, n.name)
+		}
+	}
+	for _, t := range n.tags {
+		if l := removeEntity(s.bytag[t], e); len(l) > 0 {
+			s.bytag[t] = l
+		} else {
+			delete(s.bytag, t)
+		}
+	}
+}
+
+// update changes the Name of an entity (adding the component if needed)
+func (s *NameSystem) update(e ecs.Entity, fn func(n *Name)) {
+	d := GetNameComponentData(s.world, e)
+	if d == nil {
+		n := Name{}
+		fn(&n)
+		SetNameComponentData(s.world, e, n)
+		return
+	}
+	s.unindex(e)
+	fn(d)
+	s.index(e, *d)
+}
+
+// SetName sets the name of an entity
+func (s *NameSystem) SetName(e ecs.Entity, name string) {
+	s.update(e, func(n *Name) {
+		n.name = name
+	})
+}
+
+// AddTags adds tags to an entity
+func (s *NameSystem) AddTags(e ecs.Entity, tags ...string) {
+	s.update(e, func(n *Name) {
+		for _, t := range tags {
+			n.addTag(t)
+		}
+	})
+}
+
+// RemoveTags removes tags from an entity
+func (s *NameSystem) RemoveTags(e ecs.Entity, tags ...string) {
+	if GetNameComponentData(s.world, e) == nil {
+		return
+	}
+	s.update(e, func(n *Name) {
+		for _, t := range tags {
+			n.removeTag(t)
+		}
+	})
+}
+
+// FindByName returns the first entity (the lowest id) with the name. It
+// returns 0 if no entity is found.
+func (s *NameSystem) FindByName(name string) ecs.Entity {
+	if l := s.byname[name]; len(l) > 0 {
+		return l[0]
+	}
+	return 0
+}
+
+// FindAllByName returns all the entities with the name
+func (s *NameSystem) FindAllByName(name string) []ecs.Entity {
+	return append([]ecs.Entity(nil), s.byname[name]...)
+}
+
+// FindAllByTag returns all the entities with the tag
+func (s *NameSystem) FindAllByTag(tag string) []ecs.Entity {
+	return append([]ecs.Entity(nil), s.bytag[tag]...)
+}
+
+// FindPath returns the entity at path ("level/enemies/boss"). The path
+// follows the Transform hierarchy of the named entities (unnamed parents
+// are skipped, see Path); the first segment is a named entity without a
+// named parent. It returns 0 if no entity is found.
+func (s *NameSystem) FindPath(path string) ecs.Entity {
+	return s.FindPathFrom(0, path)
+}
+
+// FindPathFrom returns the entity at path, relative to the entity root
+// (see FindPath). If root is 0, the path is absolute.
+func (s *NameSystem) FindPathFrom(root ecs.Entity, path string) ecs.Entity {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return root
+	}
+	for _, e := range s.byname[segments[len(segments)-1]] {
+		cur := e
+		ok := true
+		for i := len(segments) - 2; i >= 0; i-- {
+			p := s.namedParentOf(cur, root)
+			if p == 0 || p == root || s.names[p].name != segments[i] {
+				ok = false
+				break
+			}
+			cur = p
+		}
+		if ok && s.namedParentOf(cur, root) == root {
+			return e
+		}
+	}
+	return 0
+}
+
+// Path returns the path of an entity (the names of the entity and its
+// Transform parents). Unnamed parents are skipped, so FindPath(Path(e))
+// returns e. It returns "" if the entity has no name.
+func (s *NameSystem) Path(e ecs.Entity) string {
+	if s.names[e].name == "" {
+		return ""
+	}
+	segments := make([]string, 0, 4)
+	for cur := e; cur != 0; cur = s.namedParentOf(cur, 0) {
+		segments = append(segments, s.names[cur].name)
+	}
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	return strings.Join(segments, "/")
+}
+
+// namedParentOf returns the first named Transform parent of e (or root, if
+// it's found first). It returns 0 if there is none.
+func (s *NameSystem) namedParentOf(e, root ecs.Entity) ecs.Entity {
+	for p := s.parentOf(e); p != 0; p = s.parentOf(p) {
+		if p == root || s.names[p].name != "" {
+			return p
+		}
+	}
+	return 0
+}
+
+func (s *NameSystem) parentOf(e ecs.Entity) ecs.Entity {
+	if tr := GetTransformComponentData(s.world, e); tr != nil {
+		return tr.Parent()
+	}
+	return 0
+}
+
+// DrawPriority noop
+func (s *NameSystem) DrawPriority(ctx core.DrawCtx) {}
+
+// Draw noop
+func (s *NameSystem) Draw(ctx core.DrawCtx) {}
+
+// UpdatePriority noop
+func (s *NameSystem) UpdatePriority(ctx core.UpdateCtx) {}
+
+// Update noop
+func (s *NameSystem) Update(ctx core.UpdateCtx) {}
+
+// SetName sets the name of an entity (see NameSystem.SetName)
+func SetName(w ecs.BaseWorld, e ecs.Entity, name string) {
+	GetNameSystem(w).SetName(e, name)
+}
+
+// AddTags adds tags to an entity (see NameSystem.AddTags)
+func AddTags(w ecs.BaseWorld, e ecs.Entity, tags ...string) {
+	GetNameSystem(w).AddTags(e, tags...)
+}
+
+// RemoveTags removes tags from an entity (see NameSystem.RemoveTags)
+func RemoveTags(w ecs.BaseWorld, e ecs.Entity, tags ...string) {
+	GetNameSystem(w).RemoveTags(e, tags...)
+}
+
+// FindByName returns the first entity with the name (see
+// NameSystem.FindByName)
+func FindByName(w ecs.BaseWorld, name string) ecs.Entity {
+	return GetNameSystem(w).FindByName(name)
+}
+
+// FindAllByTag returns all the entities with the tag
+func FindAllByTag(w ecs.BaseWorld, tag string) []ecs.Entity {
+	return GetNameSystem(w).FindAllByTag(tag)
+}
+
+// FindPath returns the entity at path (see NameSystem.FindPath)
+func FindPath(w ecs.BaseWorld, path string) ecs.Entity {
+	return GetNameSystem(w).FindPath(path)
+}
+
+func splitPath(path string) []string {
+	out := make([]string, 0, 4)
+	for _, v := range strings.Split(path, "/") {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func insertEntity(l []ecs.Entity, e ecs.Entity) []ecs.Entity {
+	i := sort.Search(len(l), func(i int) bool { return l[i] >= e })
+	if i < len(l) && l[i] == e {
+		return l
+	}
+	l = append(l, 0)
+	copy(l[i+1:], l[i:])
+	l[i] = e
+	return l
+}
+
+func removeEntity(l []ecs.Entity, e ecs.Entity) []ecs.Entity {
+	i := sort.Search(len(l), func(i int) bool { return l[i] >= e })
+	if i < len(l) && l[i] == e {
+		return l[:i+copy(l[i:], l[i+1:])]
+	}
+	return l
+}
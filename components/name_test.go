@@ -0,0 +1,73 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEntity creates an entity with a Transform (and a name, if not "")
+func newTestEntity(w ecs.BaseWorld, parent ecs.Entity, name string) ecs.Entity {
+	e := w.NewEntity()
+	SetTransformComponentData(w, e, NewTransform(0, 0))
+	if parent != 0 {
+		_ = GetTransformComponentData(w, e).SetParent(parent)
+	}
+	if name != "" {
+		SetName(w, e, name)
+	}
+	return e
+}
+
+func TestNames(t *testing.T) {
+	_, w := newTestWorld()
+	level := newTestEntity(w, 0, "level")
+	enemies := newTestEntity(w, level, "enemies")
+	boss := newTestEntity(w, enemies, "boss")
+	AddTags(w, boss, "enemy", "big")
+	weapon := newTestEntity(w, boss, "weapon")
+	minion := newTestEntity(w, enemies, "minion")
+	AddTags(w, minion, "enemy")
+	s := GetNameSystem(w)
+
+	assert.Equal(t, weapon, FindPath(w, "level/enemies/boss/weapon"))
+	assert.Equal(t, ecs.Entity(0), FindPath(w, "enemies/boss/weapon"))
+	assert.Equal(t, weapon, s.FindPathFrom(enemies, "boss/weapon"))
+	assert.Equal(t, boss, FindByName(w, "boss"))
+	assert.Equal(t, []ecs.Entity{boss, minion}, FindAllByTag(w, "enemy"))
+	assert.Equal(t, "level/enemies/boss/weapon", s.Path(weapon))
+
+	// reparent
+	assert.NoError(t, GetTransformComponentData(w, weapon).SetParent(minion))
+	assert.Equal(t, ecs.Entity(0), FindPath(w, "level/enemies/boss/weapon"))
+	assert.Equal(t, weapon, FindPath(w, "level/enemies/minion/weapon"))
+
+	// rename and remove
+	SetName(w, boss, "king")
+	assert.Equal(t, boss, FindPath(w, "level/enemies/king"))
+	RemoveTags(w, boss, "big")
+	assert.Equal(t, 0, len(FindAllByTag(w, "big")))
+	w.RemoveEntity(minion)
+	assert.Equal(t, []ecs.Entity{boss}, FindAllByTag(w, "enemy"))
+}
+
+func TestNamePathUnnamed(t *testing.T) {
+	_, w := newTestWorld()
+	s := GetNameSystem(w)
+	group := newTestEntity(w, 0, "")
+	level := newTestEntity(w, group, "level")
+	pivot := newTestEntity(w, level, "")
+	boss := newTestEntity(w, pivot, "boss")
+	hat := newTestEntity(w, boss, "hat")
+
+	// unnamed parents are skipped both ways
+	for _, e := range []ecs.Entity{level, boss, hat} {
+		assert.Equal(t, e, FindPath(w, s.Path(e)))
+	}
+	assert.Equal(t, "level/boss/hat", s.Path(hat))
+	assert.Equal(t, hat, s.FindPathFrom(level, "boss/hat"))
+	assert.Equal(t, hat, s.FindPathFrom(pivot, "boss/hat"))
+	assert.Equal(t, ecs.Entity(0), s.FindPathFrom(boss, "boss/hat"))
+	assert.Equal(t, "", s.Path(pivot))
+}
@@ -0,0 +1,227 @@
+// Code generated by ecs https://github.com/gabstv/ecs; DO NOT EDIT.
+
+package components
+
+import (
+    "sort"
+    
+
+    "github.com/gabstv/ecs/v2"
+)
+
+
+
+
+
+
+
+
+const uuidScheduledComponent = "61A0FA40-042C-44F1-80E0-B6E4B448F475"
+const capScheduledComponent = 256
+
+type drawerScheduledComponent struct {
+    Entity ecs.Entity
+    Data   Scheduled
+}
+
+// WatchScheduled is a helper struct to access a valid pointer of Scheduled
+type WatchScheduled interface {
+    Entity() ecs.Entity
+    Data() *Scheduled
+}
+
+type slcdrawerScheduledComponent []drawerScheduledComponent
+func (a slcdrawerScheduledComponent) Len() int           { return len(a) }
+func (a slcdrawerScheduledComponent) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a slcdrawerScheduledComponent) Less(i, j int) bool { return a[i].Entity < a[j].Entity }
+
+
+type mWatchScheduled struct {
+    c *ScheduledComponent
+    entity ecs.Entity
+}
+
+func (w *mWatchScheduled) Entity() ecs.Entity {
+    return w.entity
+}
+
+func (w *mWatchScheduled) Data() *Scheduled {
+    
+    
+    id := w.c.indexof(w.entity)
+    if id == -1 {
+        return nil
+    }
+    return &w.c.data[id].Data
+}
+
+// ScheduledComponent implements ecs.BaseComponent
+type ScheduledComponent struct {
+    initialized bool
+    flag        ecs.Flag
+    world       ecs.BaseWorld
+    wkey        [4]byte
+    data        []drawerScheduledComponent
+    
+}
+
+// GetScheduledComponent returns the instance of the component in a World
+func GetScheduledComponent(w ecs.BaseWorld) *ScheduledComponent {
+    return w.C(uuidScheduledComponent).(*ScheduledComponent)
+}
+
+// SetScheduledComponentData updates/adds a Scheduled to Entity e
+func SetScheduledComponentData(w ecs.BaseWorld, e ecs.Entity, data Scheduled) {
+    GetScheduledComponent(w).Upsert(e, data)
+}
+
+// GetScheduledComponentData gets the *Scheduled of Entity e
+func GetScheduledComponentData(w ecs.BaseWorld, e ecs.Entity) *Scheduled {
+    return GetScheduledComponent(w).Data(e)
+}
+
+// WatchScheduledComponentData gets a pointer getter of an entity's Scheduled.
+//
+// The pointer must not be stored because it may become invalid overtime.
+func WatchScheduledComponentData(w ecs.BaseWorld, e ecs.Entity) WatchScheduled {
+    return &mWatchScheduled{
+        c: GetScheduledComponent(w),
+        entity: e,
+    }
+}
+
+// UUID implements ecs.BaseComponent
+func (ScheduledComponent) UUID() string {
+    return "61A0FA40-042C-44F1-80E0-B6E4B448F475"
+}
+
+// Name implements ecs.BaseComponent
+func (ScheduledComponent) Name() string {
+    return "ScheduledComponent"
+}
+
+func (c *ScheduledComponent) indexof(e ecs.Entity) int {
+    i := sort.Search(len(c.data), func(i int) bool { return c.data[i].Entity >= e })
+    if i < len(c.data) && c.data[i].Entity == e {
+        return i
+    }
+    return -1
+}
+
+// Upsert creates or updates a component data of an entity.
+// Not recommended to be used directly. Use SetScheduledComponentData to change component
+// data outside of a system loop.
+func (c *ScheduledComponent) Upsert(e ecs.Entity, data interface{}) {
+    v, ok := data.(Scheduled)
+    if !ok {
+        panic("data must be Scheduled")
+    }
+    
+    id := c.indexof(e)
+    
+    if id > -1 {
+        
+        dwr := &c.data[id]
+        dwr.Data = v
+        
+        return
+    }
+    
+    rsz := false
+    if cap(c.data) == len(c.data) {
+        rsz = true
+        c.world.CWillResize(c, c.wkey)
+        
+    }
+    newindex := len(c.data)
+    c.data = append(c.data, drawerScheduledComponent{
+        Entity: e,
+        Data:   v,
+    })
+    if len(c.data) > 1 {
+        if c.data[newindex].Entity < c.data[newindex-1].Entity {
+            c.world.CWillResize(c, c.wkey)
+            
+            sort.Sort(slcdrawerScheduledComponent(c.data))
+            rsz = true
+        }
+    }
+    
+    if rsz {
+        
+        c.world.CResized(c, c.wkey)
+        c.world.Dispatch(ecs.Event{
+            Type: ecs.EvtComponentsResized,
+            ComponentName: "ScheduledComponent",
+            ComponentID: "61A0FA40-042C-44F1-80E0-B6E4B448F475",
+        })
+    }
+    
+    c.world.CAdded(e, c, c.wkey)
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentAdded,
+        ComponentName: "ScheduledComponent",
+        ComponentID: "61A0FA40-042C-44F1-80E0-B6E4B448F475",
+        Entity: e,
+    })
+}
+
+// Remove a Scheduled data from entity e
+//
+// Warning: DO NOT call remove inside the system entities loop
+func (c *ScheduledComponent) Remove(e ecs.Entity) {
+    
+    
+    i := c.indexof(e)
+    if i == -1 {
+        return
+    }
+    
+    //c.data = append(c.data[:i], c.data[i+1:]...)
+    c.data = c.data[:i+copy(c.data[i:], c.data[i+1:])]
+    c.world.CRemoved(e, c, c.wkey)
+    
+    c.world.Dispatch(ecs.Event{
+        Type: ecs.EvtComponentRemoved,
+        ComponentName: "ScheduledComponent",
+        ComponentID: "61A0FA40-042C-44F1-80E0-B6E4B448F475",
+        Entity: e,
+    })
+}
+
+func (c *ScheduledComponent) Data(e ecs.Entity) *Scheduled {
+    
+    
+    index := c.indexof(e)
+    if index > -1 {
+        return &c.data[index].Data
+    }
+    return nil
+}
+
+// Flag returns the 
+func (c *ScheduledComponent) Flag() ecs.Flag {
+    return c.flag
+}
+
+// Setup is called by ecs.BaseWorld
+//
+// Do not call this directly
+func (c *ScheduledComponent) Setup(w ecs.BaseWorld, f ecs.Flag, key [4]byte) {
+    if c.initialized {
+        panic("ScheduledComponent called Setup() more than once")
+    }
+    c.flag = f
+    c.world = w
+    c.wkey = key
+    c.data = make([]drawerScheduledComponent, 0, 256)
+    c.initialized = true
+    
+}
+
+
+func init() {
+    ecs.RegisterComponent(func() ecs.BaseComponent {
+        return &ScheduledComponent{}
+    })
+}
@@ -0,0 +1,233 @@
+package components
+
+import (
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core"
+)
+
+// Timer is a handle of a scheduled function or sequence
+type Timer struct {
+	id       int64
+	entity   ecs.Entity
+	delay    float64
+	interval float64
+	repeat   bool
+	elapsed  float64
+	fn       func()
+	seq      *sequenceRun
+	done     bool
+	s        *SchedulerSystem
+}
+
+// Cancel stops the timer. It returns false if the timer already finished
+// (or was already cancelled).
+func (t *Timer) Cancel() bool {
+	if t == nil || t.done {
+		return false
+	}
+	t.finish()
+	return true
+}
+
+// Active returns true if the timer didn't finish and wasn't cancelled
+func (t *Timer) Active() bool {
+	return t != nil && !t.done
+}
+
+// Entity returns the entity that owns the timer (0 if it's not owned)
+func (t *Timer) Entity() ecs.Entity {
+	return t.entity
+}
+
+func (t *Timer) finish() {
+	t.done = true
+	if t.seq != nil {
+		t.seq.cleanup()
+	}
+	if t.entity != 0 {
+		if d := GetScheduledComponentData(t.s.world, t.entity); d != nil {
+			d.timers--
+		}
+	}
+}
+
+// Scheduled is added to the entities that own timers. The timers of an
+// entity are cancelled when the component is removed (or when the entity
+// is destroyed).
+type Scheduled struct {
+	timers int
+}
+
+// Timers returns the number of active timers owned by the entity
+func (s *Scheduled) Timers() int {
+	return s.timers
+}
+
+//go:generate ecsgen -n Scheduled -p components -o scheduled_component.go --component-tpl --vars "UUID=61A0FA40-042C-44F1-80E0-B6E4B448F475"
+
+//go:generate ecsgen -n Scheduler -p components -o scheduler_system.go --system-tpl --vars "EntityRemoved=s.onEntityRemoved(e)" --vars "Setup=s.setupScheduler()" --vars "Priority=80" --vars "UUID=3BA758CF-588E-4070-AAF8-7D402B2DA28F" --components "Scheduled" --members "timers=[]*Timer" --members "nextid=int64"
+
+var matchSchedulerSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetScheduledComponent(w).Flag())
+}
+
+var resizematchSchedulerSystem = func(f ecs.Flag, w ecs.BaseWorld) bool {
+	return f.Contains(GetScheduledComponent(w).Flag())
+}
+
+func (s *SchedulerSystem) setupScheduler() {
+	s.timers = make([]*Timer, 0, 16)
+}
+
+func (s *SchedulerSystem) onEntityRemoved(e ecs.Entity) {
+	s.CancelAll(e)
+}
+
+// After calls fn once, after d seconds (of world time)
+func (s *SchedulerSystem) After(d float64, fn func()) *Timer {
+	return s.add(&Timer{delay: d, fn: fn})
+}
+
+// Every calls fn every d seconds (of world time) until the timer is
+// cancelled
+func (s *SchedulerSystem) Every(d float64, fn func()) *Timer {
+	return s.add(&Timer{delay: d, interval: d, repeat: true, fn: fn})
+}
+
+// Run starts a sequence
+func (s *SchedulerSystem) Run(seq *Sequence) *Timer {
+	return s.add(&Timer{seq: newSequenceRun(seq)})
+}
+
+// AfterFor is like After, but the timer is owned by an entity (and it's
+// cancelled when the entity is destroyed)
+func (s *SchedulerSystem) AfterFor(e ecs.Entity, d float64, fn func()) *Timer {
+	return s.add(&Timer{entity: e, delay: d, fn: fn})
+}
+
+// EveryFor is like Every, but the timer is owned by an entity (and it's
+// cancelled when the entity is destroyed)
+func (s *SchedulerSystem) EveryFor(e ecs.Entity, d float64, fn func()) *Timer {
+	return s.add(&Timer{entity: e, delay: d, interval: d, repeat: true, fn: fn})
+}
+
+// RunFor is like Run, but the sequence is owned by an entity (and it's
+// cancelled when the entity is destroyed)
+func (s *SchedulerSystem) RunFor(e ecs.Entity, seq *Sequence) *Timer {
+	return s.add(&Timer{entity: e, seq: newSequenceRun(seq)})
+}
+
+// CancelAll cancels all the timers owned by an entity. It returns the
+// number of cancelled timers.
+func (s *SchedulerSystem) CancelAll(e ecs.Entity) int {
+	n := 0
+	for _, t := range s.timers {
+		if t.entity == e && t.Cancel() {
+			n++
+		}
+	}
+	return n
+}
+
+// Timers returns the number of active timers
+func (s *SchedulerSystem) Timers() int {
+	n := 0
+	for _, t := range s.timers {
+		if !t.done {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *SchedulerSystem) add(t *Timer) *Timer {
+	s.nextid++
+	t.id = s.nextid
+	t.s = s
+	if t.entity != 0 {
+		c := GetScheduledComponent(s.world)
+		if c.Data(t.entity) == nil {
+			c.Upsert(t.entity, Scheduled{})
+		}
+		c.Data(t.entity).timers++
+	}
+	s.timers = append(s.timers, t)
+	return t
+}
+
+// DrawPriority noop
+func (s *SchedulerSystem) DrawPriority(ctx core.DrawCtx) {}
+
+// Draw noop
+func (s *SchedulerSystem) Draw(ctx core.DrawCtx) {}
+
+// UpdatePriority noop
+func (s *SchedulerSystem) UpdatePriority(ctx core.UpdateCtx) {}
+
+// Update advances the timers with the world delta (so the timers respect
+// the world time scale). The timers and sequences of a paused world are not
+// updated.
+func (s *SchedulerSystem) Update(ctx core.UpdateCtx) {
+	if ctx.TimeScale() == 0 {
+		return
+	}
+	dt := ctx.DT()
+	// timers created by the callbacks start on the next frame
+	timers := s.timers
+	for _, t := range timers {
+		if t.done {
+			continue
+		}
+		if t.entity != 0 && !IsActive(s.world, t.entity) {
+			// the timers of inactive entities are paused
+			continue
+		}
+		if t.seq != nil {
+			if t.seq.update(ctx, s.world, t.entity, dt) {
+				t.finish()
+			}
+			continue
+		}
+		t.elapsed += dt
+		for !t.done && t.elapsed >= t.delay {
+			if !t.repeat {
+				t.finish()
+				t.fn()
+				break
+			}
+			t.fn()
+			if t.interval <= 0 {
+				t.elapsed = 0
+				break
+			}
+			t.elapsed -= t.interval
+		}
+	}
+	// remove the finished timers
+	n := 0
+	for _, t := range s.timers {
+		if !t.done {
+			s.timers[n] = t
+			n++
+		}
+	}
+	for i := n; i < len(s.timers); i++ {
+		s.timers[i] = nil
+	}
+	s.timers = s.timers[:n]
+}
+
+// After calls fn once, after d seconds (of world time)
+func After(w ecs.BaseWorld, d float64, fn func()) *Timer {
+	return GetSchedulerSystem(w).After(d, fn)
+}
+
+// Every calls fn every d seconds (of world time)
+func Every(w ecs.BaseWorld, d float64, fn func()) *Timer {
+	return GetSchedulerSystem(w).Every(d, fn)
+}
+
+// RunSequence starts a sequence on the world scheduler
+func RunSequence(w ecs.BaseWorld, seq *Sequence) *Timer {
+	return GetSchedulerSystem(w).Run(seq)
+}
@@ -0,0 +1,49 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	e, w := newTestWorld()
+	s := GetSchedulerSystem(w)
+	after := 0
+	every := 0
+	s.After(1, func() { after++ })
+	tmr := s.Every(0.5, func() { every++ })
+	owner := w.NewEntity()
+	owned := 0
+	s.EveryFor(owner, 0.25, func() { owned++ })
+	var steps []string
+	s.Run(NewSequence().
+		Wait(0.5).
+		Call(func() { steps = append(steps, "a") }).
+		WaitEvent("go").
+		Call(func() { steps = append(steps, "b") }))
+
+	e.stepN(w, 4, 0.25)
+	assert.Equal(t, 1, after)
+	assert.Equal(t, 2, every)
+	assert.Equal(t, 4, owned)
+	assert.Equal(t, []string{"a"}, steps)
+
+	// paused worlds don't advance the timers or the sequences
+	w.SetPaused(true)
+	e.DispatchEvent("go", nil)
+	e.stepN(w, 4, 0.25)
+	assert.Equal(t, 2, every)
+	assert.Equal(t, []string{"a"}, steps)
+	w.SetPaused(false)
+	e.step(w, 0.25)
+	assert.Equal(t, []string{"a", "b"}, steps)
+
+	// the timers of a removed entity are cancelled
+	w.RemoveEntity(owner)
+	assert.True(t, tmr.Cancel())
+	e.stepN(w, 4, 0.25)
+	assert.Equal(t, 5, owned)
+	assert.Equal(t, 2, every)
+	assert.Equal(t, 0, s.Timers())
+}
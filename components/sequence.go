@@ -0,0 +1,164 @@
+package components
+
+import (
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core"
+)
+
+// SequenceCtx is passed to the steps of a sequence
+type SequenceCtx struct {
+	Ctx    core.UpdateCtx
+	World  ecs.BaseWorld
+	Entity ecs.Entity // the entity that owns the sequence (or 0)
+	DT     float64
+	// Elapsed is the time (of world time) since the current step started
+	// (including DT)
+	Elapsed float64
+	// First is true on the first call of a step
+	First bool
+	// State can be used by the step to store data (it's reset on each
+	// step)
+	State   interface{}
+	cleanup []func()
+}
+
+// Defer registers a function that is called when the current step ends
+// (or when the sequence is cancelled)
+func (ctx *SequenceCtx) Defer(fn func()) {
+	ctx.cleanup = append(ctx.cleanup, fn)
+}
+
+// SequenceStep is called on every frame until it returns true
+type SequenceStep func(ctx *SequenceCtx) bool
+
+// Sequence is a list of steps that run one after the other (see
+// SchedulerSystem.Run). A sequence can run more than once.
+type Sequence struct {
+	steps []SequenceStep
+}
+
+// NewSequence creates a sequence
+func NewSequence(steps ...SequenceStep) *Sequence {
+	return &Sequence{
+		steps: append([]SequenceStep(nil), steps...),
+	}
+}
+
+// Then appends steps
+func (s *Sequence) Then(steps ...SequenceStep) *Sequence {
+	s.steps = append(s.steps, steps...)
+	return s
+}
+
+// Wait appends a WaitStep
+func (s *Sequence) Wait(d float64) *Sequence {
+	return s.Then(WaitStep(d))
+}
+
+// Call appends a CallStep
+func (s *Sequence) Call(fn func()) *Sequence {
+	return s.Then(CallStep(fn))
+}
+
+// WaitUntil appends a WaitUntilStep
+func (s *Sequence) WaitUntil(cond func() bool) *Sequence {
+	return s.Then(WaitUntilStep(cond))
+}
+
+// WaitEvent appends a WaitEventStep
+func (s *Sequence) WaitEvent(name string) *Sequence {
+	return s.Then(WaitEventStep(name))
+}
+
+// WaitStep waits d seconds (of world time)
+func WaitStep(d float64) SequenceStep {
+	return func(ctx *SequenceCtx) bool {
+		return ctx.Elapsed >= d
+	}
+}
+
+// CallStep calls fn and ends
+func CallStep(fn func()) SequenceStep {
+	return func(ctx *SequenceCtx) bool {
+		fn()
+		return true
+	}
+}
+
+// WaitUntilStep waits until cond returns true
+func WaitUntilStep(cond func() bool) SequenceStep {
+	return func(ctx *SequenceCtx) bool {
+		return cond()
+	}
+}
+
+// WaitEventStep waits until an engine event is dispatched (after the step
+// started)
+func WaitEventStep(name string) SequenceStep {
+	type waitEvent struct {
+		fired bool
+	}
+	return func(ctx *SequenceCtx) bool {
+		if ctx.First {
+			st := &waitEvent{}
+			ctx.State = st
+			engine := ctx.Ctx.Engine()
+			id := engine.AddEventListenerWithOptions(name, func(eventName string, e core.Event) {
+				st.fired = true
+			}, core.ListenerOptions{
+				// the state is read by the scheduler (main thread)
+				MainThread: true,
+			})
+			ctx.Defer(func() {
+				engine.RemoveEventListener(id)
+			})
+		}
+		return ctx.State.(*waitEvent).fired
+	}
+}
+
+type sequenceRun struct {
+	steps []SequenceStep
+	index int
+	ctx   SequenceCtx
+}
+
+func newSequenceRun(seq *Sequence) *sequenceRun {
+	return &sequenceRun{
+		steps: append([]SequenceStep(nil), seq.steps...),
+		ctx: SequenceCtx{
+			First: true,
+		},
+	}
+}
+
+// update runs the current step(s). It returns true when the sequence ends.
+// Steps that end immediately (like CallStep) don't wait for the next frame.
+func (r *sequenceRun) update(ctx core.UpdateCtx, w ecs.BaseWorld, e ecs.Entity, dt float64) bool {
+	r.ctx.Ctx = ctx
+	r.ctx.World = w
+	r.ctx.Entity = e
+	r.ctx.DT = dt
+	r.ctx.Elapsed += dt
+	for r.index < len(r.steps) {
+		if !r.steps[r.index](&r.ctx) {
+			r.ctx.First = false
+			return false
+		}
+		r.cleanup()
+		r.index++
+		r.ctx.First = true
+		r.ctx.State = nil
+		// the next step starts now
+		r.ctx.Elapsed = 0
+		r.ctx.DT = 0
+	}
+	return true
+}
+
+func (r *sequenceRun) cleanup() {
+	for _, fn := range r.ctx.cleanup {
+		fn()
+	}
+	r.ctx.cleanup = nil
+}
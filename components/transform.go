@@ -1,6 +1,8 @@
 package components
 
 import (
+	"math"
+
 	"github.com/gabstv/ecs/v2"
 	"github.com/gabstv/primen/core"
 	"github.com/gabstv/primen/core/debug"
@@ -15,6 +17,10 @@ type Transform struct {
 	angle  float64
 	scaleX float64
 	scaleY float64
+	// inactive transforms (and their children) are not updated or drawn
+	inactive bool
+	// hidden transforms (and their children) are not drawn
+	hidden bool
 
 	// priv
 	lastTick uint64
@@ -22,6 +28,9 @@ type Transform struct {
 	parent   *Transform
 	// calculated transform matrix (Ebiten)
 	m ebiten.GeoM
+	// transform matrix of the previous tick (used for interpolation)
+	pm   ebiten.GeoM
+	hasm bool
 	// copy the current world
 	// this is set once the component is created
 	w ecs.BaseWorld
@@ -36,15 +45,42 @@ func NewTransform(x, y float64) Transform {
 	}
 }
 
-func (t *Transform) SetParent(e ecs.Entity) bool {
+// SetParent sets the parent transform (0 removes the parent). It returns
+// ErrTransformNotFound if e doesn't have a Transform and ErrCyclicTransform
+// if e is the transform itself or one of its children.
+func (t *Transform) SetParent(e ecs.Entity) error {
+	if e == 0 {
+		t.pentity = 0
+		t.parent = nil
+		return nil
+	}
 	pt := GetTransformComponentData(t.w, e)
 	if pt == nil {
-		return false
+		return ErrTransformNotFound
+	}
+	for p := pt; p != nil; p = p.parent {
+		if p == t {
+			return ErrCyclicTransform
+		}
 	}
-	//TODO: check cyclic transform parenting
 	t.pentity = e
 	t.parent = pt
-	return true
+	return nil
+}
+
+// SetParentKeepWorld is like SetParent, but the local position, angle and
+// scale are changed so the global ones are kept.
+func (t *Transform) SetParentKeepWorld(e ecs.Entity) error {
+	pos := t.GlobalPos()
+	angle := t.GlobalAngle()
+	sx, sy := t.GlobalScale()
+	if err := t.SetParent(e); err != nil {
+		return err
+	}
+	t.SetGlobalScale(sx, sy)
+	t.SetGlobalAngle(angle)
+	t.SetGlobalPos(pos)
+	return nil
 }
 
 func (t *Transform) Parent() ecs.Entity {
@@ -134,10 +170,239 @@ func (t *Transform) ScaleY() float64 {
 	return t.scaleY
 }
 
+// SetActive activates or deactivates the transform. The entities of an
+// inactive transform (and of its children) are not updated or drawn.
+func (t *Transform) SetActive(v bool) *Transform {
+	t.inactive = !v
+	return t
+}
+
+// Active returns the active flag of the transform (see ActiveInHierarchy)
+func (t *Transform) Active() bool {
+	return !t.inactive
+}
+
+// ActiveInHierarchy returns true if the transform and all its parents are
+// active
+func (t *Transform) ActiveInHierarchy() bool {
+	for p := t; p != nil; p = p.parent {
+		if p.inactive {
+			return false
+		}
+	}
+	return true
+}
+
+// SetVisible shows or hides the transform. The entities of a hidden
+// transform (and of its children) are updated, but not drawn.
+func (t *Transform) SetVisible(v bool) *Transform {
+	t.hidden = !v
+	return t
+}
+
+// Visible returns the visible flag of the transform (see
+// VisibleInHierarchy)
+func (t *Transform) Visible() bool {
+	return !t.hidden
+}
+
+// VisibleInHierarchy returns true if the transform and all its parents are
+// active and visible
+func (t *Transform) VisibleInHierarchy() bool {
+	for p := t; p != nil; p = p.parent {
+		if p.inactive || p.hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// IsActive returns true if the Transform of an entity is active in the
+// hierarchy (or if the entity doesn't have a Transform)
+func IsActive(w ecs.BaseWorld, e ecs.Entity) bool {
+	if t := GetTransformComponentData(w, e); t != nil {
+		return t.ActiveInHierarchy()
+	}
+	return true
+}
+
+// IsVisible returns true if the Transform of an entity is visible in the
+// hierarchy (or if the entity doesn't have a Transform)
+func IsVisible(w ecs.BaseWorld, e ecs.Entity) bool {
+	if t := GetTransformComponentData(w, e); t != nil {
+		return t.VisibleInHierarchy()
+	}
+	return true
+}
+
+// LocalGeoM returns the transform matrix without the parents
+func (t *Transform) LocalGeoM() ebiten.GeoM {
+	m := ebiten.GeoM{}
+	m.Scale(t.scaleX, t.scaleY)
+	m.Rotate(t.angle)
+	m.Translate(t.x, t.y)
+	return m
+}
+
+// WorldGeoM calculates the transform matrix (with the parents). Unlike
+// GeoM, the result reflects the changes made after the last update of the
+// TransformSystem.
+func (t *Transform) WorldGeoM() ebiten.GeoM {
+	m := t.LocalGeoM()
+	if t.parent != nil {
+		m.Concat(t.parent.WorldGeoM())
+	}
+	return m
+}
+
+func (t *Transform) parentGeoM() ebiten.GeoM {
+	if t.parent == nil {
+		return ebiten.GeoM{}
+	}
+	return t.parent.WorldGeoM()
+}
+
+// GlobalPos returns the position in world space
+func (t *Transform) GlobalPos() geom.Vec {
+	m := t.WorldGeoM()
+	x, y := m.Apply(0, 0)
+	return geom.Vec{X: x, Y: y}
+}
+
+// SetGlobalPos sets the position in world space
+func (t *Transform) SetGlobalPos(p geom.Vec) *Transform {
+	m := t.parentGeoM()
+	if !m.IsInvertible() {
+		return t
+	}
+	m.Invert()
+	t.x, t.y = m.Apply(p.X, p.Y)
+	return t
+}
+
+// GlobalAngle returns the angle (in radians) in world space
+func (t *Transform) GlobalAngle() float64 {
+	angle, _, _ := decomposeGeoM(t.WorldGeoM())
+	return angle
+}
+
+// SetGlobalAngle sets the angle (in radians) in world space
+func (t *Transform) SetGlobalAngle(r float64) *Transform {
+	pangle, _, _ := decomposeGeoM(t.parentGeoM())
+	t.angle = r - pangle
+	return t
+}
+
+// GlobalScale returns the scale in world space. The result is only exact
+// if the parents don't combine rotations with non uniform scales.
+func (t *Transform) GlobalScale() (sx, sy float64) {
+	_, sx, sy = decomposeGeoM(t.WorldGeoM())
+	return
+}
+
+// SetGlobalScale sets the scale in world space (see GlobalScale)
+func (t *Transform) SetGlobalScale(sx, sy float64) *Transform {
+	_, psx, psy := decomposeGeoM(t.parentGeoM())
+	if psx == 0 || psy == 0 {
+		return t
+	}
+	t.scaleX, t.scaleY = sx/psx, sy/psy
+	return t
+}
+
+// LookAt rotates the transform so its X axis points to target (in world
+// space)
+func (t *Transform) LookAt(target geom.Vec) *Transform {
+	d := target.Sub(t.GlobalPos())
+	if d.IsZero() {
+		return t
+	}
+	return t.SetGlobalAngle(math.Atan2(d.Y, d.X))
+}
+
+// TransformPoint converts a point from the local space to the world space
+func (t *Transform) TransformPoint(p geom.Vec) geom.Vec {
+	m := t.WorldGeoM()
+	x, y := m.Apply(p.X, p.Y)
+	return geom.Vec{X: x, Y: y}
+}
+
+// InverseTransformPoint converts a point from the world space to the local
+// space
+func (t *Transform) InverseTransformPoint(p geom.Vec) geom.Vec {
+	m := t.WorldGeoM()
+	if !m.IsInvertible() {
+		return geom.Vec{}
+	}
+	m.Invert()
+	x, y := m.Apply(p.X, p.Y)
+	return geom.Vec{X: x, Y: y}
+}
+
+// decomposeGeoM extracts the rotation and the scale of a matrix
+func decomposeGeoM(m ebiten.GeoM) (angle, sx, sy float64) {
+	a, b := m.Element(0, 0), m.Element(0, 1)
+	c, d := m.Element(1, 0), m.Element(1, 1)
+	sx = math.Hypot(a, c)
+	if sx == 0 {
+		return 0, 0, math.Hypot(b, d)
+	}
+	angle = math.Atan2(c, a)
+	sy = (a*d - b*c) / sx
+	return
+}
+
 func (t *Transform) GeoM() ebiten.GeoM {
 	return t.m
 }
 
+// PrevGeoM returns the transform matrix calculated on the previous tick.
+func (t *Transform) PrevGeoM() ebiten.GeoM {
+	return t.pm
+}
+
+// InterpolatedGeoM blends the previous and the current transform matrices.
+// Use it with DrawCtx.Alpha() to render smooth movement when the engine
+// runs in fixed-timestep mode.
+//
+// The position, the angle (through the shortest arc), the scale and the
+// shear are interpolated separately, so rotating sprites keep their shape.
+func (t *Transform) InterpolatedGeoM(alpha float64) ebiten.GeoM {
+	if alpha >= 1 || t.pm == t.m {
+		return t.m
+	}
+	if alpha <= 0 {
+		return t.pm
+	}
+	pa, psx, psy, pk := decomposeShearGeoM(t.pm)
+	ca, csx, csy, ck := decomposeShearGeoM(t.m)
+	angle := pa + math.Remainder(ca-pa, 2*math.Pi)*alpha
+	sx := core.Lerpf(psx, csx, alpha)
+	sy := core.Lerpf(psy, csy, alpha)
+	k := core.Lerpf(pk, ck, alpha)
+	sin, cos := math.Sincos(angle)
+	g := ebiten.GeoM{}
+	g.SetElement(0, 0, cos*sx)
+	g.SetElement(0, 1, cos*k-sin*sy)
+	g.SetElement(1, 0, sin*sx)
+	g.SetElement(1, 1, sin*k+cos*sy)
+	g.SetElement(0, 2, core.Lerpf(t.pm.Element(0, 2), t.m.Element(0, 2), alpha))
+	g.SetElement(1, 2, core.Lerpf(t.pm.Element(1, 2), t.m.Element(1, 2), alpha))
+	return g
+}
+
+// decomposeShearGeoM is decomposeGeoM plus the shear (k) of the matrix, so
+// that R(angle) * [sx k; 0 sy] rebuilds the linear part of m
+func decomposeShearGeoM(m ebiten.GeoM) (angle, sx, sy, k float64) {
+	angle, sx, sy = decomposeGeoM(m)
+	if sx == 0 {
+		return 0, 0, m.Element(1, 1), m.Element(0, 1)
+	}
+	sin, cos := math.Sincos(angle)
+	k = cos*m.Element(0, 1) + sin*m.Element(1, 1)
+	return
+}
+
 //go:generate ecsgen -n Transform -p components -o transform_component.go --component-tpl --vars "UUID=45E8849D-7EA9-4CDC-8AB1-86DB8705C253" --vars "OnAdd=c.setupTransform(e)" --vars "OnResize=c.resized()" --vars "OnWillResize=c.willresize()" --vars "OnRemove=c.removed(e)"
 
 func (c *TransformComponent) setupTransform(e ecs.Entity) {
@@ -295,11 +560,16 @@ func resolveTransform(t *Transform, tick uint64) ebiten.GeoM {
 		return t.m
 	}
 	parent := resolveTransform(t.parent, tick)
+	t.pm = t.m
 	t.m = ebiten.GeoM{}
 	t.m.Scale(t.scaleX, t.scaleY)
 	t.m.Rotate(t.angle)
 	t.m.Translate(t.x, t.y)
 	t.m.Concat(parent)
+	if !t.hasm {
+		t.pm = t.m
+		t.hasm = true
+	}
 	t.lastTick = tick
 	return t.m
 }
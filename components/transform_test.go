@@ -0,0 +1,88 @@
+package components
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformWorld(t *testing.T) {
+	_, w := newTestWorld()
+	platform := newTestEntity(w, 0, "")
+	item := newTestEntity(w, 0, "")
+	bare := w.NewEntity()
+	pt := GetTransformComponentData(w, platform)
+	it := GetTransformComponentData(w, item)
+	pt.SetPos(geom.Vec{X: 100, Y: 50}).SetAngle(math.Pi/2).SetScale(2, 2)
+	it.SetPos(geom.Vec{X: 100, Y: 70})
+
+	// pick up the item without moving it
+	assert.NoError(t, it.SetParentKeepWorld(platform))
+	assert.True(t, it.GlobalPos().EqualsEpsilon2(geom.Vec{X: 100, Y: 70}, 1e-9))
+	assert.True(t, it.Pos().EqualsEpsilon2(geom.Vec{X: 10, Y: 0}, 1e-9))
+	sx, sy := it.GlobalScale()
+	assert.InDelta(t, 1, sx, 1e-9)
+	assert.InDelta(t, 1, sy, 1e-9)
+	assert.InDelta(t, 0, it.GlobalAngle(), 1e-9)
+
+	// ride the platform
+	pt.SetX(0)
+	assert.True(t, it.GlobalPos().EqualsEpsilon2(geom.Vec{X: 0, Y: 70}, 1e-9))
+	assert.True(t, it.InverseTransformPoint(geom.Vec{X: 0, Y: 70}).EqualsEpsilon2(geom.Vec{}, 1e-9))
+	assert.True(t, pt.TransformPoint(geom.Vec{X: 1, Y: 0}).EqualsEpsilon2(geom.Vec{X: 0, Y: 52}, 1e-9))
+
+	it.LookAt(geom.Vec{X: 10, Y: 70})
+	assert.InDelta(t, 0, it.GlobalAngle(), 1e-9)
+
+	// cycles and parents without a Transform
+	assert.Equal(t, ErrCyclicTransform, pt.SetParentKeepWorld(item))
+	assert.Equal(t, ErrCyclicTransform, pt.SetParent(platform))
+	assert.Equal(t, ErrTransformNotFound, pt.SetParent(bare))
+	assert.Equal(t, ecs.Entity(0), pt.Parent())
+
+	// drop it
+	assert.NoError(t, it.SetParentKeepWorld(0))
+	assert.Equal(t, ecs.Entity(0), it.Parent())
+	assert.True(t, it.Pos().EqualsEpsilon2(geom.Vec{X: 0, Y: 70}, 1e-9))
+}
+
+func TestTransformActive(t *testing.T) {
+	e, w := newTestWorld()
+	root := newTestEntity(w, 0, "")
+	child := newTestEntity(w, root, "")
+	fn := newTestEntity(w, child, "")
+	updates := 0
+	SetFunctionComponentData(w, fn, Function{
+		Update: func(ctx core.UpdateCtx, e ecs.Entity) { updates++ },
+	})
+	tr := func(e ecs.Entity) *Transform { return GetTransformComponentData(w, e) }
+
+	e.step(w, 1.0/60)
+	assert.Equal(t, 1, updates)
+
+	tr(root).SetActive(false)
+	assert.False(t, IsActive(w, fn))
+	assert.True(t, tr(fn).Active())
+	e.step(w, 1.0/60)
+	assert.Equal(t, 1, updates)
+
+	// the child is inactive itself, so it stays inactive
+	tr(child).SetActive(false)
+	tr(root).SetActive(true)
+	assert.False(t, IsActive(w, fn))
+	e.step(w, 1.0/60)
+	assert.Equal(t, 1, updates)
+	tr(child).SetActive(true)
+	e.step(w, 1.0/60)
+	assert.Equal(t, 2, updates)
+
+	// visibility is independent of the active flag
+	tr(root).SetVisible(false)
+	assert.False(t, IsVisible(w, fn))
+	assert.True(t, IsActive(w, fn))
+	assert.True(t, IsVisible(w, w.NewEntity()))
+}
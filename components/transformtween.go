@@ -121,6 +121,39 @@ func (t *TrTweening) SetDoneCallback(fn func(name string)) *TrTweening {
 	return t
 }
 
+// Tweens returns a copy of the tweens (sorted by name)
+func (t *TrTweening) Tweens() []TrTweenTuple {
+	out := make([]TrTweenTuple, len(t.tweens))
+	copy(out, t.tweens)
+	return out
+}
+
+func (t *TrTweening) SetEnabled(enabled bool) *TrTweening {
+	t.disabled = !enabled
+	return t
+}
+
+func (t *TrTweening) Enabled() bool {
+	return !t.disabled
+}
+
+// State returns the active tween and its progress (0-1)
+func (t *TrTweening) State() (active TrTweenTuple, progress float64, playing bool) {
+	return TrTweenTuple{
+		Name:  t.activename,
+		Tween: t.active,
+	}, t.t, t.playing
+}
+
+// RestoreState sets the active tween and its progress (used to restore
+// saved games)
+func (t *TrTweening) RestoreState(active TrTweenTuple, progress float64, playing bool) {
+	t.activename = active.Name
+	t.active = active.Tween
+	t.t = progress
+	t.playing = playing
+}
+
 //go:generate ecsgen -n TrTweening -p components -o trtweening_component.go --component-tpl --vars "UUID=7D0BCDA8-ABE8-41EB-BF23-7DDCB4152AFD"
 
 //go:generate ecsgen -n TrTweening -p components -o trtweening_system.go --system-tpl --vars "Priority=90" --vars "UUID=820C75AB-CAD6-47AE-A84C-1EC7BAECE328" --components "Transform" --components "TrTweening"
@@ -149,7 +182,7 @@ func (s *TrTweeningSystem) Update(ctx core.UpdateCtx) {
 		if v.TrTweening.disabled {
 			continue
 		}
-		if !v.TrTweening.playing {
+		if !v.TrTweening.playing || !IsActive(s.world, v.Entity) {
 			continue
 		}
 		done := false
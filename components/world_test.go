@@ -0,0 +1,62 @@
+package components
+
+import (
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core"
+)
+
+// testEngine implements the part of core.Engine used by the systems (the
+// events); the other methods panic.
+type testEngine struct {
+	core.Engine
+	events core.EventManager
+	frame  int64
+}
+
+func newTestWorld() (*testEngine, *core.GameWorld) {
+	e := &testEngine{}
+	w := core.NewWorld(e)
+	ecs.RegisterWorldDefaults(w)
+	return e, w
+}
+
+// step updates the systems of the world like the engine loop does
+func (e *testEngine) step(w *core.GameWorld, dt float64) {
+	e.frame++
+	e.events.Flush()
+	ctx := core.NewScaledUpdateCtx(e, e.frame, dt, 1/dt, w.AdvanceTime(dt))
+	w.EachSystem(func(s ecs.BaseSystem) bool {
+		s.(core.System).UpdatePriority(ctx)
+		return true
+	})
+	w.EachSystem(func(s ecs.BaseSystem) bool {
+		s.(core.System).Update(ctx)
+		return true
+	})
+}
+
+func (e *testEngine) stepN(w *core.GameWorld, n int, dt float64) {
+	for i := 0; i < n; i++ {
+		e.step(w, dt)
+	}
+}
+
+func (e *testEngine) AddEventListener(name string, fn core.EventFn) core.EventID {
+	return e.events.Register(name, fn)
+}
+
+func (e *testEngine) AddEventListenerWithOptions(name string, fn core.EventFn, opt core.ListenerOptions) core.EventID {
+	return e.events.RegisterWithOptions(name, fn, opt)
+}
+
+func (e *testEngine) RemoveEventListener(id core.EventID) bool {
+	return e.events.Deregister(id)
+}
+
+func (e *testEngine) RemoveEventListenersByOwner(owner interface{}) int {
+	return e.events.DeregisterOwner(owner)
+}
+
+func (e *testEngine) DispatchEvent(name string, data interface{}) {
+	e.events.Dispatch(name, e, data)
+}
@@ -0,0 +1,445 @@
+package primen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gabstv/primen/audio"
+	"github.com/gabstv/primen/core/logger"
+	"github.com/gabstv/primen/io"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Config is the set of engine settings that can be changed by the players
+// (saved as JSON):
+//
+//	{
+//	  "window": {"width": 800, "height": 600, "scale": 1, "fullscreen": false},
+//	  "tps": 60,
+//	  "scene": "main_menu",
+//	  "debug": {"fps": false, "tps": false, "profiler": false},
+//	  "volumes": {"master": 1, "music": 0.5},
+//	  "bindings": {"contexts": {"gameplay": {"actions": {"jump": [...]}}}}
+//	}
+//
+// The bindings use the format of input.Manager.SaveBindings.
+type Config struct {
+	Window   WindowConfig       `json:"window"`
+	TPS      int                `json:"tps,omitempty"`
+	Scene    string             `json:"scene,omitempty"`
+	Debug    DebugConfig        `json:"debug"`
+	Volumes  map[string]float64 `json:"volumes,omitempty"`
+	Bindings json.RawMessage    `json:"bindings,omitempty"`
+}
+
+// WindowConfig is the window section of a Config
+type WindowConfig struct {
+	Width           int     `json:"width,omitempty"`
+	Height          int     `json:"height,omitempty"`
+	Scale           float64 `json:"scale,omitempty"`
+	Title           string  `json:"title,omitempty"`
+	Fullscreen      bool    `json:"fullscreen"`
+	Resizable       bool    `json:"resizable"`
+	Maximized       bool    `json:"maximized"`
+	Floating        bool    `json:"floating"`
+	FixedResolution bool    `json:"fixed_resolution"`
+	FixedWidth      int     `json:"fixed_width,omitempty"`
+	FixedHeight     int     `json:"fixed_height,omitempty"`
+}
+
+// DebugConfig is the debug section of a Config
+type DebugConfig struct {
+	FPS      bool `json:"fps"`
+	TPS      bool `json:"tps"`
+	Profiler bool `json:"profiler"`
+}
+
+// DefaultConfig returns the settings used by NewEngine(nil)
+func DefaultConfig() *Config {
+	return &Config{
+		Window: WindowConfig{
+			Width:  800,
+			Height: 600,
+			Scale:  1,
+			Title:  "PRIMEN",
+		},
+		TPS: ebiten.DefaultTPS,
+	}
+}
+
+// LoadConfig reads a config file through fs. The settings not present in
+// the file keep their DefaultConfig values. If the file doesn't exist, the
+// DefaultConfig is returned.
+func LoadConfig(fs io.Filesystem, name string) (*Config, error) {
+	c := DefaultConfig()
+	if _, err := fs.Stat(name); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, err
+	}
+	raw, err := io.ReadFile(name, fs)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return c, nil
+}
+
+// Save writes the config to a file (of the OS filesystem)
+func (c *Config) Save(name string) error {
+	raw, err := c.marshal()
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (c *Config) marshal() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Clone returns a deep copy of the config
+func (c *Config) Clone() *Config {
+	c2 := *c
+	if c.Volumes != nil {
+		c2.Volumes = make(map[string]float64, len(c.Volumes))
+		for k, v := range c.Volumes {
+			c2.Volumes[k] = v
+		}
+	}
+	c2.Bindings = append(json.RawMessage(nil), c.Bindings...)
+	return &c2
+}
+
+// NewEngineInput returns the input of NewEngine with the settings of the
+// config. The engine writes the config back to savePath when it changes
+// (see Engine.UpdateConfig); an empty savePath disables the write back.
+func (c *Config) NewEngineInput(fs io.Filesystem, savePath string) *NewEngineInput {
+	return &NewEngineInput{
+		Width:           c.Window.Width,
+		Height:          c.Window.Height,
+		Scale:           c.Window.Scale,
+		Title:           c.Window.Title,
+		Fullscreen:      c.Window.Fullscreen,
+		Resizable:       c.Window.Resizable,
+		Maximized:       c.Window.Maximized,
+		Floating:        c.Window.Floating,
+		FixedResolution: c.Window.FixedResolution,
+		FixedWidth:      c.Window.FixedWidth,
+		FixedHeight:     c.Window.FixedHeight,
+		FS:              fs,
+		Scene:           c.Scene,
+		Config:          c.Clone(),
+		ConfigPath:      savePath,
+	}
+}
+
+// configField is a setting that can be overridden by a command-line flag or
+// by an environment variable
+type configField struct {
+	name  string // flag name; the env var is PREFIX_NAME ("-" is replaced by "_")
+	usage string
+	isb   bool
+	get   func(c *Config) string
+	set   func(c *Config, v string) error
+}
+
+func intConfigField(name, usage string, ptr func(c *Config) *int) configField {
+	return configField{
+		name:  name,
+		usage: usage,
+		get: func(c *Config) string {
+			return strconv.Itoa(*ptr(c))
+		},
+		set: func(c *Config, v string) error {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return err
+			}
+			*ptr(c) = i
+			return nil
+		},
+	}
+}
+
+func floatConfigField(name, usage string, ptr func(c *Config) *float64) configField {
+	return configField{
+		name:  name,
+		usage: usage,
+		get: func(c *Config) string {
+			return strconv.FormatFloat(*ptr(c), 'g', -1, 64)
+		},
+		set: func(c *Config, v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return err
+			}
+			*ptr(c) = f
+			return nil
+		},
+	}
+}
+
+func boolConfigField(name, usage string, ptr func(c *Config) *bool) configField {
+	return configField{
+		name:  name,
+		usage: usage,
+		isb:   true,
+		get: func(c *Config) string {
+			return strconv.FormatBool(*ptr(c))
+		},
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			*ptr(c) = b
+			return nil
+		},
+	}
+}
+
+func stringConfigField(name, usage string, ptr func(c *Config) *string) configField {
+	return configField{
+		name:  name,
+		usage: usage,
+		get: func(c *Config) string {
+			return *ptr(c)
+		},
+		set: func(c *Config, v string) error {
+			*ptr(c) = v
+			return nil
+		},
+	}
+}
+
+var configFields = []configField{
+	intConfigField("width", "window width", func(c *Config) *int { return &c.Window.Width }),
+	intConfigField("height", "window height", func(c *Config) *int { return &c.Window.Height }),
+	floatConfigField("scale", "pixel scale", func(c *Config) *float64 { return &c.Window.Scale }),
+	stringConfigField("title", "window title", func(c *Config) *string { return &c.Window.Title }),
+	boolConfigField("fullscreen", "start in fullscreen", func(c *Config) *bool { return &c.Window.Fullscreen }),
+	boolConfigField("resizable", "resizable window", func(c *Config) *bool { return &c.Window.Resizable }),
+	boolConfigField("maximized", "start window maximized", func(c *Config) *bool { return &c.Window.Maximized }),
+	boolConfigField("fixed-resolution", "fixed logical screen resolution", func(c *Config) *bool { return &c.Window.FixedResolution }),
+	intConfigField("fixed-width", "fixed logical screen width", func(c *Config) *int { return &c.Window.FixedWidth }),
+	intConfigField("fixed-height", "fixed logical screen height", func(c *Config) *int { return &c.Window.FixedHeight }),
+	intConfigField("tps", "max ticks per second", func(c *Config) *int { return &c.TPS }),
+	stringConfigField("scene", "starting scene", func(c *Config) *string { return &c.Scene }),
+	boolConfigField("debug-fps", "show the FPS", func(c *Config) *bool { return &c.Debug.FPS }),
+	boolConfigField("debug-tps", "show the TPS", func(c *Config) *bool { return &c.Debug.TPS }),
+	boolConfigField("debug-profiler", "show the profiler", func(c *Config) *bool { return &c.Debug.Profiler }),
+	{
+		name:  "volume",
+		usage: "bus volumes (master=0.8,music=0.5)",
+		get: func(c *Config) string {
+			keys := make([]string, 0, len(c.Volumes))
+			for k := range c.Volumes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for i, k := range keys {
+				keys[i] = k + "=" + strconv.FormatFloat(c.Volumes[k], 'g', -1, 64)
+			}
+			return strings.Join(keys, ",")
+		},
+		set: func(c *Config, v string) error {
+			for _, kv := range strings.Split(v, ",") {
+				if kv == "" {
+					continue
+				}
+				i := strings.Index(kv, "=")
+				if i == -1 {
+					return fmt.Errorf("invalid volume %q (bus=value)", kv)
+				}
+				f, err := strconv.ParseFloat(kv[i+1:], 64)
+				if err != nil {
+					return err
+				}
+				if c.Volumes == nil {
+					c.Volumes = make(map[string]float64)
+				}
+				c.Volumes[kv[:i]] = f
+			}
+			return nil
+		},
+	},
+}
+
+// ApplyEnv overrides the settings with environment variables. The name of a
+// variable is the prefix and the name of the flag of the setting, in upper
+// case ("PRIMEN_WIDTH", "PRIMEN_DEBUG_FPS", "PRIMEN_VOLUME").
+func (c *Config) ApplyEnv(prefix string) error {
+	for _, f := range configFields {
+		name := strings.ToUpper(strings.Replace(f.name, "-", "_", -1))
+		if prefix != "" {
+			name = strings.ToUpper(prefix) + "_" + name
+		}
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := f.set(c, v); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// BindFlags defines the command-line flags of the settings on fs
+// (-width, -fullscreen, -scene, -debug-fps, -volume master=0.5, ...).
+// The config is changed when fs is parsed.
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	for _, f := range configFields {
+		fs.Var(&configFlag{c: c, f: f}, f.name, f.usage)
+	}
+}
+
+// ApplyFlags overrides the settings with command-line flags (see BindFlags).
+// It returns an error if args has unknown flags; use BindFlags to mix the
+// engine flags with the flags of the game.
+func (c *Config) ApplyFlags(args []string) error {
+	fs := flag.NewFlagSet("primen", flag.ContinueOnError)
+	c.BindFlags(fs)
+	return fs.Parse(args)
+}
+
+type configFlag struct {
+	c *Config
+	f configField
+}
+
+func (v *configFlag) String() string {
+	if v.c == nil {
+		return ""
+	}
+	return v.f.get(v.c)
+}
+
+func (v *configFlag) Set(s string) error {
+	return v.f.set(v.c, s)
+}
+
+func (v *configFlag) IsBoolFlag() bool {
+	return v.f.isb
+}
+
+// Config returns a copy of the current settings of the engine (nil if the
+// engine was not created with a Config)
+func (e *engine) Config() *Config {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.config == nil {
+		return nil
+	}
+	c := e.config.Clone()
+	e.captureConfig(c)
+	return c
+}
+
+// UpdateConfig changes the settings of the engine (usually by an options
+// menu). The changes are applied on the next frame and the config is written
+// back to NewEngineInput.ConfigPath. fn is called without the engine lock,
+// so it can call the other engine methods (except UpdateConfig). Concurrent
+// calls are serialized, so no change is lost.
+func (e *engine) UpdateConfig(fn func(c *Config)) error {
+	e.configLock.Lock()
+	defer e.configLock.Unlock()
+	e.lock.Lock()
+	c := e.config
+	if c == nil {
+		c = DefaultConfig()
+	} else {
+		c = c.Clone()
+	}
+	e.lock.Unlock()
+	e.captureConfig(c)
+	fn(c)
+	e.lock.Lock()
+	e.config = c
+	e.lock.Unlock()
+	e.RunFn(func() {
+		e.applyConfig(c, false)
+	})
+	return e.saveConfig(c)
+}
+
+// captureConfig copies the settings that can be changed outside of the
+// config (bindings and volumes) to c.
+func (e *engine) captureConfig(c *Config) {
+	buf := new(bytes.Buffer)
+	if err := e.input.SaveBindings(buf); err == nil {
+		c.Bindings = json.RawMessage(bytes.TrimSpace(buf.Bytes()))
+	}
+	if vols := audio.BusVolumes(); len(vols) > 0 {
+		if c.Volumes == nil {
+			c.Volumes = make(map[string]float64, len(vols))
+		}
+		for k, v := range vols {
+			c.Volumes[k] = v
+		}
+	}
+}
+
+// applyConfig applies the settings that can change while the engine is
+// running (the window settings are only applied after boot).
+func (e *engine) applyConfig(c *Config, boot bool) {
+	if c.TPS > 0 {
+		ebiten.SetMaxTPS(c.TPS)
+	}
+	e.SetDebugFPS(c.Debug.FPS)
+	e.SetDebugTPS(c.Debug.TPS)
+	e.SetDebugProfiler(c.Debug.Profiler)
+	for bus, v := range c.Volumes {
+		audio.SetBusVolume(bus, v)
+	}
+	if len(c.Bindings) > 0 {
+		if err := e.input.LoadBindings(bytes.NewReader(c.Bindings)); err != nil {
+			e.log.Tag("input").Error("loading bindings failed", logger.Err(err))
+		}
+	}
+	if boot || e.headless {
+		return
+	}
+	if c.Window.Scale > 0 {
+		e.SetScreenScale(c.Window.Scale)
+	}
+	ebiten.SetFullscreen(c.Window.Fullscreen)
+	ebiten.SetWindowResizable(c.Window.Resizable)
+	ebiten.SetWindowFloating(c.Window.Floating)
+	if c.Window.Width > 0 && c.Window.Height > 0 && !c.Window.Fullscreen {
+		ebiten.SetWindowSize(c.Window.Width, c.Window.Height)
+	}
+}
+
+// saveConfig writes c to NewEngineInput.ConfigPath
+func (e *engine) saveConfig(c *Config) error {
+	if e.configPath == "" || c == nil {
+		return nil
+	}
+	return c.Save(e.configPath)
+}
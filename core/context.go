@@ -1,6 +1,8 @@
 package core
 
 import (
+	"github.com/gabstv/primen/core/input"
+	"github.com/gabstv/primen/core/logger"
 	"github.com/gabstv/primen/geom"
 )
 
@@ -14,16 +16,26 @@ type Context interface {
 // UpdateCtx is the context passed to every system update function.
 type UpdateCtx interface {
 	Context
+	// RealDT is the delta time without the world time scale
+	RealDT() float64
+	// TimeScale is the effective time scale of the world being updated
+	// (0 if the world is paused)
+	TimeScale() float64
 }
 
 // DrawCtx is the context passed to every system update function.
 type DrawCtx interface {
 	Context
 	Renderer() DrawManager
+	// Alpha is the interpolation factor (0 to 1) between the previous and
+	// the current fixed update. It is always 1 if the engine is not running
+	// in fixed-timestep mode.
+	Alpha() float64
 }
 
 type Engine interface {
-	AddModule(module Module, priority int)
+	AddModule(module Module, priority int) error
+	RemoveModule(module Module) error
 	NewWorld(priority int) World
 	NewWorldWithDefaults(priority int) World
 	RemoveWorld(w World)
@@ -35,17 +47,27 @@ type Engine interface {
 	Height() int
 	SizeVec() geom.Vec
 	AddEventListener(eventName string, fn EventFn) EventID
+	AddEventListenerWithOptions(eventName string, fn EventFn, opt ListenerOptions) EventID
+	AddCatchAllEventListener(fn EventFn) EventID
 	RemoveEventListener(id EventID) bool
+	RemoveEventListenersByOwner(owner interface{}) int
 	DispatchEvent(eventName string, data interface{})
+	SetEventDispatchMode(mode EventDispatchMode)
 	SetDebugTPS(v bool)
 	SetDebugFPS(v bool)
+	SetDebugProfiler(v bool)
 	SetScreenScale(scale float64)
+	Input() *input.Manager
+	Logger() *logger.Logger
 }
 
 type ctxt struct {
 	frame  int64
 	dt     float64
 	tps    float64
+	alpha  float64
+	realdt float64
+	scale  float64
 	r      DrawManager
 	engine Engine
 }
@@ -62,6 +84,18 @@ func (c *ctxt) TPS() float64 {
 	return c.tps
 }
 
+func (c *ctxt) RealDT() float64 {
+	return c.realdt
+}
+
+func (c *ctxt) TimeScale() float64 {
+	return c.scale
+}
+
+func (c *ctxt) Alpha() float64 {
+	return c.alpha
+}
+
 func (c *ctxt) Renderer() DrawManager {
 	return c.r
 }
@@ -71,19 +105,34 @@ func (c *ctxt) Engine() Engine {
 }
 
 func NewUpdateCtx(e Engine, frame int64, dt, tps float64) UpdateCtx {
+	return NewScaledUpdateCtx(e, frame, dt, tps, 1)
+}
+
+// NewScaledUpdateCtx creates an UpdateCtx where DT() is realdt * scale.
+func NewScaledUpdateCtx(e Engine, frame int64, realdt, tps, scale float64) UpdateCtx {
 	return &ctxt{
 		frame:  frame,
-		dt:     dt,
+		dt:     realdt * scale,
+		realdt: realdt,
+		scale:  scale,
 		tps:    tps,
 		engine: e,
 	}
 }
 
 func NewDrawCtx(e Engine, frame int64, dt, tps float64, m DrawManager) DrawCtx {
+	return NewInterpolatedDrawCtx(e, frame, dt, tps, 1, m)
+}
+
+// NewInterpolatedDrawCtx creates a DrawCtx with a custom interpolation alpha.
+func NewInterpolatedDrawCtx(e Engine, frame int64, dt, tps, alpha float64, m DrawManager) DrawCtx {
 	return &ctxt{
 		frame:  frame,
 		dt:     dt,
+		realdt: dt,
+		scale:  1,
 		tps:    tps,
+		alpha:  alpha,
 		r:      m,
 		engine: e,
 	}
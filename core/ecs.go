@@ -6,8 +6,11 @@ import (
 
 type GameWorld struct {
 	*ecs.World
-	e        Engine
-	disabled bool
+	e         Engine
+	disabled  bool
+	timeScale float64
+	paused    bool
+	hitstop   float64
 }
 
 func (w *GameWorld) Engine() Engine {
@@ -25,10 +28,67 @@ func (w *GameWorld) Enabled() bool {
 	return !w.disabled
 }
 
+// SetTimeScale sets the time scale of the world (1 = normal speed;
+// 0.5 = half speed; 0 = frozen). The delta time (ctx.DT()) of every system
+// of this world is multiplied by the time scale.
+func (w *GameWorld) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	w.timeScale = scale
+}
+
+// TimeScale gets the time scale of the world
+func (w *GameWorld) TimeScale() float64 {
+	return w.timeScale
+}
+
+// SetPaused pauses or resumes the world. The systems of a paused world still
+// run, but with a zero delta time.
+func (w *GameWorld) SetPaused(paused bool) {
+	w.paused = paused
+}
+
+// Paused gets if this world is paused or not
+func (w *GameWorld) Paused() bool {
+	return w.paused
+}
+
+// HitStop freezes the world for n seconds (of real time). If a hit-stop is
+// already active, the longest duration is used.
+func (w *GameWorld) HitStop(seconds float64) {
+	if seconds > w.hitstop {
+		w.hitstop = seconds
+	}
+}
+
+// EffectiveTimeScale is the time scale applied to the systems (0 if the
+// world is paused or in a hit-stop)
+func (w *GameWorld) EffectiveTimeScale() float64 {
+	if w.paused || w.hitstop > 0 {
+		return 0
+	}
+	return w.timeScale
+}
+
+// AdvanceTime is called by the engine on every frame. It counts down the
+// hit-stop timer and returns the effective time scale of this frame.
+func (w *GameWorld) AdvanceTime(realdt float64) float64 {
+	scale := w.EffectiveTimeScale()
+	if w.hitstop > 0 {
+		w.hitstop -= realdt
+		if w.hitstop < 0 {
+			w.hitstop = 0
+		}
+	}
+	return scale
+}
+
 func NewWorld(e Engine) *GameWorld {
 	return &GameWorld{
-		World: ecs.NewWorld().(*ecs.World),
-		e:     e,
+		World:     ecs.NewWorld().(*ecs.World),
+		e:         e,
+		timeScale: 1,
 	}
 }
 
@@ -37,6 +97,11 @@ type World interface {
 	Engine() Engine
 	SetEnabled(enabled bool)
 	Enabled() bool
+	SetTimeScale(scale float64)
+	TimeScale() float64
+	SetPaused(paused bool)
+	Paused() bool
+	HitStop(seconds float64)
 }
 
 type System interface {
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -9,74 +12,331 @@ type EventID int64
 
 type EventFn func(eventName string, e Event)
 
+// EventDispatchMode determines how (and when) the listeners are called
+type EventDispatchMode int
+
+const (
+	// EventDispatchQueued buffers the dispatched events. They are delivered
+	// synchronously (on the main thread) when Flush is called, in priority
+	// and registration order. This is the default mode.
+	EventDispatchQueued EventDispatchMode = 0
+	// EventDispatchAsync calls every listener on its own goroutine as soon
+	// as the event is dispatched (except the MainThread listeners). The
+	// listener order is not guaranteed.
+	EventDispatchAsync EventDispatchMode = 1
+)
+
+// ListenerOptions are the optional settings of an event listener
+type ListenerOptions struct {
+	// Priority of the listener. Higher priority listeners are called first.
+	// Listeners with the same priority are called in registration order.
+	Priority int
+	// Once removes the listener after its first call
+	Once bool
+	// Owner is an optional token used to remove a group of listeners
+	// at once (see EventManager.DeregisterOwner). It must be comparable
+	// (usually a pointer); listeners with a non comparable owner (a slice,
+	// a map, a func...) can only be removed by ID.
+	Owner interface{}
+	// MainThread listeners are always delivered by Flush (on the main
+	// thread), even in EventDispatchAsync mode. Use it for listeners that
+	// are not goroutine safe (script runtimes, ECS data).
+	MainThread bool
+}
+
+// EventCatchAll is the event name pattern that matches every event
+const EventCatchAll = "**"
+
 type EventManager struct {
 	nextid     int64
 	l          sync.RWMutex
-	registered map[EventID]Observer
-	list       map[string]map[EventID]Observer
+	registered map[EventID]*listener
+	list       map[string][]*listener
+	patterns   []*listener
+	mode       EventDispatchMode
+	ql         sync.Mutex
+	queue      []queuedEvent
+}
+
+type listener struct {
+	id         EventID
+	priority   int
+	once       bool
+	owner      interface{}
+	mainThread bool
+	ob         Observer
+}
+
+type queuedEvent struct {
+	name   string
+	engine Engine
+	data   interface{}
+	// observers are the listeners taken by an async dispatch (nil = all the
+	// listeners of the event at Flush time)
+	observers []Observer
+}
+
+// SetMode sets the dispatch mode of the event manager
+func (m *EventManager) SetMode(mode EventDispatchMode) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.mode = mode
 }
 
+// Mode returns the dispatch mode of the event manager
+func (m *EventManager) Mode() EventDispatchMode {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	return m.mode
+}
+
+// Register adds an event listener.
+//
+// Event names are namespaced by dots (e.g.: "player.hit.fire"). A listener
+// can use wildcards to match a namespace: "*" matches exactly one segment
+// ("player.*" matches "player.hit") and "**" matches one or more segments
+// ("player.**" matches "player.hit" and "player.hit.fire").
 func (m *EventManager) Register(eventName string, fn EventFn) EventID {
+	return m.RegisterWithOptions(eventName, fn, ListenerOptions{})
+}
+
+// RegisterCatchAll adds a listener that is called on every event.
+func (m *EventManager) RegisterCatchAll(fn EventFn) EventID {
+	return m.RegisterWithOptions(EventCatchAll, fn, ListenerOptions{})
+}
+
+// RegisterWithOptions registers a listener with a priority, an owner and/or
+// as a one-shot listener.
+func (m *EventManager) RegisterWithOptions(eventName string, fn EventFn, opt ListenerOptions) EventID {
 	m.l.Lock()
 	defer m.l.Unlock()
 	if m.registered == nil {
-		m.registered = make(map[EventID]Observer)
+		m.registered = make(map[EventID]*listener)
 	}
 	if m.list == nil {
-		m.list = make(map[string]map[EventID]Observer)
-	}
-	if m.list[eventName] == nil {
-		m.list[eventName] = make(map[EventID]Observer)
+		m.list = make(map[string][]*listener)
 	}
 	id := EventID(atomic.AddInt64(&m.nextid, 1))
-	o := &observer{
-		name: eventName,
-		fn:   fn,
+	l := &listener{
+		id:         id,
+		priority:   opt.Priority,
+		once:       opt.Once,
+		owner:      opt.Owner,
+		mainThread: opt.MainThread,
+		ob: &observer{
+			name: eventName,
+			fn:   fn,
+		},
+	}
+	m.registered[id] = l
+	if isEventPattern(eventName) {
+		m.patterns = append(m.patterns, l)
+		return id
 	}
-	m.list[eventName][id] = o
-	m.registered[id] = o
+	list := append(m.list[eventName], l)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].priority > list[j].priority
+	})
+	m.list[eventName] = list
 	return id
 }
 
 func (m *EventManager) Deregister(id EventID) bool {
-	ok := false
-	var ob Observer
-	m.l.RLock()
-	ob, ok = m.registered[id]
-	m.l.RUnlock()
+	m.l.Lock()
+	defer m.l.Unlock()
+	return m.deregister(id)
+}
+
+func (m *EventManager) deregister(id EventID) bool {
+	l, ok := m.registered[id]
 	if !ok {
 		return false
 	}
-	m.l.Lock()
-	defer m.l.Unlock()
 	delete(m.registered, id)
-	delete(m.list[ob.EventName()], id)
+	name := l.ob.EventName()
+	if isEventPattern(name) {
+		for i, v := range m.patterns {
+			if v.id == id {
+				m.patterns = m.patterns[:i+copy(m.patterns[i:], m.patterns[i+1:])]
+				break
+			}
+		}
+		return true
+	}
+	list := m.list[name]
+	for i, v := range list {
+		if v.id == id {
+			list = list[:i+copy(list[i:], list[i+1:])]
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(m.list, name)
+	} else {
+		m.list[name] = list
+	}
 	return true
 }
 
+// DeregisterOwner removes all listeners registered with the owner token.
+// It returns the number of removed listeners (0 if the owner is not
+// comparable).
+func (m *EventManager) DeregisterOwner(owner interface{}) int {
+	if owner == nil || !reflect.TypeOf(owner).Comparable() {
+		return 0
+	}
+	m.l.Lock()
+	defer m.l.Unlock()
+	ids := make([]EventID, 0)
+	for id, l := range m.registered {
+		if l.owner == owner {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		m.deregister(id)
+	}
+	return len(ids)
+}
+
+// Dispatch queues the event (EventDispatchQueued) or calls every listener on
+// a new goroutine (EventDispatchAsync). The MainThread listeners are always
+// queued.
 func (m *EventManager) Dispatch(eventName string, engine Engine, data interface{}) {
-	m.l.RLock()
-	if m.list == nil {
-		// no listeners
-		m.l.RUnlock()
+	if m.Mode() == EventDispatchAsync {
+		var queued []Observer
+		for _, v := range m.take(eventName) {
+			if v.mainThread {
+				queued = append(queued, v.ob)
+				continue
+			}
+			go v.ob.OnEvent(Event{
+				Name:   eventName,
+				Engine: engine,
+				Data:   data,
+			})
+		}
+		if len(queued) > 0 {
+			m.enqueue(queuedEvent{
+				name:      eventName,
+				engine:    engine,
+				data:      data,
+				observers: queued,
+			})
+		}
 		return
 	}
-	if m.list[eventName] == nil {
+	m.enqueue(queuedEvent{
+		name:   eventName,
+		engine: engine,
+		data:   data,
+	})
+}
+
+func (m *EventManager) enqueue(qe queuedEvent) {
+	m.ql.Lock()
+	m.queue = append(m.queue, qe)
+	m.ql.Unlock()
+}
+
+// Flush delivers all queued events on the caller's goroutine. Events
+// dispatched by the listeners while flushing are delivered on the next Flush.
+//
+// The engine calls Flush once per frame, before the modules and systems are
+// updated.
+func (m *EventManager) Flush() {
+	m.ql.Lock()
+	q := m.queue
+	m.queue = nil
+	m.ql.Unlock()
+	for _, qe := range q {
+		obs := qe.observers
+		if obs == nil {
+			for _, v := range m.take(qe.name) {
+				obs = append(obs, v.ob)
+			}
+		}
+		for _, v := range obs {
+			v.OnEvent(Event{
+				Name:   qe.name,
+				Engine: qe.engine,
+				Data:   qe.data,
+			})
+		}
+	}
+}
+
+// take returns a copy of the listeners of an event (including the wildcard
+// listeners), sorted by priority, and deregisters the one-shot listeners.
+func (m *EventManager) take(eventName string) []*listener {
+	m.l.Lock()
+	defer m.l.Unlock()
+	src := m.list[eventName]
+	if len(m.patterns) > 0 {
+		src = append(make([]*listener, 0, len(src)+len(m.patterns)), src...)
+		for _, v := range m.patterns {
+			if MatchEventName(v.ob.EventName(), eventName) {
+				src = append(src, v)
+			}
+		}
+		sort.SliceStable(src, func(i, j int) bool {
+			if src[i].priority == src[j].priority {
+				return src[i].id < src[j].id
+			}
+			return src[i].priority > src[j].priority
+		})
+	}
+	if len(src) == 0 {
 		// no listeners
-		m.l.RUnlock()
-		return
+		return nil
 	}
-	list := make([]Observer, 0, 8)
-	for _, ob := range m.list[eventName] {
-		list = append(list, ob)
+	list := make([]*listener, 0, len(src))
+	once := make([]EventID, 0)
+	for _, v := range src {
+		list = append(list, v)
+		if v.once {
+			once = append(once, v.id)
+		}
 	}
-	m.l.RUnlock()
-	for _, v := range list {
-		go v.OnEvent(Event{
-			Engine: engine,
-			Data:   data,
-		})
+	for _, id := range once {
+		m.deregister(id)
 	}
+	return list
+}
+
+func isEventPattern(name string) bool {
+	return strings.Contains(name, "*")
+}
+
+// MatchEventName reports whether an event name matches a listener pattern.
+// See EventManager.Register for the wildcard rules.
+func MatchEventName(pattern, name string) bool {
+	if pattern == name {
+		return true
+	}
+	return matchEventSegments(strings.Split(pattern, "."), strings.Split(name, "."))
+}
+
+func matchEventSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if len(name) == 0 {
+		return false
+	}
+	switch pattern[0] {
+	case "**":
+		// one or more segments
+		for i := 1; i <= len(name); i++ {
+			if matchEventSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*", name[0]:
+		return matchEventSegments(pattern[1:], name[1:])
+	}
+	return false
 }
 
 type Observer interface {
@@ -94,12 +354,19 @@ func (o *observer) EventName() string {
 }
 
 func (o *observer) OnEvent(e Event) {
-	if o.fn != nil {
-		o.fn(o.name, e)
+	if o.fn == nil {
+		return
+	}
+	if e.Name != "" {
+		// the actual event name (the observer name may be a pattern)
+		o.fn(e.Name, e)
+		return
 	}
+	o.fn(o.name, e)
 }
 
 type Event struct {
+	Name   string
 	Engine Engine
 	Data   interface{}
 }
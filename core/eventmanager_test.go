@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventManagerQueued(t *testing.T) {
+	m := &EventManager{}
+	calls := make([]string, 0)
+	m.Register("hit", func(eventName string, e Event) {
+		calls = append(calls, "a")
+	})
+	m.RegisterWithOptions("hit", func(eventName string, e Event) {
+		calls = append(calls, "b")
+	}, ListenerOptions{Priority: 10})
+	m.RegisterWithOptions("hit", func(eventName string, e Event) {
+		calls = append(calls, "once")
+	}, ListenerOptions{Once: true})
+	id := m.Register("hit", func(eventName string, e Event) {
+		calls = append(calls, "c")
+	})
+	m.Dispatch("hit", nil, 1)
+	assert.Empty(t, calls)
+	m.Flush()
+	assert.Equal(t, []string{"b", "a", "once", "c"}, calls)
+	assert.True(t, m.Deregister(id))
+	assert.False(t, m.Deregister(id))
+	calls = calls[:0]
+	m.Dispatch("hit", nil, 2)
+	m.Flush()
+	assert.Equal(t, []string{"b", "a"}, calls)
+}
+
+func TestEventManagerFlushReentrant(t *testing.T) {
+	m := &EventManager{}
+	n := 0
+	m.Register("ping", func(eventName string, e Event) {
+		n++
+		m.Dispatch("ping", nil, nil)
+	})
+	m.Dispatch("ping", nil, nil)
+	m.Flush()
+	assert.Equal(t, 1, n)
+	m.Flush()
+	assert.Equal(t, 2, n)
+}
+
+func TestMatchEventName(t *testing.T) {
+	assert.True(t, MatchEventName("player.hit", "player.hit"))
+	assert.True(t, MatchEventName("player.*", "player.hit"))
+	assert.False(t, MatchEventName("player.*", "player.hit.fire"))
+	assert.False(t, MatchEventName("player.*", "player"))
+	assert.True(t, MatchEventName("player.**", "player.hit"))
+	assert.True(t, MatchEventName("player.**", "player.hit.fire"))
+	assert.True(t, MatchEventName("player.*.fire", "player.hit.fire"))
+	assert.True(t, MatchEventName("**.fire", "player.hit.fire"))
+	assert.False(t, MatchEventName("enemy.**", "player.hit"))
+	assert.True(t, MatchEventName(EventCatchAll, "anything"))
+}
+
+func TestEventManagerWildcards(t *testing.T) {
+	m := &EventManager{}
+	owner := &struct{ n int }{}
+	names := make([]string, 0)
+	m.RegisterWithOptions("player.*", func(eventName string, e Event) {
+		names = append(names, "1:"+eventName)
+	}, ListenerOptions{Owner: owner})
+	m.RegisterWithOptions("player.**", func(eventName string, e Event) {
+		names = append(names, "2:"+eventName)
+	}, ListenerOptions{Owner: owner})
+	m.Register("player.hit", func(eventName string, e Event) {
+		names = append(names, "3:"+eventName)
+	})
+	m.RegisterCatchAll(func(eventName string, e Event) {
+		names = append(names, "4:"+eventName)
+	})
+	m.Dispatch("player.hit", nil, nil)
+	m.Dispatch("player.hit.fire", nil, nil)
+	m.Flush()
+	assert.Equal(t, []string{
+		"1:player.hit", "2:player.hit", "3:player.hit", "4:player.hit",
+		"2:player.hit.fire", "4:player.hit.fire",
+	}, names)
+	// non comparable owners don't panic
+	m.RegisterWithOptions("player.hit", func(eventName string, e Event) {}, ListenerOptions{Owner: []int{1}})
+	assert.Equal(t, 0, m.DeregisterOwner([]int{1}))
+	assert.Equal(t, 0, m.DeregisterOwner(struct{ s []int }{}))
+	assert.Equal(t, 2, m.DeregisterOwner(owner))
+	names = names[:0]
+	m.Dispatch("player.hit", nil, nil)
+	m.Flush()
+	assert.Equal(t, []string{"3:player.hit", "4:player.hit"}, names)
+}
+
+func TestEventManagerAsyncMainThread(t *testing.T) {
+	m := &EventManager{}
+	m.SetMode(EventDispatchAsync)
+	async := make(chan string, 1)
+	m.Register("hit", func(eventName string, e Event) {
+		async <- eventName
+	})
+	names := make([]string, 0)
+	m.RegisterWithOptions("*", func(eventName string, e Event) {
+		names = append(names, eventName)
+	}, ListenerOptions{MainThread: true})
+	m.Dispatch("hit", nil, nil)
+	assert.Equal(t, "hit", <-async)
+	assert.Equal(t, 0, len(names))
+	m.Flush()
+	assert.Equal(t, []string{"hit"}, names)
+}
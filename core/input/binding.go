@@ -0,0 +1,232 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Device is the kind of input of a Binding
+type Device int
+
+// Devices
+const (
+	Keyboard Device = iota
+	Mouse
+	GamepadButton
+	GamepadAxis
+)
+
+var deviceNames = [...]string{
+	Keyboard:      "key",
+	Mouse:         "mouse",
+	GamepadButton: "button",
+	GamepadAxis:   "axis",
+}
+
+func (d Device) String() string {
+	if d < 0 || int(d) >= len(deviceNames) {
+		return "device" + strconv.Itoa(int(d))
+	}
+	return deviceNames[d]
+}
+
+// AnyGamepad matches the input of all connected gamepads
+const AnyGamepad = -1
+
+// Binding is a physical input bound to an action or axis.
+//
+// Bindings are saved as text:
+//
+//	key:Space        keyboard key
+//	key:A*-1         keyboard key with scale -1 (the value when bound to an axis)
+//	mouse:left       mouse button (left, right or middle)
+//	button:0         gamepad button of any gamepad
+//	button@1:0       gamepad button of the gamepad 1
+//	axis:0           gamepad axis of any gamepad
+//	axis@0:1*-1      inverted gamepad axis 1 of the gamepad 0
+type Binding struct {
+	Device Device
+	// Code is the key, mouse button, gamepad button or gamepad axis
+	Code int
+	// Gamepad is the gamepad ID (AnyGamepad matches all gamepads)
+	Gamepad int
+	// Scale multiplies the value of the binding when it's used by an axis.
+	// A gamepad axis bound to an action is only pressed when the value has
+	// the same sign of the scale (and it's outside of the dead zone).
+	Scale float64
+}
+
+// Key binds a keyboard key
+func Key(key ebiten.Key) Binding {
+	return Binding{Device: Keyboard, Code: int(key), Gamepad: AnyGamepad, Scale: 1}
+}
+
+// MouseButton binds a mouse button
+func MouseButton(button ebiten.MouseButton) Binding {
+	return Binding{Device: Mouse, Code: int(button), Gamepad: AnyGamepad, Scale: 1}
+}
+
+// PadButton binds a button of a gamepad (or AnyGamepad)
+func PadButton(gamepad int, button ebiten.GamepadButton) Binding {
+	return Binding{Device: GamepadButton, Code: int(button), Gamepad: gamepad, Scale: 1}
+}
+
+// PadAxis binds an axis of a gamepad (or AnyGamepad)
+func PadAxis(gamepad, axis int) Binding {
+	return Binding{Device: GamepadAxis, Code: axis, Gamepad: gamepad, Scale: 1}
+}
+
+// WithScale returns a copy of the binding with a different scale
+func (b Binding) WithScale(scale float64) Binding {
+	b.Scale = scale
+	return b
+}
+
+// Negative returns a copy of the binding with the scale inverted
+func (b Binding) Negative() Binding {
+	b.Scale = -b.Scale
+	return b
+}
+
+func (b Binding) String() string {
+	sb := new(strings.Builder)
+	sb.WriteString(b.Device.String())
+	if (b.Device == GamepadButton || b.Device == GamepadAxis) && b.Gamepad != AnyGamepad {
+		sb.WriteString("@")
+		sb.WriteString(strconv.Itoa(b.Gamepad))
+	}
+	sb.WriteString(":")
+	switch b.Device {
+	case Keyboard:
+		sb.WriteString(ebiten.Key(b.Code).String())
+	case Mouse:
+		sb.WriteString(mouseButtonName(ebiten.MouseButton(b.Code)))
+	default:
+		sb.WriteString(strconv.Itoa(b.Code))
+	}
+	if b.Scale != 1 {
+		sb.WriteString("*")
+		sb.WriteString(strconv.FormatFloat(b.Scale, 'g', -1, 64))
+	}
+	return sb.String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (b Binding) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *Binding) UnmarshalText(text []byte) error {
+	v, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// ParseBinding parses the text representation of a Binding
+func ParseBinding(v string) (Binding, error) {
+	b := Binding{
+		Gamepad: AnyGamepad,
+		Scale:   1,
+	}
+	i := strings.Index(v, ":")
+	if i < 1 {
+		return b, fmt.Errorf("input: invalid binding '%s'", v)
+	}
+	dev, code := v[:i], v[i+1:]
+	if j := strings.Index(dev, "@"); j >= 0 {
+		id, err := strconv.Atoi(dev[j+1:])
+		if err != nil {
+			return b, fmt.Errorf("input: invalid gamepad of binding '%s'", v)
+		}
+		b.Gamepad = id
+		dev = dev[:j]
+	}
+	if j := strings.LastIndex(code, "*"); j >= 0 {
+		scale, err := strconv.ParseFloat(code[j+1:], 64)
+		if err != nil {
+			return b, fmt.Errorf("input: invalid scale of binding '%s'", v)
+		}
+		b.Scale = scale
+		code = code[:j]
+	}
+	switch dev {
+	case "key":
+		k, ok := keyByName(code)
+		if !ok {
+			return b, fmt.Errorf("input: unknown key '%s'", code)
+		}
+		b.Device = Keyboard
+		b.Code = int(k)
+	case "mouse":
+		mb, ok := mouseButtonByName(code)
+		if !ok {
+			return b, fmt.Errorf("input: unknown mouse button '%s'", code)
+		}
+		b.Device = Mouse
+		b.Code = int(mb)
+	case "button", "axis":
+		n, err := strconv.Atoi(code)
+		if err != nil || n < 0 {
+			return b, fmt.Errorf("input: invalid %s '%s'", dev, code)
+		}
+		b.Device = GamepadButton
+		if dev == "axis" {
+			b.Device = GamepadAxis
+		}
+		b.Code = n
+	default:
+		return b, fmt.Errorf("input: unknown device '%s'", dev)
+	}
+	if b.Device != GamepadButton && b.Device != GamepadAxis && b.Gamepad != AnyGamepad {
+		return b, fmt.Errorf("input: binding '%s' is not a gamepad input", v)
+	}
+	return b, nil
+}
+
+var (
+	keyNames     map[string]ebiten.Key
+	keyNamesOnce sync.Once
+)
+
+func keyByName(name string) (ebiten.Key, bool) {
+	keyNamesOnce.Do(func() {
+		keyNames = make(map[string]ebiten.Key)
+		for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+			keyNames[strings.ToLower(k.String())] = k
+		}
+	})
+	k, ok := keyNames[strings.ToLower(name)]
+	return k, ok
+}
+
+func mouseButtonName(b ebiten.MouseButton) string {
+	switch b {
+	case ebiten.MouseButtonLeft:
+		return "left"
+	case ebiten.MouseButtonRight:
+		return "right"
+	case ebiten.MouseButtonMiddle:
+		return "middle"
+	}
+	return strconv.Itoa(int(b))
+}
+
+func mouseButtonByName(name string) (ebiten.MouseButton, bool) {
+	switch strings.ToLower(name) {
+	case "left":
+		return ebiten.MouseButtonLeft, true
+	case "right":
+		return ebiten.MouseButtonRight, true
+	case "middle":
+		return ebiten.MouseButtonMiddle, true
+	}
+	return 0, false
+}
@@ -0,0 +1,145 @@
+package input
+
+import (
+	"sort"
+	"sync"
+)
+
+// Context is a named set of action and axis bindings (e.g. "gameplay",
+// "menu"). Contexts are pushed and popped on the Manager; the actions are
+// resolved from the top context to the bottom.
+type Context struct {
+	name        string
+	l           sync.RWMutex
+	passThrough bool
+	actions     map[string][]Binding
+	axes        map[string][]Binding
+}
+
+func newContext(name string) *Context {
+	return &Context{
+		name:    name,
+		actions: make(map[string][]Binding),
+		axes:    make(map[string][]Binding),
+	}
+}
+
+// Name of the context
+func (c *Context) Name() string {
+	return c.name
+}
+
+// SetPassThrough controls what happens to the actions and axes that are not
+// bound by this context when it's active. If true, they are resolved by the
+// contexts below it. If false (default), they are blocked.
+func (c *Context) SetPassThrough(v bool) *Context {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.passThrough = v
+	return c
+}
+
+// PassThrough (see SetPassThrough)
+func (c *Context) PassThrough() bool {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return c.passThrough
+}
+
+// BindAction adds bindings to an action
+func (c *Context) BindAction(action string, bindings ...Binding) *Context {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.actions[action] = append(c.actions[action], bindings...)
+	return c
+}
+
+// BindAxis adds bindings to an axis
+func (c *Context) BindAxis(axis string, bindings ...Binding) *Context {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.axes[axis] = append(c.axes[axis], bindings...)
+	return c
+}
+
+// RebindAction replaces the bindings of an action
+func (c *Context) RebindAction(action string, bindings ...Binding) *Context {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.actions[action] = append([]Binding(nil), bindings...)
+	return c
+}
+
+// RebindAxis replaces the bindings of an axis
+func (c *Context) RebindAxis(axis string, bindings ...Binding) *Context {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.axes[axis] = append([]Binding(nil), bindings...)
+	return c
+}
+
+// UnbindAction removes an action from the context
+func (c *Context) UnbindAction(action string) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	delete(c.actions, action)
+}
+
+// UnbindAxis removes an axis from the context
+func (c *Context) UnbindAxis(axis string) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	delete(c.axes, axis)
+}
+
+// ActionBindings returns a copy of the bindings of an action
+func (c *Context) ActionBindings(action string) []Binding {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return append([]Binding(nil), c.actions[action]...)
+}
+
+// AxisBindings returns a copy of the bindings of an axis
+func (c *Context) AxisBindings(axis string) []Binding {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return append([]Binding(nil), c.axes[axis]...)
+}
+
+// Actions returns the (sorted) action names of the context
+func (c *Context) Actions() []string {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return sortedKeys(c.actions)
+}
+
+// Axes returns the (sorted) axis names of the context
+func (c *Context) Axes() []string {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return sortedKeys(c.axes)
+}
+
+// lookup returns the bindings of an action or axis, and if this context
+// has it
+func (c *Context) lookup(name string, axis bool) ([]Binding, bool) {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	var b []Binding
+	var ok bool
+	if axis {
+		b, ok = c.axes[name]
+	} else {
+		b, ok = c.actions[name]
+	}
+	return b, ok
+}
+
+func sortedKeys(m map[string][]Binding) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,368 @@
+// Package input maps the physical input (keys, mouse buttons, gamepad
+// buttons and axes) to named actions ("jump") and axes ("move_x").
+//
+// The bindings are grouped in contexts (e.g. "gameplay", "menu") that are
+// pushed and popped on a Manager. The Manager polls a Source once per update
+// frame, so the queries (Pressed, JustPressed, JustReleased, Axis) are
+// consistent during the whole frame.
+package input
+
+import (
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// DefaultDeadZone is the default dead zone of the gamepad axes
+const DefaultDeadZone = 0.2
+
+type actionState struct {
+	pressed bool
+	prev    bool
+	frames  int64
+}
+
+// Manager resolves the actions and axes of the active contexts
+type Manager struct {
+	l         sync.RWMutex
+	src       Source
+	contexts  map[string]*Context
+	stack     []*Context
+	deadZone  float64
+	deadZones map[string]float64
+	actions   map[string]*actionState
+	axes      map[string]float64
+}
+
+// New creates a new Manager that reads the input from src
+func New(src Source) *Manager {
+	return &Manager{
+		src:       src,
+		contexts:  make(map[string]*Context),
+		deadZone:  DefaultDeadZone,
+		deadZones: make(map[string]float64),
+		actions:   make(map[string]*actionState),
+		axes:      make(map[string]float64),
+	}
+}
+
+// Source returns the current input source
+func (m *Manager) Source() Source {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	return m.src
+}
+
+// SetSource replaces the input source
+func (m *Manager) SetSource(src Source) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.src = src
+}
+
+// Context returns the context with this name. The context is created if it
+// doesn't exist.
+func (m *Manager) Context(name string) *Context {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return m.context(name)
+}
+
+func (m *Manager) context(name string) *Context {
+	c, ok := m.contexts[name]
+	if !ok {
+		c = newContext(name)
+		m.contexts[name] = c
+	}
+	return c
+}
+
+// Contexts returns all the contexts (active or not)
+func (m *Manager) Contexts() []*Context {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	out := make([]*Context, 0, len(m.contexts))
+	for _, c := range m.contexts {
+		out = append(out, c)
+	}
+	return out
+}
+
+// PushContext activates a context on top of the active contexts
+func (m *Manager) PushContext(name string) *Context {
+	m.l.Lock()
+	defer m.l.Unlock()
+	c := m.context(name)
+	m.stack = append(m.stack, c)
+	return c
+}
+
+// PopContext deactivates the top context. It returns nil if there are no
+// active contexts.
+func (m *Manager) PopContext() *Context {
+	m.l.Lock()
+	defer m.l.Unlock()
+	if len(m.stack) < 1 {
+		return nil
+	}
+	c := m.stack[len(m.stack)-1]
+	m.stack[len(m.stack)-1] = nil
+	m.stack = m.stack[:len(m.stack)-1]
+	return c
+}
+
+// ActiveContexts returns the names of the active contexts (the last one is
+// on top)
+func (m *Manager) ActiveContexts() []string {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	names := make([]string, len(m.stack))
+	for i, c := range m.stack {
+		names[i] = c.name
+	}
+	return names
+}
+
+// SetDeadZone sets the default dead zone of the gamepad axes [0, 1)
+func (m *Manager) SetDeadZone(v float64) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.deadZone = v
+}
+
+// SetAxisDeadZone sets the dead zone of a named axis (overrides the default
+// dead zone)
+func (m *Manager) SetAxisDeadZone(axis string, v float64) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.deadZones[axis] = v
+}
+
+// Update polls the source. The engine calls it once per update frame.
+func (m *Manager) Update() {
+	m.l.Lock()
+	defer m.l.Unlock()
+	names := make(map[string]struct{}, len(m.actions))
+	for k := range m.actions {
+		names[k] = struct{}{}
+	}
+	axes := make(map[string]struct{}, len(m.axes))
+	for k := range m.axes {
+		axes[k] = struct{}{}
+	}
+	for _, c := range m.stack {
+		c.l.RLock()
+		for k := range c.actions {
+			names[k] = struct{}{}
+		}
+		for k := range c.axes {
+			axes[k] = struct{}{}
+		}
+		c.l.RUnlock()
+	}
+	for name := range names {
+		st := m.actions[name]
+		if st == nil {
+			st = &actionState{}
+			m.actions[name] = st
+		}
+		st.prev = st.pressed
+		st.pressed = false
+		for _, b := range m.resolve(name, false) {
+			if m.bindingPressed(b, m.deadZone) {
+				st.pressed = true
+				break
+			}
+		}
+		if st.pressed {
+			st.frames++
+		} else {
+			st.frames = 0
+		}
+	}
+	for name := range axes {
+		dz, ok := m.deadZones[name]
+		if !ok {
+			dz = m.deadZone
+		}
+		v := 0.0
+		for _, b := range m.resolve(name, true) {
+			v += m.bindingValue(b, dz)
+		}
+		m.axes[name] = math.Max(-1, math.Min(1, v))
+	}
+}
+
+// resolve finds the bindings of an action or axis (from the top context to
+// the bottom)
+func (m *Manager) resolve(name string, axis bool) []Binding {
+	for i := len(m.stack) - 1; i >= 0; i-- {
+		c := m.stack[i]
+		if b, ok := c.lookup(name, axis); ok {
+			return b
+		}
+		if !c.PassThrough() {
+			return nil
+		}
+	}
+	return nil
+}
+
+// bindingPressed returns true if the input of the binding is pressed. A
+// gamepad axis is pressed if it's outside of the dead zone and it points to
+// the same direction of the binding scale.
+func (m *Manager) bindingPressed(b Binding, deadZone float64) bool {
+	if m.src == nil {
+		return false
+	}
+	switch b.Device {
+	case Keyboard:
+		return m.src.IsKeyPressed(ebiten.Key(b.Code))
+	case Mouse:
+		return m.src.IsMouseButtonPressed(ebiten.MouseButton(b.Code))
+	case GamepadButton:
+		for _, id := range m.gamepads(b.Gamepad) {
+			if m.src.IsGamepadButtonPressed(id, ebiten.GamepadButton(b.Code)) {
+				return true
+			}
+		}
+	case GamepadAxis:
+		return m.rawAxis(b, deadZone)*b.Scale > 0
+	}
+	return false
+}
+
+// bindingValue returns the value of a binding used by an axis (with the
+// scale applied)
+func (m *Manager) bindingValue(b Binding, deadZone float64) float64 {
+	if b.Device == GamepadAxis {
+		if m.src == nil {
+			return 0
+		}
+		return m.rawAxis(b, deadZone) * b.Scale
+	}
+	if m.bindingPressed(b, deadZone) {
+		return b.Scale
+	}
+	return 0
+}
+
+// rawAxis returns the value of a gamepad axis (the one with the largest
+// magnitude if the binding is for any gamepad)
+func (m *Manager) rawAxis(b Binding, deadZone float64) float64 {
+	best := 0.0
+	for _, id := range m.gamepads(b.Gamepad) {
+		if b.Code >= m.src.GamepadAxisNum(id) {
+			continue
+		}
+		v := applyDeadZone(m.src.GamepadAxis(id, b.Code), deadZone)
+		if math.Abs(v) > math.Abs(best) {
+			best = v
+		}
+	}
+	return best
+}
+
+func (m *Manager) gamepads(id int) []int {
+	if id != AnyGamepad {
+		return []int{id}
+	}
+	return m.src.GamepadIDs()
+}
+
+// applyDeadZone zeroes the values inside the dead zone and rescales the
+// rest to [-1, 1]
+func applyDeadZone(v, deadZone float64) float64 {
+	a := math.Abs(v)
+	if a <= deadZone {
+		return 0
+	}
+	if deadZone >= 1 {
+		return 0
+	}
+	a = math.Min(1, (a-deadZone)/(1-deadZone))
+	if v < 0 {
+		return -a
+	}
+	return a
+}
+
+// Pressed returns true if the action is pressed
+func (m *Manager) Pressed(action string) bool {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	st := m.actions[action]
+	return st != nil && st.pressed
+}
+
+// JustPressed returns true if the action was pressed on this frame
+func (m *Manager) JustPressed(action string) bool {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	st := m.actions[action]
+	return st != nil && st.pressed && !st.prev
+}
+
+// JustReleased returns true if the action was released on this frame
+func (m *Manager) JustReleased(action string) bool {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	st := m.actions[action]
+	return st != nil && !st.pressed && st.prev
+}
+
+// PressedFrames returns for how many update frames the action is pressed
+func (m *Manager) PressedFrames(action string) int64 {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	if st := m.actions[action]; st != nil {
+		return st.frames
+	}
+	return 0
+}
+
+// Axis returns the value of an axis [-1, 1]
+func (m *Manager) Axis(axis string) float64 {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	return m.axes[axis]
+}
+
+// CapturePressed returns the first input that is pressed right now. Use it
+// to rebind an action ("press a key..."). Gamepad axes are only captured if
+// they are pushed past the half of their range.
+func (m *Manager) CapturePressed() (Binding, bool) {
+	m.l.RLock()
+	defer m.l.RUnlock()
+	if m.src == nil {
+		return Binding{}, false
+	}
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if m.src.IsKeyPressed(k) {
+			return Key(k), true
+		}
+	}
+	for _, b := range []ebiten.MouseButton{ebiten.MouseButtonLeft, ebiten.MouseButtonRight, ebiten.MouseButtonMiddle} {
+		if m.src.IsMouseButtonPressed(b) {
+			return MouseButton(b), true
+		}
+	}
+	for _, id := range m.src.GamepadIDs() {
+		for b := ebiten.GamepadButton(0); b <= ebiten.GamepadButtonMax; b++ {
+			if m.src.IsGamepadButtonPressed(id, b) {
+				return PadButton(AnyGamepad, b), true
+			}
+		}
+		for axis := 0; axis < m.src.GamepadAxisNum(id); axis++ {
+			v := m.src.GamepadAxis(id, axis)
+			if v > .5 {
+				return PadAxis(AnyGamepad, axis), true
+			}
+			if v < -.5 {
+				return PadAxis(AnyGamepad, axis).Negative(), true
+			}
+		}
+	}
+	return Binding{}, false
+}
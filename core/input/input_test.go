@@ -0,0 +1,104 @@
+package input
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActions(t *testing.T) {
+	src := NewVirtualSource()
+	m := New(src)
+	m.Context("gameplay").
+		BindAction("jump", Key(ebiten.KeySpace), PadButton(AnyGamepad, ebiten.GamepadButton0)).
+		BindAction("fire", MouseButton(ebiten.MouseButtonLeft))
+	m.Context("menu").BindAction("confirm", Key(ebiten.KeyEnter))
+	m.PushContext("gameplay")
+
+	m.Update()
+	assert.False(t, m.Pressed("jump"))
+
+	src.SetKey(ebiten.KeySpace, true)
+	m.Update()
+	assert.True(t, m.Pressed("jump"))
+	assert.True(t, m.JustPressed("jump"))
+	m.Update()
+	assert.True(t, m.Pressed("jump"))
+	assert.False(t, m.JustPressed("jump"))
+	assert.Equal(t, int64(2), m.PressedFrames("jump"))
+	src.SetKey(ebiten.KeySpace, false)
+	m.Update()
+	assert.True(t, m.JustReleased("jump"))
+
+	src.SetGamepadButton(3, ebiten.GamepadButton0, true)
+	m.Update()
+	assert.True(t, m.JustPressed("jump"))
+
+	// the menu blocks the gameplay actions
+	m.PushContext("menu")
+	src.SetKey(ebiten.KeyEnter, true)
+	m.Update()
+	assert.True(t, m.JustReleased("jump"))
+	assert.True(t, m.Pressed("confirm"))
+	m.Context("menu").SetPassThrough(true)
+	m.Update()
+	assert.True(t, m.JustPressed("jump"))
+	assert.Equal(t, "menu", m.PopContext().Name())
+	assert.Equal(t, []string{"gameplay"}, m.ActiveContexts())
+}
+
+func TestAxes(t *testing.T) {
+	src := NewVirtualSource()
+	m := New(src)
+	m.PushContext("gameplay").
+		BindAxis("move_x", Key(ebiten.KeyA).Negative(), Key(ebiten.KeyD), PadAxis(AnyGamepad, 0)).
+		BindAction("left", PadAxis(AnyGamepad, 0).Negative())
+
+	src.SetKey(ebiten.KeyA, true)
+	m.Update()
+	assert.Equal(t, -1.0, m.Axis("move_x"))
+	src.SetKey(ebiten.KeyA, false)
+
+	src.SetGamepadAxis(0, 0, 0.1)
+	m.Update()
+	assert.Equal(t, 0.0, m.Axis("move_x"))
+	assert.False(t, m.Pressed("left"))
+
+	src.SetGamepadAxis(0, 0, -0.6)
+	m.Update()
+	assert.InDelta(t, -0.5, m.Axis("move_x"), 0.0001)
+	assert.True(t, m.Pressed("left"))
+
+	m.SetAxisDeadZone("move_x", 0)
+	m.Update()
+	assert.InDelta(t, -0.6, m.Axis("move_x"), 0.0001)
+}
+
+func TestBindingText(t *testing.T) {
+	for _, v := range []string{"key:Space", "key:A*-1", "mouse:left", "button:0", "button@1:3", "axis@0:1*-0.5"} {
+		b, err := ParseBinding(v)
+		assert.NoError(t, err)
+		assert.Equal(t, v, b.String())
+	}
+	_, err := ParseBinding("key@1:A")
+	assert.Error(t, err)
+	_, err = ParseBinding("gamepad:1")
+	assert.Error(t, err)
+}
+
+func TestSaveBindings(t *testing.T) {
+	m := New(NewVirtualSource())
+	m.Context("gameplay").BindAction("jump", Key(ebiten.KeySpace)).BindAxis("move_x", PadAxis(0, 0))
+	m.Context("gameplay").RebindAction("jump", Key(ebiten.KeyW))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, m.SaveBindings(buf))
+
+	m2 := New(NewVirtualSource())
+	m2.Context("gameplay").BindAction("jump", Key(ebiten.KeySpace)).BindAction("crouch", Key(ebiten.KeyS))
+	assert.NoError(t, m2.LoadBindings(buf))
+	assert.Equal(t, []Binding{Key(ebiten.KeyW)}, m2.Context("gameplay").ActionBindings("jump"))
+	assert.Equal(t, []Binding{Key(ebiten.KeyS)}, m2.Context("gameplay").ActionBindings("crouch"))
+	assert.Equal(t, []Binding{PadAxis(0, 0)}, m2.Context("gameplay").AxisBindings("move_x"))
+}
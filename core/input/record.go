@@ -0,0 +1,544 @@
+package input
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// RecordingVersion is the version of the recording file format
+const RecordingVersion = 1
+
+var recordingMagic = [4]byte{'P', 'R', 'I', 'R'}
+
+// ErrInvalidRecording is returned by ReadRecording when the data is not a
+// input recording (or it's from an unsupported version)
+var ErrInvalidRecording = errors.New("input: invalid recording")
+
+// GamepadState is the state of a gamepad on a FrameState
+type GamepadState struct {
+	ID      int
+	Buttons []ebiten.GamepadButton
+	Axes    []float64
+}
+
+// FrameState is the state of all the input devices on an update frame, and
+// the delta of the frame. It implements Source, so it can be fed to the
+// Manager.
+type FrameState struct {
+	DT           float64
+	Keys         []ebiten.Key
+	MouseButtons []ebiten.MouseButton
+	Gamepads     []GamepadState
+}
+
+var _ Source = (*FrameState)(nil)
+
+// Capture reads the current state of a Source. The axis values are stored
+// with float32 precision (the same precision of a recording file).
+func Capture(src Source) *FrameState {
+	f := &FrameState{}
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if src.IsKeyPressed(k) {
+			f.Keys = append(f.Keys, k)
+		}
+	}
+	for _, b := range []ebiten.MouseButton{ebiten.MouseButtonLeft, ebiten.MouseButtonRight, ebiten.MouseButtonMiddle} {
+		if src.IsMouseButtonPressed(b) {
+			f.MouseButtons = append(f.MouseButtons, b)
+		}
+	}
+	for _, id := range src.GamepadIDs() {
+		gs := GamepadState{
+			ID: id,
+		}
+		for b := ebiten.GamepadButton(0); b <= ebiten.GamepadButtonMax; b++ {
+			if src.IsGamepadButtonPressed(id, b) {
+				gs.Buttons = append(gs.Buttons, b)
+			}
+		}
+		n := src.GamepadAxisNum(id)
+		gs.Axes = make([]float64, n)
+		for i := 0; i < n; i++ {
+			gs.Axes[i] = float64(float32(src.GamepadAxis(id, i)))
+		}
+		f.Gamepads = append(f.Gamepads, gs)
+	}
+	return f
+}
+
+// IsKeyPressed implements Source
+func (f *FrameState) IsKeyPressed(key ebiten.Key) bool {
+	for _, k := range f.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMouseButtonPressed implements Source
+func (f *FrameState) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	for _, b := range f.MouseButtons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+// GamepadIDs implements Source
+func (f *FrameState) GamepadIDs() []int {
+	ids := make([]int, len(f.Gamepads))
+	for i, g := range f.Gamepads {
+		ids[i] = g.ID
+	}
+	return ids
+}
+
+// IsGamepadButtonPressed implements Source
+func (f *FrameState) IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool {
+	g := f.gamepad(id)
+	if g == nil {
+		return false
+	}
+	for _, b := range g.Buttons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+// GamepadAxisNum implements Source
+func (f *FrameState) GamepadAxisNum(id int) int {
+	if g := f.gamepad(id); g != nil {
+		return len(g.Axes)
+	}
+	return 0
+}
+
+// GamepadAxis implements Source
+func (f *FrameState) GamepadAxis(id, axis int) float64 {
+	g := f.gamepad(id)
+	if g == nil || axis < 0 || axis >= len(g.Axes) {
+		return 0
+	}
+	return g.Axes[axis]
+}
+
+func (f *FrameState) gamepad(id int) *GamepadState {
+	for i := range f.Gamepads {
+		if f.Gamepads[i].ID == id {
+			return &f.Gamepads[i]
+		}
+	}
+	return nil
+}
+
+// Recording is a sequence of frame states
+type Recording struct {
+	// Seed is the seed of the random number generators used while recording
+	Seed   int64
+	Frames []*FrameState
+}
+
+// Recorder captures the state of a Source on each frame
+type Recorder struct {
+	src Source
+	rec *Recording
+}
+
+// NewRecorder creates a recorder of src. Seed is stored in the recording
+// (see Recording.Seed).
+func NewRecorder(src Source, seed int64) *Recorder {
+	return &Recorder{
+		src: src,
+		rec: &Recording{
+			Seed: seed,
+		},
+	}
+}
+
+// Source returns the recorded source
+func (r *Recorder) Source() Source {
+	return r.src
+}
+
+// Frame captures the state of a new frame. The returned state must be used
+// as the source of the frame (instead of the recorded source), so the frame
+// sees exactly what is played back later.
+func (r *Recorder) Frame(dt float64) *FrameState {
+	f := Capture(r.src)
+	f.DT = dt
+	r.rec.Frames = append(r.rec.Frames, f)
+	return f
+}
+
+// Recording returns the frames captured so far
+func (r *Recorder) Recording() *Recording {
+	return r.rec
+}
+
+// Player plays back a recording
+type Player struct {
+	rec  *Recording
+	next int
+}
+
+// NewPlayer creates a player of a recording
+func NewPlayer(rec *Recording) *Player {
+	return &Player{
+		rec: rec,
+	}
+}
+
+// Next returns the state of the next frame. It returns false after the
+// last frame.
+func (p *Player) Next() (*FrameState, bool) {
+	if p.next >= len(p.rec.Frames) {
+		return nil, false
+	}
+	f := p.rec.Frames[p.next]
+	p.next++
+	return f, true
+}
+
+// Frame returns the number of frames played
+func (p *Player) Frame() int {
+	return p.next
+}
+
+// Len returns the number of frames of the recording
+func (p *Player) Len() int {
+	return len(p.rec.Frames)
+}
+
+// frame flags (what changed since the previous frame)
+const (
+	recDT byte = 1 << iota
+	recKeys
+	recMouse
+	recGamepads
+)
+
+// WriteTo writes the recording (gzip compressed). Only the parts of each
+// frame that changed since the previous frame are written.
+func (r *Recording) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	bw := bufio.NewWriter(zw)
+	e := &recEncoder{w: bw}
+	e.bytes(recordingMagic[:])
+	e.uvarint(RecordingVersion)
+	e.varint(r.Seed)
+	e.uvarint(uint64(len(r.Frames)))
+	prev := &FrameState{}
+	for _, f := range r.Frames {
+		var flags byte
+		if f.DT != prev.DT {
+			flags |= recDT
+		}
+		if !equalKeys(f.Keys, prev.Keys) {
+			flags |= recKeys
+		}
+		if !equalMouseButtons(f.MouseButtons, prev.MouseButtons) {
+			flags |= recMouse
+		}
+		if !equalGamepads(f.Gamepads, prev.Gamepads) {
+			flags |= recGamepads
+		}
+		e.bytes([]byte{flags})
+		if flags&recDT != 0 {
+			e.float64(f.DT)
+		}
+		if flags&recKeys != 0 {
+			e.uvarint(uint64(len(f.Keys)))
+			for _, k := range f.Keys {
+				e.uvarint(uint64(k))
+			}
+		}
+		if flags&recMouse != 0 {
+			e.uvarint(uint64(len(f.MouseButtons)))
+			for _, b := range f.MouseButtons {
+				e.uvarint(uint64(b))
+			}
+		}
+		if flags&recGamepads != 0 {
+			e.uvarint(uint64(len(f.Gamepads)))
+			for _, g := range f.Gamepads {
+				e.varint(int64(g.ID))
+				e.uvarint(uint64(len(g.Buttons)))
+				for _, b := range g.Buttons {
+					e.uvarint(uint64(b))
+				}
+				e.uvarint(uint64(len(g.Axes)))
+				for _, v := range g.Axes {
+					e.float32(float32(v))
+				}
+			}
+		}
+		prev = f
+	}
+	if e.err == nil {
+		e.err = bw.Flush()
+	}
+	if err := zw.Close(); e.err == nil {
+		e.err = err
+	}
+	return cw.n, e.err
+}
+
+// ReadRecording reads a recording written by Recording.WriteTo
+func ReadRecording(r io.Reader) (*Recording, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidRecording
+	}
+	defer zr.Close()
+	d := &recDecoder{r: bufio.NewReader(zr)}
+	var magic [4]byte
+	d.bytes(magic[:])
+	if d.err != nil || magic != recordingMagic || d.uvarint() != RecordingVersion {
+		return nil, ErrInvalidRecording
+	}
+	rec := &Recording{
+		Seed: d.varint(),
+	}
+	n := d.uvarint()
+	if d.err != nil {
+		return nil, d.err
+	}
+	prev := &FrameState{}
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		var flags [1]byte
+		d.bytes(flags[:])
+		f := &FrameState{
+			DT:           prev.DT,
+			Keys:         prev.Keys,
+			MouseButtons: prev.MouseButtons,
+			Gamepads:     prev.Gamepads,
+		}
+		if flags[0]&recDT != 0 {
+			f.DT = d.float64()
+		}
+		if flags[0]&recKeys != 0 {
+			f.Keys = nil
+			if n := d.count(); n > 0 {
+				f.Keys = make([]ebiten.Key, n)
+			}
+			for j := range f.Keys {
+				f.Keys[j] = ebiten.Key(d.uvarint())
+			}
+		}
+		if flags[0]&recMouse != 0 {
+			f.MouseButtons = nil
+			if n := d.count(); n > 0 {
+				f.MouseButtons = make([]ebiten.MouseButton, n)
+			}
+			for j := range f.MouseButtons {
+				f.MouseButtons[j] = ebiten.MouseButton(d.uvarint())
+			}
+		}
+		if flags[0]&recGamepads != 0 {
+			f.Gamepads = nil
+			if n := d.count(); n > 0 {
+				f.Gamepads = make([]GamepadState, n)
+			}
+			for j := range f.Gamepads {
+				g := &f.Gamepads[j]
+				g.ID = int(d.varint())
+				if n := d.count(); n > 0 {
+					g.Buttons = make([]ebiten.GamepadButton, n)
+				}
+				for k := range g.Buttons {
+					g.Buttons[k] = ebiten.GamepadButton(d.uvarint())
+				}
+				g.Axes = make([]float64, d.count())
+				for k := range g.Axes {
+					g.Axes[k] = float64(d.float32())
+				}
+			}
+		}
+		rec.Frames = append(rec.Frames, f)
+		prev = f
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return rec, nil
+}
+
+// SaveFile writes the recording to a file
+func (r *Recording) SaveFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadRecordingFile reads a recording file
+func LoadRecordingFile(name string) (*Recording, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
+
+func equalKeys(a, b []ebiten.Key) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalMouseButtons(a, b []ebiten.MouseButton) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalGamepads(a, b []GamepadState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID || len(a[i].Buttons) != len(b[i].Buttons) || len(a[i].Axes) != len(b[i].Axes) {
+			return false
+		}
+		for j := range a[i].Buttons {
+			if a[i].Buttons[j] != b[i].Buttons[j] {
+				return false
+			}
+		}
+		for j := range a[i].Axes {
+			if a[i].Axes[j] != b[i].Axes[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+type recEncoder struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (e *recEncoder) bytes(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *recEncoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.buf[:], v)
+	e.bytes(e.buf[:n])
+}
+
+func (e *recEncoder) varint(v int64) {
+	n := binary.PutVarint(e.buf[:], v)
+	e.bytes(e.buf[:n])
+}
+
+func (e *recEncoder) float64(v float64) {
+	binary.LittleEndian.PutUint64(e.buf[:8], math.Float64bits(v))
+	e.bytes(e.buf[:8])
+}
+
+func (e *recEncoder) float32(v float32) {
+	binary.LittleEndian.PutUint32(e.buf[:4], math.Float32bits(v))
+	e.bytes(e.buf[:4])
+}
+
+type recDecoder struct {
+	r   *bufio.Reader
+	buf [8]byte
+	err error
+}
+
+func (d *recDecoder) bytes(b []byte) {
+	if d.err == nil {
+		_, d.err = io.ReadFull(d.r, b)
+	}
+}
+
+func (d *recDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *recDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.err = err
+	return v
+}
+
+// count reads a slice length (and checks if it's sane)
+func (d *recDecoder) count() int {
+	n := d.uvarint()
+	if n > 1<<16 {
+		d.err = ErrInvalidRecording
+		return 0
+	}
+	return int(n)
+}
+
+func (d *recDecoder) float64() float64 {
+	d.bytes(d.buf[:8])
+	if d.err != nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(d.buf[:8]))
+}
+
+func (d *recDecoder) float32() float32 {
+	d.bytes(d.buf[:4])
+	if d.err != nil {
+		return 0
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(d.buf[:4]))
+}
@@ -0,0 +1,58 @@
+package input
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordReplay(t *testing.T) {
+	src := NewVirtualSource()
+	m := New(src)
+	m.PushContext("gameplay").
+		BindAction("jump", Key(ebiten.KeySpace)).
+		BindAxis("move_x", PadAxis(AnyGamepad, 0))
+
+	rec := NewRecorder(src, 42)
+	var jumps []bool
+	var moves []float64
+	for i := 0; i < 20; i++ {
+		src.SetKey(ebiten.KeySpace, i%5 == 0)
+		src.SetGamepadAxis(1, 0, float64(i)/20)
+		m.SetSource(rec.Frame(1.0 / 60))
+		m.Update()
+		jumps = append(jumps, m.JustPressed("jump"))
+		moves = append(moves, m.Axis("move_x"))
+	}
+
+	buf := new(bytes.Buffer)
+	_, err := rec.Recording().WriteTo(buf)
+	assert.NoError(t, err)
+	loaded, err := ReadRecording(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), loaded.Seed)
+	assert.Equal(t, rec.Recording().Frames, loaded.Frames)
+
+	m2 := New(nil)
+	m2.PushContext("gameplay").
+		BindAction("jump", Key(ebiten.KeySpace)).
+		BindAxis("move_x", PadAxis(AnyGamepad, 0))
+	p := NewPlayer(loaded)
+	for i := 0; ; i++ {
+		f, ok := p.Next()
+		if !ok {
+			assert.Equal(t, 20, i)
+			break
+		}
+		assert.Equal(t, 1.0/60, f.DT)
+		m2.SetSource(f)
+		m2.Update()
+		assert.Equal(t, jumps[i], m2.JustPressed("jump"))
+		assert.Equal(t, moves[i], m2.Axis("move_x"))
+	}
+
+	_, err = ReadRecording(bytes.NewReader([]byte("not a recording")))
+	assert.Equal(t, ErrInvalidRecording, err)
+}
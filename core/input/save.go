@@ -0,0 +1,82 @@
+package input
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+type savedContext struct {
+	Actions map[string][]Binding `json:"actions,omitempty"`
+	Axes    map[string][]Binding `json:"axes,omitempty"`
+}
+
+type savedBindings struct {
+	Contexts map[string]savedContext `json:"contexts"`
+}
+
+// SaveBindings writes the bindings of all contexts (as JSON)
+func (m *Manager) SaveBindings(w io.Writer) error {
+	out := savedBindings{
+		Contexts: make(map[string]savedContext),
+	}
+	for _, c := range m.Contexts() {
+		sc := savedContext{
+			Actions: make(map[string][]Binding),
+			Axes:    make(map[string][]Binding),
+		}
+		for _, name := range c.Actions() {
+			sc.Actions[name] = c.ActionBindings(name)
+		}
+		for _, name := range c.Axes() {
+			sc.Axes[name] = c.AxisBindings(name)
+		}
+		out.Contexts[c.Name()] = sc
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// LoadBindings reads bindings saved by SaveBindings. The actions and axes
+// found are rebound; the ones not found keep their current bindings (so new
+// actions keep their defaults).
+func (m *Manager) LoadBindings(r io.Reader) error {
+	in := savedBindings{}
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return err
+	}
+	for cname, sc := range in.Contexts {
+		c := m.Context(cname)
+		for name, b := range sc.Actions {
+			c.RebindAction(name, b...)
+		}
+		for name, b := range sc.Axes {
+			c.RebindAxis(name, b...)
+		}
+	}
+	return nil
+}
+
+// SaveBindingsFile saves the bindings to a file
+func (m *Manager) SaveBindingsFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := m.SaveBindings(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadBindingsFile loads the bindings of a file saved by SaveBindingsFile
+func (m *Manager) LoadBindingsFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.LoadBindings(f)
+}
@@ -0,0 +1,182 @@
+package input
+
+import (
+	"sync"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Source is a input device (or a set of devices). The Manager polls the
+// source once per update frame.
+type Source interface {
+	IsKeyPressed(key ebiten.Key) bool
+	IsMouseButtonPressed(button ebiten.MouseButton) bool
+	GamepadIDs() []int
+	IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool
+	GamepadAxisNum(id int) int
+	GamepadAxis(id, axis int) float64
+}
+
+// EbitenSource reads the input from ebiten
+type EbitenSource struct{}
+
+var _ Source = EbitenSource{}
+
+// IsKeyPressed implements Source
+func (EbitenSource) IsKeyPressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+// IsMouseButtonPressed implements Source
+func (EbitenSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(button)
+}
+
+// GamepadIDs implements Source
+func (EbitenSource) GamepadIDs() []int {
+	return ebiten.GamepadIDs()
+}
+
+// IsGamepadButtonPressed implements Source
+func (EbitenSource) IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool {
+	return ebiten.IsGamepadButtonPressed(id, button)
+}
+
+// GamepadAxisNum implements Source
+func (EbitenSource) GamepadAxisNum(id int) int {
+	return ebiten.GamepadAxisNum(id)
+}
+
+// GamepadAxis implements Source
+func (EbitenSource) GamepadAxis(id, axis int) float64 {
+	return ebiten.GamepadAxis(id, axis)
+}
+
+// VirtualSource is a Source that is controlled by code. Use it to inject
+// input on tests (or to feed input from the network, bots, etc).
+type VirtualSource struct {
+	l       sync.Mutex
+	keys    map[ebiten.Key]bool
+	mouse   map[ebiten.MouseButton]bool
+	buttons map[int]map[ebiten.GamepadButton]bool
+	axes    map[int][]float64
+	pads    []int
+}
+
+var _ Source = (*VirtualSource)(nil)
+
+// NewVirtualSource returns a VirtualSource without any pressed input
+func NewVirtualSource() *VirtualSource {
+	return &VirtualSource{
+		keys:    make(map[ebiten.Key]bool),
+		mouse:   make(map[ebiten.MouseButton]bool),
+		buttons: make(map[int]map[ebiten.GamepadButton]bool),
+		axes:    make(map[int][]float64),
+	}
+}
+
+// SetKey presses or releases a key
+func (s *VirtualSource) SetKey(key ebiten.Key, pressed bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.keys[key] = pressed
+}
+
+// SetMouseButton presses or releases a mouse button
+func (s *VirtualSource) SetMouseButton(button ebiten.MouseButton, pressed bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.mouse[button] = pressed
+}
+
+// SetGamepadButton presses or releases a gamepad button. The gamepad is
+// connected if it isn't.
+func (s *VirtualSource) SetGamepadButton(id int, button ebiten.GamepadButton, pressed bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.connect(id)
+	s.buttons[id][button] = pressed
+}
+
+// SetGamepadAxis sets the value of a gamepad axis. The gamepad is connected
+// if it isn't.
+func (s *VirtualSource) SetGamepadAxis(id, axis int, value float64) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.connect(id)
+	for len(s.axes[id]) <= axis {
+		s.axes[id] = append(s.axes[id], 0)
+	}
+	s.axes[id][axis] = value
+}
+
+// Reset releases everything (the gamepads stay connected)
+func (s *VirtualSource) Reset() {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.keys = make(map[ebiten.Key]bool)
+	s.mouse = make(map[ebiten.MouseButton]bool)
+	for id := range s.buttons {
+		s.buttons[id] = make(map[ebiten.GamepadButton]bool)
+	}
+	for id := range s.axes {
+		for i := range s.axes[id] {
+			s.axes[id][i] = 0
+		}
+	}
+}
+
+func (s *VirtualSource) connect(id int) {
+	if _, ok := s.buttons[id]; ok {
+		return
+	}
+	s.buttons[id] = make(map[ebiten.GamepadButton]bool)
+	s.pads = append(s.pads, id)
+}
+
+// IsKeyPressed implements Source
+func (s *VirtualSource) IsKeyPressed(key ebiten.Key) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.keys[key]
+}
+
+// IsMouseButtonPressed implements Source
+func (s *VirtualSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.mouse[button]
+}
+
+// GamepadIDs implements Source
+func (s *VirtualSource) GamepadIDs() []int {
+	s.l.Lock()
+	defer s.l.Unlock()
+	ids := make([]int, len(s.pads))
+	copy(ids, s.pads)
+	return ids
+}
+
+// IsGamepadButtonPressed implements Source
+func (s *VirtualSource) IsGamepadButtonPressed(id int, button ebiten.GamepadButton) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.buttons[id][button]
+}
+
+// GamepadAxisNum implements Source
+func (s *VirtualSource) GamepadAxisNum(id int) int {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return len(s.axes[id])
+}
+
+// GamepadAxis implements Source
+func (s *VirtualSource) GamepadAxis(id, axis int) float64 {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if axis < 0 || axis >= len(s.axes[id]) {
+		return 0
+	}
+	return s.axes[id][axis]
+}
@@ -25,9 +25,13 @@ func eventListenFn(e core.Engine, runtime *goja.Runtime) func(call goja.Function
 		}
 		if callback, ok := goja.AssertFunction(call.Argument(1)); ok {
 			if name, ok := arg0i.(string); ok {
-				id := e.AddEventListener(name, func(eventName string, e core.Event) {
-					callback(goja.Null(), runtime.ToValue(name), runtime.ToValue(e.Data))
-				})
+				// goja runtimes are not thread safe; the listener is always
+				// called on the main thread (even with EventDispatchAsync)
+				opt := listenerOptions(runtime, call.Argument(2))
+				opt.MainThread = true
+				id := e.AddEventListenerWithOptions(name, func(eventName string, e core.Event) {
+					callback(goja.Null(), runtime.ToValue(eventName), runtime.ToValue(e.Data))
+				}, opt)
 				return runtime.ToValue(int64(id))
 			}
 		}
@@ -35,6 +39,22 @@ func eventListenFn(e core.Engine, runtime *goja.Runtime) func(call goja.Function
 	}
 }
 
+// listenerOptions parses an optional { priority: number, once: bool } object
+func listenerOptions(runtime *goja.Runtime, v goja.Value) core.ListenerOptions {
+	opt := core.ListenerOptions{}
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return opt
+	}
+	obj := v.ToObject(runtime)
+	if p := obj.Get("priority"); p != nil && !goja.IsUndefined(p) {
+		opt.Priority = int(p.ToInteger())
+	}
+	if o := obj.Get("once"); o != nil && !goja.IsUndefined(o) {
+		opt.Once = o.ToBoolean()
+	}
+	return opt
+}
+
 func eventRemoveListenerFn(e core.Engine, runtime *goja.Runtime) func(call goja.FunctionCall) goja.Value {
 	return func(call goja.FunctionCall) goja.Value {
 		id := call.Argument(0).ToInteger()
@@ -0,0 +1,50 @@
+package js_test
+
+import (
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/gabstv/primen"
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/js"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRuntime(t *testing.T) (primen.Engine, *goja.Runtime) {
+	e := primen.NewHeadlessEngine(nil)
+	r := goja.New()
+	js.Stdlib(e, r)
+	return e, r
+}
+
+func TestEventsWildcards(t *testing.T) {
+	e, r := newTestRuntime(t)
+	_, err := r.RunString(`
+		var names = [];
+		$events.add_listener("enemy.*", function(name, data) { names.push("1:" + name + ":" + data); });
+		$events.add_listener("**", function(name) { names.push("2:" + name); });
+		$events.dispatch("enemy.spawn", 3);
+	`)
+	assert.NoError(t, err)
+	e.DispatchEvent("enemy.hit.fire", nil)
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, []interface{}{
+		"1:enemy.spawn:3", "2:enemy.spawn",
+		"2:enemy.hit.fire",
+	}, r.Get("names").Export())
+}
+
+func TestEventsAsyncMode(t *testing.T) {
+	e, r := newTestRuntime(t)
+	e.SetEventDispatchMode(core.EventDispatchAsync)
+	_, err := r.RunString(`
+		var names = [];
+		$events.add_listener("hit", function(name) { names.push(name); });
+	`)
+	assert.NoError(t, err)
+	// the runtime is only called on the main thread (Step)
+	e.DispatchEvent("hit", nil)
+	assert.Equal(t, []interface{}{}, r.Get("names").Export())
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, []interface{}{"hit"}, r.Get("names").Export())
+}
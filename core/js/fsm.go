@@ -0,0 +1,241 @@
+package js
+
+import (
+	"errors"
+
+	"github.com/dop251/goja"
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components/fsm"
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/logger"
+)
+
+const fsmDefinitionKey = "__definition"
+
+// fsmDefine builds a fsm.Definition from a js object:
+//
+//	$fsm.define({
+//	  initial: "idle",
+//	  states: {
+//	    idle: { clip: "idle", enter: function(ctx) {}, update: function(ctx) {}, exit: function(ctx) {} },
+//	    run: { clip: "run" }
+//	  },
+//	  transitions: [
+//	    { from: "idle", to: "run", event: "move" },
+//	    { from: "*", to: "idle", event: "level_end", engine: true },
+//	    { from: "run", to: "idle", when: function(ctx) { return ctx.state_time > 2 } }
+//	  ]
+//	})
+func fsmDefine(e core.Engine, runtime *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		spec := call.Argument(0)
+		if goja.IsUndefined(spec) || goja.IsNull(spec) {
+			panic(runtime.NewGoError(errors.New("$fsm.define: missing spec")))
+		}
+		obj := spec.ToObject(runtime)
+		def := fsm.NewDefinition(jsString(obj.Get("initial")))
+		if sv := obj.Get("states"); isSet(sv) {
+			states := sv.ToObject(runtime)
+			for _, name := range states.Keys() {
+				so := states.Get(name).ToObject(runtime)
+				def.AddState(fsm.State{
+					Name:     name,
+					Clip:     jsString(so.Get("clip")),
+					OnEnter:  fsmHook(runtime, so.Get("enter")),
+					OnUpdate: fsmHook(runtime, so.Get("update")),
+					OnExit:   fsmHook(runtime, so.Get("exit")),
+				})
+			}
+		}
+		if tv := obj.Get("transitions"); isSet(tv) {
+			tobj := tv.ToObject(runtime)
+			n := int(tobj.Get("length").ToInteger())
+			for i := 0; i < n; i++ {
+				to := tobj.Get(runtime.ToValue(i).String()).ToObject(runtime)
+				t := fsm.Transition{
+					From:  jsString(to.Get("from")),
+					To:    jsString(to.Get("to")),
+					Event: jsString(to.Get("event")),
+				}
+				if t.From == "" {
+					t.From = fsm.AnyState
+				}
+				if ev := to.Get("engine"); isSet(ev) {
+					t.EngineEvent = ev.ToBoolean()
+				}
+				if fn, ok := goja.AssertFunction(to.Get("when")); ok {
+					t.When = func(ctx *fsm.Ctx) bool {
+						v, err := fn(goja.Null(), fsmCtxObject(runtime, ctx))
+						if err != nil {
+							fsmLogger(ctx).Error("$fsm guard failed", logger.Err(err))
+							return false
+						}
+						return v.ToBoolean()
+					}
+				}
+				def.AddTransition(t)
+			}
+		}
+		dobj := runtime.NewObject()
+		dobj.Set(fsmDefinitionKey, def)
+		dobj.Set("initial", def.Initial())
+		return dobj
+	}
+}
+
+// fsmAttach adds a state machine to an entity and returns its machine object
+// (see StateMachineObject). The world is a Go value set in the runtime:
+//
+//	var m = $fsm.attach(world, entity, $fsm.define({...}))
+//	m.trigger("move")
+func fsmAttach(runtime *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		w, e := fsmEntity(runtime, "$fsm.attach", call)
+		def := FSMDefinition(call.Argument(2))
+		if def == nil {
+			panic(runtime.NewGoError(errors.New("$fsm.attach: invalid definition (use $fsm.define)")))
+		}
+		fsm.SetStateMachineComponentData(w, e, fsm.New(def))
+		return StateMachineObject(runtime, w, e)
+	}
+}
+
+// fsmGet returns the machine object of an entity (or null if the entity
+// doesn't have a state machine):
+//
+//	var m = $fsm.get(world, entity)
+func fsmGet(runtime *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		w, e := fsmEntity(runtime, "$fsm.get", call)
+		if fsm.GetStateMachineComponentData(w, e) == nil {
+			return goja.Null()
+		}
+		return StateMachineObject(runtime, w, e)
+	}
+}
+
+// fsmEntity parses the (world, entity) arguments
+func fsmEntity(runtime *goja.Runtime, fname string, call goja.FunctionCall) (ecs.BaseWorld, ecs.Entity) {
+	w, ok := call.Argument(0).Export().(ecs.BaseWorld)
+	if !ok {
+		panic(runtime.NewGoError(errors.New(fname + ": invalid world")))
+	}
+	e := call.Argument(1).ToInteger()
+	if e <= 0 {
+		panic(runtime.NewGoError(errors.New(fname + ": invalid entity")))
+	}
+	return w, ecs.Entity(e)
+}
+
+// FSMDefinition returns the fsm.Definition of an object created with
+// $fsm.define (or nil)
+func FSMDefinition(v goja.Value) *fsm.Definition {
+	if !isSet(v) {
+		return nil
+	}
+	obj, ok := v.(*goja.Object)
+	if !ok {
+		return nil
+	}
+	def, _ := obj.Get(fsmDefinitionKey).Export().(*fsm.Definition)
+	return def
+}
+
+// StateMachineObject exposes the state machine of an entity to a js runtime.
+// The object has the functions trigger(event, data), set_state(name),
+// state(), state_time() and history().
+func StateMachineObject(runtime *goja.Runtime, w ecs.BaseWorld, e ecs.Entity) *goja.Object {
+	obj := runtime.NewObject()
+	obj.Set("entity", uint64(e))
+	obj.Set("trigger", func(event string, data goja.Value) bool {
+		var d interface{}
+		if isSet(data) {
+			d = data.Export()
+		}
+		return fsm.Trigger(w, e, event, d)
+	})
+	obj.Set("set_state", func(name string) bool {
+		m := fsm.GetStateMachineComponentData(w, e)
+		if m == nil {
+			return false
+		}
+		m.SetState(name)
+		return true
+	})
+	obj.Set("state", func() string {
+		if m := fsm.GetStateMachineComponentData(w, e); m != nil {
+			return m.Current()
+		}
+		return ""
+	})
+	obj.Set("state_time", func() float64 {
+		if m := fsm.GetStateMachineComponentData(w, e); m != nil {
+			return m.StateTime()
+		}
+		return 0
+	})
+	obj.Set("history", func() goja.Value {
+		m := fsm.GetStateMachineComponentData(w, e)
+		if m == nil {
+			return runtime.NewArray()
+		}
+		h := m.History()
+		items := make([]interface{}, 0, len(h))
+		for _, v := range h {
+			item := runtime.NewObject()
+			item.Set("from", v.From)
+			item.Set("to", v.To)
+			item.Set("event", v.Event)
+			item.Set("frame", v.Frame)
+			items = append(items, item)
+		}
+		return runtime.NewArray(items...)
+	})
+	return obj
+}
+
+func fsmHook(runtime *goja.Runtime, v goja.Value) fsm.HookFn {
+	fn, ok := goja.AssertFunction(v)
+	if !ok {
+		return nil
+	}
+	return func(ctx *fsm.Ctx) {
+		if _, err := fn(goja.Null(), fsmCtxObject(runtime, ctx)); err != nil {
+			fsmLogger(ctx).Error("$fsm hook failed", logger.F("from", ctx.From), logger.F("to", ctx.To), logger.Err(err))
+		}
+	}
+}
+
+// fsmLogger returns the logger of the engine of the state machine world
+func fsmLogger(ctx *fsm.Ctx) *logger.Logger {
+	if w, ok := ctx.World.(core.World); ok && w.Engine() != nil {
+		return w.Engine().Logger().Tag("js")
+	}
+	return logger.Default.Tag("js")
+}
+
+func fsmCtxObject(runtime *goja.Runtime, ctx *fsm.Ctx) *goja.Object {
+	obj := runtime.NewObject()
+	obj.Set("entity", uint64(ctx.Entity))
+	obj.Set("from", ctx.From)
+	obj.Set("to", ctx.To)
+	obj.Set("event", ctx.Event)
+	obj.Set("data", ctx.Data)
+	obj.Set("state_time", ctx.StateTime)
+	obj.Set("machine", StateMachineObject(runtime, ctx.World, ctx.Entity))
+	if ctx.Ctx != nil {
+		obj.Set("dt", ctx.Ctx.DT())
+	}
+	return obj
+}
+
+func isSet(v goja.Value) bool {
+	return v != nil && !goja.IsUndefined(v) && !goja.IsNull(v)
+}
+
+func jsString(v goja.Value) string {
+	if !isSet(v) {
+		return ""
+	}
+	return v.String()
+}
@@ -0,0 +1,47 @@
+package js_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSMAttach(t *testing.T) {
+	e, r := newTestRuntime(t)
+	w := e.NewWorldWithDefaults(0)
+	r.Set("world", w)
+	r.Set("entity", uint64(w.NewEntity()))
+	_, err := r.RunString(`
+		var log = [];
+		var m = $fsm.attach(world, entity, $fsm.define({
+			initial: "idle",
+			states: {
+				idle: { exit: function(ctx) { log.push("exit idle"); } },
+				run: { enter: function(ctx) { log.push("enter run:" + ctx.event); } }
+			},
+			transitions: [
+				{ from: "idle", to: "run", event: "move" },
+				{ from: "run", to: "idle", when: function(ctx) { return ctx.state_time >= 1; } }
+			]
+		}));
+	`)
+	assert.NoError(t, err)
+	v, err := r.RunString(`$fsm.get(world, entity + 100) === null && $fsm.get(world, entity).state() === m.state()`)
+	assert.NoError(t, err)
+	assert.True(t, v.ToBoolean())
+	_, err = r.RunString(`$fsm.attach(world, entity, {})`)
+	assert.Error(t, err)
+
+	assert.NoError(t, e.Step(0.25))
+	_, err = r.RunString(`m.trigger("move")`)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(0.25))
+	v, err = r.RunString(`m.state()`)
+	assert.NoError(t, err)
+	assert.Equal(t, "run", v.String())
+	assert.NoError(t, e.StepN(5, 0.25))
+	v, err = r.RunString(`m.state() + ":" + $fsm.get(world, entity).history().length`)
+	assert.NoError(t, err)
+	assert.Equal(t, "idle:3", v.String())
+	assert.Equal(t, []interface{}{"exit idle", "enter run:move"}, r.Get("log").Export())
+}
@@ -3,6 +3,7 @@ package js
 import (
 	"github.com/dop251/goja"
 	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/logger"
 )
 
 type sceneLoader interface {
@@ -60,7 +61,7 @@ func scenesLoad(e core.Engine, runtime *goja.Runtime) func(call goja.FunctionCal
 	return func(call goja.FunctionCall) goja.Value {
 		hh := e.(sceneLoader).LoadSceneJS(call.Argument(0).String()).(sceneLoadH)
 		if hh.Err() != nil {
-			println("LoadSceneJS: " + hh.Err().Error())
+			e.Logger().Tag("js").Error("$scenes.load failed", logger.F("scene", call.Argument(0).String()), logger.Err(hh.Err()))
 			return runtime.NewGoError(hh.Err())
 		}
 		so := jsScene(runtime, e, hh.Scene().(Scene))
@@ -17,4 +17,10 @@ func Stdlib(e core.Engine, r *goja.Runtime) {
 	scenes.Set("last", scenesLast(e, r))
 	scenes.Set("load", scenesLoad(e, r))
 	r.Set("$scenes", scenes)
+
+	fsmo := r.NewObject()
+	fsmo.Set("define", fsmDefine(e, r))
+	fsmo.Set("attach", fsmAttach(r))
+	fsmo.Set("get", fsmGet(r))
+	r.Set("$fsm", fsmo)
 }
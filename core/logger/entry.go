@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a log entry
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Tag     string
+	Message string
+	Fields  []Field
+}
+
+// Field returns the value of a field (or nil)
+func (e Entry) Field(key string) interface{} {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value
+		}
+	}
+	return nil
+}
+
+// String formats the entry as a line:
+//
+//	15:04:05.000 ERROR [io] open failed file=a.png err="not found"
+func (e Entry) String() string {
+	sb := new(strings.Builder)
+	sb.WriteString(e.Time.Format("15:04:05.000"))
+	sb.WriteString(" ")
+	sb.WriteString(e.Level.String())
+	if e.Tag != "" {
+		sb.WriteString(" [")
+		sb.WriteString(e.Tag)
+		sb.WriteString("]")
+	}
+	sb.WriteString(" ")
+	sb.WriteString(e.Message)
+	for _, f := range e.Fields {
+		sb.WriteString(" ")
+		sb.WriteString(f.Key)
+		sb.WriteString("=")
+		v := fmt.Sprint(f.Value)
+		if strings.ContainsAny(v, " \t\n\"=") || v == "" {
+			v = fmt.Sprintf("%q", v)
+		}
+		sb.WriteString(v)
+	}
+	return sb.String()
+}
+
+// WriterSink writes the entries as lines (see Entry.String)
+type WriterSink struct {
+	w io.Writer
+}
+
+// NewWriterSink returns a sink that writes to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{
+		w: w,
+	}
+}
+
+// Write implements Sink
+func (s *WriterSink) Write(e Entry) {
+	_, _ = io.WriteString(s.w, e.String()+"\n")
+}
+
+// Ring is a fixed size buffer of the recent entries. It is also a Sink, so
+// it can be added to a Logger to capture entries (in tests).
+type Ring struct {
+	l       sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRing returns a ring buffer with room for size entries
+func NewRing(size int) *Ring {
+	if size < 1 {
+		size = 1
+	}
+	return &Ring{
+		entries: make([]Entry, size),
+	}
+}
+
+// Write implements Sink
+func (r *Ring) Write(e Entry) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Len returns the number of entries in the buffer
+func (r *Ring) Len() int {
+	r.l.Lock()
+	defer r.l.Unlock()
+	return r.len()
+}
+
+func (r *Ring) len() int {
+	if r.full {
+		return len(r.entries)
+	}
+	return r.next
+}
+
+// Last returns the last n entries (oldest first). If n <= 0, all the
+// entries are returned.
+func (r *Ring) Last(n int) []Entry {
+	r.l.Lock()
+	defer r.l.Unlock()
+	total := r.len()
+	if n <= 0 || n > total {
+		n = total
+	}
+	out := make([]Entry, n)
+	start := r.next - n
+	if start < 0 {
+		start += len(r.entries)
+	}
+	for i := 0; i < n; i++ {
+		out[i] = r.entries[(start+i)%len(r.entries)]
+	}
+	return out
+}
+
+// Lines returns the last n entries as lines (see Entry.String)
+func (r *Ring) Lines(n int) []string {
+	entries := r.Last(n)
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.String()
+	}
+	return out
+}
+
+// Clear removes all the entries
+func (r *Ring) Clear() {
+	r.l.Lock()
+	defer r.l.Unlock()
+	for i := range r.entries {
+		r.entries[i] = Entry{}
+	}
+	r.next = 0
+	r.full = false
+}
@@ -0,0 +1,327 @@
+// Package logger is the leveled logger of Primen.
+//
+// A Logger writes entries (a level, a tag, a message and structured fields)
+// to sinks. The tags identify the subsystem ("io", "scene", "js", "audio",
+// ...). Every Logger keeps the recent entries in a ring buffer, so they can
+// be displayed by in-game overlays:
+//
+//	l := engine.Logger().Tag("game")
+//	l.Info("level loaded", logger.F("level", 3), logger.F("enemies", 12))
+//	for _, line := range engine.Logger().Recent(10) {
+//		...
+//	}
+package logger
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of an entry
+type Level int8
+
+const (
+	// LevelDebug is used for verbose messages
+	LevelDebug Level = -1
+	// LevelInfo is the default level
+	LevelInfo Level = 0
+	// LevelWarn is used for recoverable problems
+	LevelWarn Level = 1
+	// LevelError is used for errors
+	LevelError Level = 2
+	// LevelOff disables the logger (when used as the min level)
+	LevelOff Level = 3
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelOff:
+		return "OFF"
+	}
+	return "?"
+}
+
+// ParseLevel parses the name of a level ("debug", "info", "warn", "error"
+// or "off"). It returns LevelInfo and false if the name is invalid.
+func ParseLevel(name string) (Level, bool) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, true
+	case "info":
+		return LevelInfo, true
+	case "warn", "warning":
+		return LevelWarn, true
+	case "error":
+		return LevelError, true
+	case "off":
+		return LevelOff, true
+	}
+	return LevelInfo, false
+}
+
+// Field is a structured field of an entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a Field
+func F(key string, value interface{}) Field {
+	return Field{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// Err returns a Field with the "err" key
+func Err(err error) Field {
+	return Field{
+		Key:   "err",
+		Value: err,
+	}
+}
+
+// Sink receives the entries of a Logger. Write is called with the logger
+// lock held, so it must not log.
+type Sink interface {
+	Write(e Entry)
+}
+
+// SinkFunc is a func Sink
+type SinkFunc func(e Entry)
+
+// Write calls fn(e)
+func (fn SinkFunc) Write(e Entry) {
+	fn(e)
+}
+
+// SinkID identifies a sink added to a Logger
+type SinkID int64
+
+// DefaultRingSize is the number of recent entries kept by a Logger
+const DefaultRingSize = 256
+
+type hub struct {
+	l        sync.Mutex
+	level    Level
+	tags     map[string]Level
+	sinks    []sinkContainer
+	lastSink SinkID
+	ring     *Ring
+	now      func() time.Time
+}
+
+type sinkContainer struct {
+	id   SinkID
+	sink Sink
+}
+
+// Logger is a leveled logger. The loggers returned by Tag and With share
+// the level, the sinks and the ring buffer of their parent.
+type Logger struct {
+	h      *hub
+	tag    string
+	fields []Field
+}
+
+// New returns a Logger with the sinks (and a ring buffer of DefaultRingSize
+// entries)
+func New(sinks ...Sink) *Logger {
+	h := &hub{
+		level: LevelInfo,
+		tags:  make(map[string]Level),
+		ring:  NewRing(DefaultRingSize),
+		now:   time.Now,
+	}
+	l := &Logger{
+		h: h,
+	}
+	for _, s := range sinks {
+		l.AddSink(s)
+	}
+	return l
+}
+
+// Default writes to stderr. It is used by the engines created without a
+// logger and by the packages without access to an engine.
+var Default = New(NewWriterSink(os.Stderr))
+
+// Tag returns a logger with the tag (the subsystem of the entries)
+func (l *Logger) Tag(tag string) *Logger {
+	return &Logger{
+		h:      l.h,
+		tag:    tag,
+		fields: l.fields,
+	}
+}
+
+// With returns a logger that adds the fields to all the entries
+func (l *Logger) With(fields ...Field) *Logger {
+	ff := make([]Field, 0, len(l.fields)+len(fields))
+	ff = append(ff, l.fields...)
+	ff = append(ff, fields...)
+	return &Logger{
+		h:      l.h,
+		tag:    l.tag,
+		fields: ff,
+	}
+}
+
+// SetLevel sets the min level of the entries (of all tags without a level)
+func (l *Logger) SetLevel(level Level) {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	l.h.level = level
+}
+
+// Level returns the min level of the entries
+func (l *Logger) Level() Level {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	return l.h.level
+}
+
+// SetTagLevel sets the min level of the entries of a tag
+func (l *Logger) SetTagLevel(tag string, level Level) {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	l.h.tags[tag] = level
+}
+
+// ResetTagLevel makes the tag use the level of the logger
+func (l *Logger) ResetTagLevel(tag string) {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	delete(l.h.tags, tag)
+}
+
+// Enabled returns true if an entry with the level (and the tag of the
+// logger) would be written
+func (l *Logger) Enabled(level Level) bool {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	return l.enabled(level)
+}
+
+func (l *Logger) enabled(level Level) bool {
+	min := l.h.level
+	if v, ok := l.h.tags[l.tag]; ok {
+		min = v
+	}
+	return level >= min && level < LevelOff
+}
+
+// AddSink adds a sink to the logger (and to all the loggers that share its
+// sinks)
+func (l *Logger) AddSink(s Sink) SinkID {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	l.h.lastSink++
+	l.h.sinks = append(l.h.sinks, sinkContainer{
+		id:   l.h.lastSink,
+		sink: s,
+	})
+	return l.h.lastSink
+}
+
+// RemoveSink removes a sink
+func (l *Logger) RemoveSink(id SinkID) bool {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	for i, s := range l.h.sinks {
+		if s.id == id {
+			l.h.sinks = append(l.h.sinks[:i:i], l.h.sinks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ClearSinks removes all the sinks (the ring buffer is kept)
+func (l *Logger) ClearSinks() {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	l.h.sinks = nil
+}
+
+// Ring returns the ring buffer of the recent entries
+func (l *Logger) Ring() *Ring {
+	return l.h.ring
+}
+
+// Recent returns the last n entries (oldest first)
+func (l *Logger) Recent(n int) []Entry {
+	return l.h.ring.Last(n)
+}
+
+// Log writes an entry
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	l.h.l.Lock()
+	defer l.h.l.Unlock()
+	if !l.enabled(level) {
+		return
+	}
+	e := Entry{
+		Time:    l.h.now(),
+		Level:   level,
+		Tag:     l.tag,
+		Message: msg,
+	}
+	if len(l.fields)+len(fields) > 0 {
+		e.Fields = make([]Field, 0, len(l.fields)+len(fields))
+		e.Fields = append(e.Fields, l.fields...)
+		e.Fields = append(e.Fields, fields...)
+	}
+	l.h.ring.Write(e)
+	for _, s := range l.h.sinks {
+		s.sink.Write(e)
+	}
+}
+
+// Debug writes a LevelDebug entry
+func (l *Logger) Debug(msg string, fields ...Field) {
+	l.Log(LevelDebug, msg, fields...)
+}
+
+// Info writes a LevelInfo entry
+func (l *Logger) Info(msg string, fields ...Field) {
+	l.Log(LevelInfo, msg, fields...)
+}
+
+// Warn writes a LevelWarn entry
+func (l *Logger) Warn(msg string, fields ...Field) {
+	l.Log(LevelWarn, msg, fields...)
+}
+
+// Error writes a LevelError entry
+func (l *Logger) Error(msg string, fields ...Field) {
+	l.Log(LevelError, msg, fields...)
+}
+
+type ctxKey struct{}
+
+// NewContext returns a context that carries the logger
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger of the context (or Default)
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Default
+}
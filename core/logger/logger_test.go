@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New(NewWriterSink(buf))
+	l.h.now = func() time.Time {
+		return time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	}
+	captured := make([]Entry, 0)
+	id := l.AddSink(SinkFunc(func(e Entry) {
+		captured = append(captured, e)
+	}))
+	io := l.Tag("io").With(F("container", 1))
+	io.Debug("skipped")
+	io.Error("open failed", F("file", "a b.png"), Err(errors.New("not found")))
+	assert.Equal(t, "10:30:00.000 ERROR [io] open failed container=1 file=\"a b.png\" err=\"not found\"\n", buf.String())
+	assert.Equal(t, 1, len(captured))
+	assert.Equal(t, "a b.png", captured[0].Field("file"))
+
+	l.SetTagLevel("io", LevelDebug)
+	io.Debug("verbose")
+	l.Tag("js").Debug("skipped")
+	assert.Equal(t, 2, len(captured))
+	assert.True(t, l.RemoveSink(id))
+	l.Warn("untagged")
+	assert.Equal(t, 2, len(captured))
+
+	recent := l.Recent(2)
+	assert.Equal(t, 2, len(recent))
+	assert.Equal(t, "verbose", recent[0].Message)
+	assert.Equal(t, "untagged", recent[1].Message)
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing(3)
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		r.Write(Entry{Message: msg})
+	}
+	assert.Equal(t, 3, r.Len())
+	last := r.Last(0)
+	assert.Equal(t, "b", last[0].Message)
+	assert.Equal(t, "d", last[2].Message)
+	assert.Equal(t, "c", r.Last(2)[0].Message)
+	r.Clear()
+	assert.Equal(t, 0, len(r.Last(5)))
+}
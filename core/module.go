@@ -1,5 +1,7 @@
 package core
 
+import "fmt"
+
 // Module defines a Primen Engine module
 type Module interface {
 	BeforeUpdate(ctx UpdateCtx)
@@ -7,3 +9,39 @@ type Module interface {
 	BeforeDraw(ctx DrawCtx)
 	AfterDraw(ctx DrawCtx)
 }
+
+// ModuleInit is implemented by modules that need to be initialized. Init is
+// called by AddModule, after the dependencies of the module are checked.
+// If Init returns an error, the module is not added.
+type ModuleInit interface {
+	Init(engine Engine) error
+}
+
+// ModuleShutdown is implemented by modules that need to release resources.
+// Shutdown is called by RemoveModule and when the engine exits (in the
+// reverse order the modules were added).
+type ModuleShutdown interface {
+	Shutdown()
+}
+
+// ModuleDependencies is implemented by modules that depend on other modules.
+// The dependencies must be added to the engine before the module.
+type ModuleDependencies interface {
+	// Dependencies returns the names of the required modules (see ModuleName)
+	Dependencies() []string
+}
+
+// NamedModule is implemented by modules with a custom name
+type NamedModule interface {
+	ModuleName() string
+}
+
+// ModuleName returns the name of a module (used by dependencies and by the
+// profiler). It is the ModuleName() of a NamedModule or the type name of the
+// module ("*imgui.uiModule").
+func ModuleName(m Module) string {
+	if nm, ok := m.(NamedModule); ok {
+		return nm.ModuleName()
+	}
+	return fmt.Sprintf("%T", m)
+}
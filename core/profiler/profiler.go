@@ -0,0 +1,374 @@
+// Package profiler measures the time spent on each engine module hook and
+// system call, and exports the captured frames to the Chrome trace event
+// format (chrome://tracing).
+package profiler
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Thread IDs used on the Chrome trace
+const (
+	UpdateThread = 1
+	DrawThread   = 2
+)
+
+// DefaultWindow is the default number of frames used to calculate the
+// rolling statistics
+const DefaultWindow = 120
+
+// ErrCapturing is returned by Capture (and DumpChromeTrace) while another
+// capture is in progress
+const ErrCapturing Error = "profiler: a capture is already in progress"
+
+// Error is a profiler error
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Stat is the rolling statistics of a profiled scope
+type Stat struct {
+	Name  string
+	Count int64 // total number of samples
+	Last  time.Duration
+	Min   time.Duration // min of the rolling window
+	Max   time.Duration // max of the rolling window
+	Avg   time.Duration // average of the rolling window
+}
+
+// FrameSample is the total time spent on an update or draw frame
+type FrameSample struct {
+	Frame  int64
+	Update time.Duration
+	Draw   time.Duration
+}
+
+// TraceEvent is a Chrome trace_event "complete" event
+type TraceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat,omitempty"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`  // microseconds
+	Duration  int64             `json:"dur"` // microseconds
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+type scope struct {
+	name    string
+	count   int64
+	samples []time.Duration
+	next    int
+}
+
+func (s *scope) add(d time.Duration, window int) {
+	s.count++
+	if len(s.samples) < window {
+		s.samples = append(s.samples, d)
+		s.next = len(s.samples) % window
+		return
+	}
+	s.samples[s.next] = d
+	s.next = (s.next + 1) % window
+}
+
+func (s *scope) stat() Stat {
+	st := Stat{
+		Name:  s.name,
+		Count: s.count,
+	}
+	if len(s.samples) < 1 {
+		return st
+	}
+	last := s.next - 1
+	if last < 0 {
+		last = len(s.samples) - 1
+	}
+	st.Last = s.samples[last]
+	st.Min = s.samples[0]
+	var total time.Duration
+	for _, v := range s.samples {
+		total += v
+		if v < st.Min {
+			st.Min = v
+		}
+		if v > st.Max {
+			st.Max = v
+		}
+	}
+	st.Avg = total / time.Duration(len(s.samples))
+	return st
+}
+
+// Profiler collects timings. It is safe for concurrent use.
+type Profiler struct {
+	l       sync.Mutex
+	enabled bool
+	window  int
+	epoch   time.Time
+	scopes  map[string]*scope
+	frames  []FrameSample
+	fnext   int
+	cur     FrameSample
+	// trace capture
+	capturing int
+	capdone   chan struct{}
+	events    []TraceEvent
+}
+
+// New creates a new (disabled) profiler. The window is the number of frames
+// used to calculate the rolling statistics.
+func New(window int) *Profiler {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Profiler{
+		window: window,
+		epoch:  time.Now(),
+		scopes: make(map[string]*scope),
+		frames: make([]FrameSample, 0, window),
+	}
+}
+
+// SetEnabled enables or disables the profiler
+func (p *Profiler) SetEnabled(enabled bool) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.enabled = enabled
+}
+
+// Enabled returns true if the profiler is collecting timings
+func (p *Profiler) Enabled() bool {
+	p.l.Lock()
+	defer p.l.Unlock()
+	return p.enabled
+}
+
+// Span is an in-progress measurement
+type Span struct {
+	p     *Profiler
+	name  string
+	cat   string
+	tid   int
+	args  map[string]string
+	start time.Time
+}
+
+// Begin starts measuring a scope. Call End on the returned span.
+// It returns a noop span if the profiler is disabled.
+func (p *Profiler) Begin(name, category string, tid int, args map[string]string) Span {
+	if !p.Enabled() {
+		return Span{}
+	}
+	return Span{
+		p:     p,
+		name:  name,
+		cat:   category,
+		tid:   tid,
+		args:  args,
+		start: time.Now(),
+	}
+}
+
+// End finishes the measurement and returns the elapsed time
+func (s Span) End() time.Duration {
+	if s.p == nil {
+		return 0
+	}
+	d := time.Since(s.start)
+	s.p.record(s, d)
+	return d
+}
+
+func (p *Profiler) record(s Span, d time.Duration) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	sc := p.scopes[s.name]
+	if sc == nil {
+		sc = &scope{
+			name:    s.name,
+			samples: make([]time.Duration, 0, p.window),
+		}
+		p.scopes[s.name] = sc
+	}
+	sc.add(d, p.window)
+	if p.capturing > 0 {
+		p.events = append(p.events, TraceEvent{
+			Name:      s.name,
+			Category:  s.cat,
+			Phase:     "X",
+			Timestamp: s.start.Sub(p.epoch).Microseconds(),
+			Duration:  d.Microseconds(),
+			PID:       1,
+			TID:       s.tid,
+			Args:      s.args,
+		})
+	}
+}
+
+// EndUpdateFrame adds the total time of an update frame to the current frame
+// sample (the engine may run more than one update per draw). It also counts
+// down the frames being captured.
+func (p *Profiler) EndUpdateFrame(frame int64, d time.Duration) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.cur.Frame = frame
+	p.cur.Update += d
+	if p.capturing > 0 {
+		p.capturing--
+		if p.capturing == 0 && p.capdone != nil {
+			close(p.capdone)
+			p.capdone = nil
+		}
+	}
+}
+
+// EndDrawFrame stores the total time of a draw frame and pushes the frame
+// sample to the history.
+func (p *Profiler) EndDrawFrame(d time.Duration) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.cur.Draw = d
+	if len(p.frames) < p.window {
+		p.frames = append(p.frames, p.cur)
+		p.fnext = len(p.frames) % p.window
+	} else {
+		p.frames[p.fnext] = p.cur
+		p.fnext = (p.fnext + 1) % p.window
+	}
+	p.cur = FrameSample{}
+}
+
+// Frames returns the frame history (oldest first)
+func (p *Profiler) Frames() []FrameSample {
+	p.l.Lock()
+	defer p.l.Unlock()
+	out := make([]FrameSample, 0, len(p.frames))
+	if len(p.frames) < p.window {
+		return append(out, p.frames...)
+	}
+	out = append(out, p.frames[p.fnext:]...)
+	return append(out, p.frames[:p.fnext]...)
+}
+
+// Stats returns the statistics of every scope, sorted by the average time
+// (slowest first).
+func (p *Profiler) Stats() []Stat {
+	p.l.Lock()
+	defer p.l.Unlock()
+	out := make([]Stat, 0, len(p.scopes))
+	for _, v := range p.scopes {
+		out = append(out, v.stat())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Avg == out[j].Avg {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Avg > out[j].Avg
+	})
+	return out
+}
+
+// Stat returns the statistics of a scope
+func (p *Profiler) Stat(name string) (Stat, bool) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	if v, ok := p.scopes[name]; ok {
+		return v.stat(), true
+	}
+	return Stat{}, false
+}
+
+// Reset clears all statistics and captured events
+func (p *Profiler) Reset() {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.scopes = make(map[string]*scope)
+	p.frames = make([]FrameSample, 0, p.window)
+	p.fnext = 0
+	p.events = nil
+}
+
+// Capture starts recording the next n update frames (and the draw frames in
+// between) as trace events. The profiler is enabled if needed. The returned
+// channel is closed when the capture is done. It returns ErrCapturing if
+// another capture is in progress (the captured events are kept).
+func (p *Profiler) Capture(n int) (<-chan struct{}, error) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	if p.capdone != nil {
+		return nil, ErrCapturing
+	}
+	p.enabled = true
+	p.events = make([]TraceEvent, 0, 1024)
+	ch := make(chan struct{})
+	if n <= 0 {
+		close(ch)
+		p.capturing = 0
+		return ch, nil
+	}
+	p.capdone = ch
+	p.capturing = n
+	return ch, nil
+}
+
+// Capturing returns true while a capture is in progress
+func (p *Profiler) Capturing() bool {
+	p.l.Lock()
+	defer p.l.Unlock()
+	return p.capturing > 0
+}
+
+// DumpChromeTrace captures the next n update frames and writes them to a
+// Chrome trace_event JSON file. The returned channel receives the result
+// once the file is written (or ErrCapturing if another capture is in
+// progress).
+func (p *Profiler) DumpChromeTrace(n int, filename string) <-chan error {
+	errc := make(chan error, 1)
+	done, err := p.Capture(n)
+	if err != nil {
+		errc <- err
+		return errc
+	}
+	go func() {
+		<-done
+		f, err := os.Create(filename)
+		if err != nil {
+			errc <- err
+			return
+		}
+		if err := p.WriteChromeTrace(f); err != nil {
+			f.Close()
+			errc <- err
+			return
+		}
+		errc <- f.Close()
+	}()
+	return errc
+}
+
+type chromeTrace struct {
+	TraceEvents     []TraceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// WriteChromeTrace writes the captured events as a Chrome trace_event JSON
+// file (open it with chrome://tracing).
+func (p *Profiler) WriteChromeTrace(w io.Writer) error {
+	p.l.Lock()
+	events := make([]TraceEvent, len(p.events))
+	copy(events, p.events)
+	p.l.Unlock()
+	return json.NewEncoder(w).Encode(chromeTrace{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ms",
+	})
+}
@@ -0,0 +1,63 @@
+package profiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfilerStats(t *testing.T) {
+	p := New(3)
+	p.Begin("a", "system", UpdateThread, nil).End()
+	_, ok := p.Stat("a")
+	assert.False(t, ok) // disabled
+	p.SetEnabled(true)
+	for i := 0; i < 5; i++ {
+		p.record(Span{name: "a"}, time.Duration(i+1)*time.Millisecond)
+	}
+	st, ok := p.Stat("a")
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), st.Count)
+	assert.Equal(t, 5*time.Millisecond, st.Last)
+	assert.Equal(t, 3*time.Millisecond, st.Min)
+	assert.Equal(t, 5*time.Millisecond, st.Max)
+	assert.Equal(t, 4*time.Millisecond, st.Avg)
+	for i := 0; i < 4; i++ {
+		p.EndUpdateFrame(int64(i+1), time.Millisecond)
+		p.EndDrawFrame(time.Millisecond)
+	}
+	frames := p.Frames()
+	assert.Equal(t, 3, len(frames))
+	assert.Equal(t, int64(2), frames[0].Frame)
+	assert.Equal(t, int64(4), frames[2].Frame)
+}
+
+func TestProfilerCapture(t *testing.T) {
+	p := New(0)
+	done, err := p.Capture(2)
+	assert.NoError(t, err)
+	assert.True(t, p.Enabled())
+	p.Begin("TransformSystem.Update", "system", UpdateThread, map[string]string{"world": "0"}).End()
+	p.EndUpdateFrame(1, time.Millisecond)
+	// a new capture doesn't hijack the pending one
+	_, err = p.Capture(5)
+	assert.Equal(t, ErrCapturing, err)
+	assert.Equal(t, ErrCapturing, <-p.DumpChromeTrace(5, "unused.json"))
+	p.Begin("TransformSystem.Update", "system", UpdateThread, nil).End()
+	p.EndUpdateFrame(2, time.Millisecond)
+	<-done
+	assert.False(t, p.Capturing())
+	p.Begin("ignored", "system", UpdateThread, nil).End()
+	buf := new(bytes.Buffer)
+	assert.NoError(t, p.WriteChromeTrace(buf))
+	v := struct {
+		TraceEvents []TraceEvent `json:"traceEvents"`
+	}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	assert.Equal(t, 2, len(v.TraceEvents))
+	assert.Equal(t, "X", v.TraceEvents[0].Phase)
+	assert.Equal(t, "0", v.TraceEvents[0].Args["world"])
+}
@@ -39,6 +39,16 @@ func (a Attributes) IntD(name string, defaultv int) int {
 	return v
 }
 
+// FloatD retrieves the attribute [name]. Returns [defaultv] if not found
+func (a Attributes) FloatD(name string, defaultv float64) float64 {
+	vs := a[name]
+	if vs == "" {
+		return defaultv
+	}
+	v, _ := strconv.ParseFloat(vs, 64)
+	return v
+}
+
 // String returns an attribute value
 func (a Attributes) String(name string) string {
 	return a[name]
@@ -0,0 +1,65 @@
+package easing
+
+import "reflect"
+
+var named = map[string]Function{
+	"Linear":       Linear,
+	"InQuad":       InQuad,
+	"OutQuad":      OutQuad,
+	"InOutQuad":    InOutQuad,
+	"InCubic":      InCubic,
+	"OutCubic":     OutCubic,
+	"InOutCubic":   InOutCubic,
+	"InQuart":      InQuart,
+	"OutQuart":     OutQuart,
+	"InOutQuart":   InOutQuart,
+	"InQuint":      InQuint,
+	"OutQuint":     OutQuint,
+	"InOutQuint":   InOutQuint,
+	"InSine":       InSine,
+	"OutSine":      OutSine,
+	"InOutSine":    InOutSine,
+	"InExpo":       InExpo,
+	"OutExpo":      OutExpo,
+	"InOutExpo":    InOutExpo,
+	"InCirc":       InCirc,
+	"OutCirc":      OutCirc,
+	"InOutCirc":    InOutCirc,
+	"InElastic":    InElastic,
+	"OutElastic":   OutElastic,
+	"InOutElastic": InOutElastic,
+	"InBack":       InBack,
+	"OutBack":      OutBack,
+	"InOutBack":    InOutBack,
+	"InBounce":     InBounce,
+	"OutBounce":    OutBounce,
+	"InOutBounce":  InOutBounce,
+	"InSquare":     InSquare,
+	"OutSquare":    OutSquare,
+	"InOutSquare":  InOutSquare,
+}
+
+// Register adds a named easing function (used to serialize tweens)
+func Register(name string, fn Function) {
+	named[name] = fn
+}
+
+// ByName returns an easing function by name (nil if not found)
+func ByName(name string) Function {
+	return named[name]
+}
+
+// Name returns the name of a registered easing function ("" if the function
+// is not registered, e.g. a closure returned by InElasticFunction)
+func Name(fn Function) string {
+	if fn == nil {
+		return ""
+	}
+	p := reflect.ValueOf(fn).Pointer()
+	for k, v := range named {
+		if reflect.ValueOf(v).Pointer() == p {
+			return k
+		}
+	}
+	return ""
+}
@@ -2,17 +2,21 @@ package primen
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"image/color"
+	"math"
 	"os"
 	"path"
+	"reflect"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/gabstv/ecs/v2"
 	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/input"
+	"github.com/gabstv/primen/core/logger"
+	"github.com/gabstv/primen/core/profiler"
 	"github.com/gabstv/primen/geom"
 	"github.com/gabstv/primen/io"
 	osfs "github.com/gabstv/primen/io/os"
@@ -71,12 +75,35 @@ type engine struct {
 	eventManager *core.EventManager
 	debugfps     bool
 	debugtps     bool
+	debugprof    bool
+	profiler     *profiler.Profiler
+	input        *input.Manager
 	sceneldrs    map[string]NewSceneFn
 	runfns       chan func()
 	runctx       context.Context
 	exits        bool
-
-	lastScn          Scene
+	headless     bool
+	fixedStep    float64
+	maxFixedStep int
+	accumulator  float64
+	alpha        float64
+
+	lastModuleSeq   int
+	shutdownHooks   []func()
+	shutdownOnce    sync.Once
+	shutdownTimeout time.Duration
+	config          *Config
+	configLock      sync.Mutex // serializes UpdateConfig
+	configPath      string
+	log             *logger.Logger
+
+	inputRecorder   *input.Recorder
+	inputPlayer     *input.Player
+	inputReplaySrc  input.Source
+	inputReplayDone chan struct{}
+
+	scenes           []Scene
+	sceneChange      *sceneChange
 	drawTargetLock   sync.Mutex
 	drawTargets      []EngineDrawTarget
 	lastDrawTargetID core.DrawTargetID
@@ -100,6 +127,13 @@ type NewEngineInput struct {
 	FS                io.Filesystem  // the filesystem that the Scenes will use
 	OnReady           func(e Engine) // function to run once the window is opened
 	Scene             string         // Autoloads a starting scene on ready
+	FixedTimestep     float64        // fixed update delta (in seconds); 0 disables the fixed-timestep mode
+	MaxFixedSteps     int            // max fixed updates per tick (default: 5)
+	InputSource       input.Source   // input devices (default: ebiten; a virtual source on headless engines)
+	ShutdownTimeout   time.Duration  // max time to wait for each scene to unload on exit (default: 5s)
+	Config            *Config        // settings applied on ready (TPS, debug flags, volumes and bindings)
+	ConfigPath        string         // where the config is written back (see Engine.UpdateConfig)
+	Logger            *logger.Logger // engine logger (default: logger.Default)
 }
 
 // EngineOptions is used to setup Ebiten @ Engine.boot
@@ -138,6 +172,10 @@ func (i *NewEngineInput) Options() EngineOptions {
 
 // NewEngine returns a new Engine
 func NewEngine(v *NewEngineInput) Engine {
+	return newEngine(v)
+}
+
+func newEngine(v *NewEngineInput) *engine {
 	fbase := ""
 	if len(os.Args) > 0 {
 		fbase = path.Dir(os.Args[0])
@@ -155,6 +193,7 @@ func NewEngine(v *NewEngineInput) Engine {
 			MaxResolution:     false,
 			TransparentScreen: false,
 			Floating:          false,
+			ShutdownTimeout:   time.Second * 5,
 		}
 	} else {
 		if v.Scale <= 0 {
@@ -169,6 +208,18 @@ func NewEngine(v *NewEngineInput) Engine {
 		if v.FS == nil {
 			v.FS = osfs.New(fbase)
 		}
+		if v.FixedTimestep < 0 {
+			v.FixedTimestep = 0
+		}
+		if v.MaxFixedSteps <= 0 {
+			v.MaxFixedSteps = 5
+		}
+		if v.ShutdownTimeout <= 0 {
+			v.ShutdownTimeout = time.Second * 5
+		}
+	}
+	if v.InputSource == nil {
+		v.InputSource = input.EbitenSource{}
 	}
 	// assign the default systems and controllers
 	calcW, calcH := int(float64(v.Width)*v.Scale), int(float64(v.Height)*v.Scale)
@@ -199,10 +250,24 @@ func NewEngine(v *NewEngineInput) Engine {
 		ebiOutsideH:  v.Height,
 		ebiScale:     v.Scale,
 		eventManager: &core.EventManager{},
+		profiler:     profiler.New(profiler.DefaultWindow),
+		input:        input.New(v.InputSource),
 		runfns:       make(chan func(), 128),
 		runctx:       context.Background(), // redefined on Run()
 		drawTargets:  make([]EngineDrawTarget, 0, 8),
+		fixedStep:    v.FixedTimestep,
+		maxFixedStep: v.MaxFixedSteps,
+		alpha:        1,
+	}
+
+	e.shutdownTimeout = v.ShutdownTimeout
+	e.config = v.Config
+	e.configPath = v.ConfigPath
+	e.log = v.Logger
+	if e.log == nil {
+		e.log = logger.Default
 	}
+	e.runctx = logger.NewContext(e.runctx, e.log)
 
 	e.loadScenes() // load all registered scenes constructor
 
@@ -288,14 +353,105 @@ func (e *engine) Ctx() context.Context {
 	return e.runctx
 }
 
-func (e *engine) AddModule(module core.Module, priority int) {
+// AddModule adds a module to the engine. The priority is used to sort module
+// execution, from high to low.
+//
+// The dependencies of the module (core.ModuleDependencies) must be added
+// first. If the module implements core.ModuleInit, Init is called before the
+// module is added. It returns ErrModuleExists if the module (or another
+// core.NamedModule with the same name) was already added; many instances
+// of an unnamed module type can be added.
+func (e *engine) AddModule(module core.Module, priority int) error {
+	name := core.ModuleName(module)
+	_, named := module.(core.NamedModule)
+	e.lock.Lock()
+	for _, m := range e.modules {
+		if (named && m.name == name) || sameModule(m.module, module) {
+			e.lock.Unlock()
+			return fmt.Errorf("%s: %w", name, ErrModuleExists)
+		}
+	}
+	if d, ok := module.(core.ModuleDependencies); ok {
+		for _, dep := range d.Dependencies() {
+			if !e.hasModule(dep) {
+				e.lock.Unlock()
+				return fmt.Errorf("%s requires %s: %w", name, dep, ErrModuleMissing)
+			}
+		}
+	}
+	e.lock.Unlock()
+	if mi, ok := module.(core.ModuleInit); ok {
+		if err := mi.Init(e); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
 	e.lock.Lock()
 	defer e.lock.Unlock()
+	e.lastModuleSeq++
 	e.modules = append(e.modules, moduleContainer{
 		module:   module,
 		priority: priority,
+		name:     name,
+		seq:      e.lastModuleSeq,
 	})
 	sort.Sort(sortedModuleContainer(e.modules))
+	return nil
+}
+
+func sameModule(a, b core.Module) bool {
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// RemoveModule removes a module from the engine. It fails if another module
+// depends on it. If the module implements core.ModuleShutdown, Shutdown is
+// called after the module is removed.
+func (e *engine) RemoveModule(module core.Module) error {
+	e.lock.Lock()
+	mi := -1
+	for k, m := range e.modules {
+		if m.module == module {
+			mi = k
+			break
+		}
+	}
+	if mi == -1 {
+		e.lock.Unlock()
+		return ErrModuleNotFound
+	}
+	name := e.modules[mi].name
+	for _, m := range e.modules {
+		if d, ok := m.module.(core.ModuleDependencies); ok {
+			for _, dep := range d.Dependencies() {
+				if dep == name {
+					e.lock.Unlock()
+					return fmt.Errorf("%s is required by %s: %w", name, m.name, ErrModuleRequired)
+				}
+			}
+		}
+	}
+	// splice (a new slice; update may be iterating over the old one)
+	modules := make([]moduleContainer, 0, len(e.modules)-1)
+	modules = append(modules, e.modules[:mi]...)
+	e.modules = append(modules, e.modules[mi+1:]...)
+	e.lock.Unlock()
+	if ms, ok := module.(core.ModuleShutdown); ok {
+		ms.Shutdown()
+	}
+	return nil
+}
+
+// hasModule must be called with the engine lock held
+func (e *engine) hasModule(name string) bool {
+	for _, m := range e.modules {
+		if m.name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Run boots up the game engine
@@ -308,6 +464,16 @@ func (e *engine) Run() error {
 	e.drawInfo.Set(now, 0)
 	e.updateInfo.Set(now, 0)
 
+	if e.config != nil && e.config.TPS > 0 {
+		ebiten.SetMaxTPS(e.config.TPS)
+	}
+
+	if e.headless {
+		err := e.runHeadless()
+		e.Shutdown()
+		return err
+	}
+
 	ebiten.SetScreenTransparent(e.options.IsTransparentScreen)
 	ebiten.SetFullscreen(e.options.IsFullscreen)
 	ebiten.SetWindowResizable(e.options.IsResizable)
@@ -326,7 +492,12 @@ func (e *engine) Run() error {
 	}
 	ebiten.SetWindowSize(e.options.Width, e.options.Height)
 	ebiten.SetWindowTitle(e.options.Title)
-	return ebiten.RunGame(e)
+	err := ebiten.RunGame(e)
+	e.Shutdown()
+	if err == ErrRegularTermination {
+		return nil
+	}
+	return err
 }
 
 // Ready returns a channel that signals when the engine is ready
@@ -410,9 +581,43 @@ func (e *engine) SetScreenScale(scale float64) {
 }
 
 func (e *engine) Update(screen *ebiten.Image) error {
-	lastt, lastf := e.updateInfo.Get()
+	lastt, _ := e.updateInfo.Get()
 	now := time.Now()
-	delta := now.Sub(lastt).Seconds()
+	if e.fixedStep > 0 {
+		return e.fixedUpdate(now, now.Sub(lastt).Seconds())
+	}
+	return e.update(now, now.Sub(lastt).Seconds(), ebiten.CurrentTPS())
+}
+
+// fixedUpdate accumulates the elapsed time and runs as many fixed updates as
+// needed, up to maxFixedStep per tick. The leftover time is exposed to the
+// draw systems as the interpolation alpha.
+func (e *engine) fixedUpdate(now time.Time, delta float64) error {
+	e.accumulator += delta
+	steps := 0
+	for e.accumulator >= e.fixedStep {
+		if steps >= e.maxFixedStep {
+			// drop the excess to avoid a spiral of death after a long hitch
+			e.accumulator = math.Mod(e.accumulator, e.fixedStep)
+			break
+		}
+		if err := e.update(now, e.fixedStep, 1/e.fixedStep); err != nil {
+			return err
+		}
+		e.accumulator -= e.fixedStep
+		steps++
+	}
+	// the clock must advance even if no fixed update ran on this tick
+	_, frame := e.updateInfo.Get()
+	e.updateInfo.Set(now, frame)
+	e.alpha = e.accumulator / e.fixedStep
+	return nil
+}
+
+// update runs a single logic frame. It is shared by the ebiten game loop and
+// the headless engine (which feeds a virtual clock).
+func (e *engine) update(now time.Time, delta, tps float64) error {
+	_, lastf := e.updateInfo.Get()
 	e.lock.Lock()
 	worlds := e.worlds
 	modules := e.modules
@@ -420,15 +625,20 @@ func (e *engine) Update(screen *ebiten.Image) error {
 	e.lock.Unlock()
 	frame := lastf + 1
 	e.updateInfo.Set(now, frame)
+	profiling := e.profiler.Enabled()
+	fstart := time.Now()
 
 	e.once.Do(func() {
 		close(e.donech)
 		if e.ready != nil {
 			e.ready(e)
 		}
+		if e.config != nil {
+			e.applyConfig(e.config, true)
+		}
 		if e.startScene != "" {
 			if _, _, err := e.LoadScene(e.startScene); err != nil {
-				println("ERROR STARTING SCENE: " + err.Error())
+				e.log.Tag("scene").Error("starting scene failed", logger.F("scene", e.startScene), logger.Err(err))
 			}
 		}
 	})
@@ -439,32 +649,53 @@ func (e *engine) Update(screen *ebiten.Image) error {
 	default:
 	}
 
-	ctx := core.NewUpdateCtx(e, frame, delta, ebiten.CurrentTPS())
+	delta = e.updateInput(delta)
+
+	// deliver the events queued since the last frame
+	e.eventManager.Flush()
+
+	e.updateSceneChange(delta)
+
+	ctx := core.NewUpdateCtx(e, frame, delta, tps)
 
 	for _, modulec := range modules {
+		sp := e.beginModuleSpan(profiling, modulec, ".BeforeUpdate", profiler.UpdateThread)
 		modulec.module.BeforeUpdate(ctx)
+		sp.End()
 	}
 
-	for _, w := range worlds {
+	for i, w := range worlds {
 		if !w.world.Enabled() {
 			continue
 		}
+		wname := profWorldName(profiling, i)
+		wctx := core.NewScaledUpdateCtx(e, frame, delta, tps, w.world.AdvanceTime(delta))
 		w.world.EachSystem(func(s ecs.BaseSystem) bool {
-			s.(core.System).UpdatePriority(ctx)
+			sp := e.beginSystemSpan(profiling, wname, s, ".UpdatePriority", profiler.UpdateThread)
+			s.(core.System).UpdatePriority(wctx)
+			sp.End()
 			return true
 		})
 		w.world.EachSystem(func(s ecs.BaseSystem) bool {
-			s.(core.System).Update(ctx)
+			sp := e.beginSystemSpan(profiling, wname, s, ".Update", profiler.UpdateThread)
+			s.(core.System).Update(wctx)
+			sp.End()
 			return true
 		})
 	}
 
 	for _, modulec := range modules {
+		sp := e.beginModuleSpan(profiling, modulec, ".AfterUpdate", profiler.UpdateThread)
 		modulec.module.AfterUpdate(ctx)
+		sp.End()
+	}
+
+	if profiling {
+		e.profiler.EndUpdateFrame(frame, time.Since(fstart))
 	}
 
 	if exits {
-		return errors.New("regular termination") // ebiten checks for this string
+		return ErrRegularTermination
 	}
 
 	return nil
@@ -481,26 +712,34 @@ func (e *engine) Draw(screen *ebiten.Image) {
 	e.lock.Unlock()
 	frame := lastf + 1
 	e.drawInfo.Set(now, frame)
+	profiling := e.profiler.Enabled()
 
 	mgr := e.newDrawManager(screen)
-	ctx := core.NewDrawCtx(e, frame, delta, ebiten.CurrentTPS(), mgr)
+	ctx := core.NewInterpolatedDrawCtx(e, frame, delta, ebiten.CurrentTPS(), e.alpha, mgr)
 
 	mgr.PrepareTargets()
 
 	for _, modulec := range modules {
+		sp := e.beginModuleSpan(profiling, modulec, ".BeforeDraw", profiler.DrawThread)
 		modulec.module.BeforeDraw(ctx)
+		sp.End()
 	}
 
-	for _, w := range worlds {
+	for i, w := range worlds {
 		if !w.world.Enabled() {
 			continue
 		}
+		wname := profWorldName(profiling, i)
 		w.world.EachSystem(func(s ecs.BaseSystem) bool {
+			sp := e.beginSystemSpan(profiling, wname, s, ".DrawPriority", profiler.DrawThread)
 			s.(core.System).DrawPriority(ctx)
+			sp.End()
 			return true
 		})
 		w.world.EachSystem(func(s ecs.BaseSystem) bool {
+			sp := e.beginSystemSpan(profiling, wname, s, ".Draw", profiler.DrawThread)
 			s.(core.System).Draw(ctx)
+			sp.End()
 			return true
 		})
 	}
@@ -508,7 +747,9 @@ func (e *engine) Draw(screen *ebiten.Image) {
 	mgr.DrawTargets()
 
 	for _, modulec := range modules {
+		sp := e.beginModuleSpan(profiling, modulec, ".AfterDraw", profiler.DrawThread)
 		modulec.module.AfterDraw(ctx)
+		sp.End()
 	}
 
 	e.lock.Lock()
@@ -530,12 +771,20 @@ func (e *engine) Draw(screen *ebiten.Image) {
 		e.lock.Unlock()
 	}
 
+	e.drawSceneChange(screen)
+
 	if e.debugfps {
 		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("FPS: %.2f", ebiten.CurrentFPS()), 10, 10)
 	}
 	if e.debugtps {
 		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("TPS: %.2f", ebiten.CurrentTPS()), 10, 22)
 	}
+	if profiling {
+		e.profiler.EndDrawFrame(time.Since(now))
+	}
+	if e.debugprof {
+		e.drawProfilerOverlay(screen)
+	}
 
 	select {
 	case grabrq := <-e.screencopych:
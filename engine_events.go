@@ -5,9 +5,37 @@ import "github.com/gabstv/primen/core"
 func (e *engine) AddEventListener(eventName string, fn core.EventFn) core.EventID {
 	return e.eventManager.Register(eventName, fn)
 }
+
+// AddEventListenerWithOptions adds an event listener with a priority and/or
+// as a one-shot listener.
+func (e *engine) AddEventListenerWithOptions(eventName string, fn core.EventFn, opt core.ListenerOptions) core.EventID {
+	return e.eventManager.RegisterWithOptions(eventName, fn, opt)
+}
+
+// AddCatchAllEventListener adds a listener that is called on every event.
+func (e *engine) AddCatchAllEventListener(fn core.EventFn) core.EventID {
+	return e.eventManager.RegisterCatchAll(fn)
+}
+
 func (e *engine) RemoveEventListener(id core.EventID) bool {
 	return e.eventManager.Deregister(id)
 }
+
+// RemoveEventListenersByOwner removes every listener registered with the
+// owner token (core.ListenerOptions.Owner).
+func (e *engine) RemoveEventListenersByOwner(owner interface{}) int {
+	return e.eventManager.DeregisterOwner(owner)
+}
+
+// DispatchEvent queues an event to be delivered on the next frame (on the
+// main thread). If the dispatch mode is core.EventDispatchAsync, the
+// listeners are called immediately on new goroutines.
 func (e *engine) DispatchEvent(eventName string, data interface{}) {
 	e.eventManager.Dispatch(eventName, e, data)
 }
+
+// SetEventDispatchMode sets how the engine delivers events.
+// The default mode is core.EventDispatchQueued.
+func (e *engine) SetEventDispatchMode(mode core.EventDispatchMode) {
+	e.eventManager.SetMode(mode)
+}
@@ -0,0 +1,102 @@
+package primen
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/core/profiler"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+var (
+	profUpdateColor = color.RGBA{R: 80, G: 200, B: 80, A: 200}
+	profDrawColor   = color.RGBA{R: 80, G: 120, B: 230, A: 200}
+	profBudgetColor = color.RGBA{R: 230, G: 60, B: 60, A: 220}
+)
+
+const (
+	profGraphX      = 10
+	profGraphY      = 40
+	profGraphHeight = 60
+	profGraphMaxMs  = 33.3 // the height of the graph represents 2 frames @ 60 TPS
+	profTopScopes   = 6
+)
+
+// Profiler returns the frame profiler of the engine. The profiler is
+// disabled by default (enable it with Profiler().SetEnabled(true) or
+// Profiler().Capture(n)).
+func (e *engine) Profiler() *profiler.Profiler {
+	return e.profiler
+}
+
+// SetDebugProfiler shows (or hides) the frame time graph and the slowest
+// systems on screen. The profiler is enabled if v is true.
+func (e *engine) SetDebugProfiler(v bool) {
+	e.debugprof = v
+	if v {
+		e.profiler.SetEnabled(true)
+	}
+}
+
+func profWorldName(profiling bool, index int) string {
+	if !profiling {
+		return ""
+	}
+	return "world" + strconv.Itoa(index)
+}
+
+func (e *engine) beginModuleSpan(profiling bool, m moduleContainer, hook string, tid int) profiler.Span {
+	if !profiling {
+		return profiler.Span{}
+	}
+	return e.profiler.Begin(m.name+hook, "module", tid, nil)
+}
+
+func (e *engine) beginSystemSpan(profiling bool, world string, s ecs.BaseSystem, fn string, tid int) profiler.Span {
+	if !profiling {
+		return profiler.Span{}
+	}
+	name := ""
+	if ns, ok := s.(interface{ Name() string }); ok {
+		name = ns.Name()
+	} else {
+		name = fmt.Sprintf("%T", s)
+	}
+	return e.profiler.Begin(world+"/"+name+fn, "system", tid, nil)
+}
+
+func profMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (e *engine) drawProfilerOverlay(screen *ebiten.Image) {
+	pxms := profGraphHeight / profGraphMaxMs
+	y0 := float64(profGraphY + profGraphHeight)
+	for i, f := range e.profiler.Frames() {
+		x := float64(profGraphX + i)
+		uh := profMs(f.Update) * pxms
+		dh := profMs(f.Draw) * pxms
+		if uh > 0 {
+			ebitenutil.DrawRect(screen, x, y0-uh, 1, uh, profUpdateColor)
+		}
+		if dh > 0 {
+			ebitenutil.DrawRect(screen, x, y0-uh-dh, 1, dh, profDrawColor)
+		}
+	}
+	tps := ebiten.MaxTPS()
+	if tps > 0 {
+		by := y0 - (1000/float64(tps))*pxms
+		ebitenutil.DrawLine(screen, profGraphX, by, profGraphX+profiler.DefaultWindow, by, profBudgetColor)
+	}
+	stats := e.profiler.Stats()
+	if len(stats) > profTopScopes {
+		stats = stats[:profTopScopes]
+	}
+	for i, st := range stats {
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%6.2fms %s", profMs(st.Avg), st.Name), profGraphX, int(y0)+4+i*12)
+	}
+}
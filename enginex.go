@@ -5,6 +5,9 @@ import (
 	"sort"
 
 	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/input"
+	"github.com/gabstv/primen/core/logger"
+	"github.com/gabstv/primen/core/profiler"
 	"github.com/gabstv/primen/geom"
 	"github.com/gabstv/primen/io"
 	"github.com/hajimehoshi/ebiten"
@@ -14,10 +17,17 @@ type Engine interface {
 	core.Engine
 	Ctx() context.Context
 	Exit()
+	OnShutdown(fn func())
+	Config() *Config
+	UpdateConfig(fn func(c *Config)) error
 	FS() io.Filesystem
 	LoadScene(name string) (scene Scene, sig chan struct{}, err error)
 	RunFn(fn func())
 	LastLoadedScene() Scene
+	PushScene(name string, tr Transition) (done chan struct{}, err error)
+	PopScene(tr Transition) (done chan struct{}, err error)
+	ReplaceScene(name string, tr Transition) (done chan struct{}, err error)
+	SceneStack() []Scene
 	NewContainer() io.Container
 	WaitAndGrabScreenImage() ScreenCopyRequest
 	AddTempDrawFn(priority int, fn func(ctx core.DrawCtx) bool)
@@ -26,6 +36,10 @@ type Engine interface {
 	NewProgrammableDrawTarget(input ProgrammableDrawTargetInput) core.DrawTargetID
 	DrawTarget(id core.DrawTargetID) core.DrawTarget
 	RemoveDrawTarget(id core.DrawTargetID) bool
+	Profiler() *profiler.Profiler
+	RecordInput(seed int64)
+	StopInputRecording() *input.Recording
+	ReplayInput(rec *input.Recording) <-chan struct{}
 }
 
 type DrawCtx = core.DrawCtx
@@ -46,10 +60,24 @@ func (e *engine) Exit() {
 	e.exits = true
 }
 
+// Logger returns the engine logger
+func (e *engine) Logger() *logger.Logger {
+	return e.log
+}
+
+// Input returns the input manager. It's updated at the beginning of each
+// update frame.
+func (e *engine) Input() *input.Manager {
+	return e.input
+}
+
 func (e *engine) LastLoadedScene() Scene {
 	e.lock.Lock()
 	defer e.lock.Unlock()
-	return e.lastScn
+	if n := len(e.scenes); n > 0 {
+		return e.scenes[n-1]
+	}
+	return nil
 }
 
 // NewContainer is a shorthand of io.NewContainer(engine.Ctx(), engine.FS())
@@ -7,5 +7,14 @@ func (e Error) Error() string {
 }
 
 const (
-	ErrSceneNotFound Error = "scene not found"
+	ErrSceneNotFound   Error = "scene not found"
+	ErrSceneStackEmpty Error = "scene stack is empty"
+	ErrSceneTransition Error = "scene transition in progress"
+	ErrModuleNotFound  Error = "module not found"
+	ErrModuleExists    Error = "module already added"
+	ErrModuleMissing   Error = "module dependency not found"
+	ErrModuleRequired  Error = "module is required by another module"
+	// ErrRegularTermination is returned by Update when Exit is called.
+	// Ebiten checks for this exact string to stop the game loop.
+	ErrRegularTermination Error = "regular termination"
 )
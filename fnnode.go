@@ -18,6 +18,15 @@ func NewRootFnNode(w World) *FnNode {
 	return n
 }
 
+func NewChildFnNode(parent ObjectContainer) *FnNode {
+	n := &FnNode{
+		Node: NewChildNode(parent),
+	}
+	components.SetFunctionComponentData(n.w, n.e, components.Function{})
+	n.wf = components.WatchFunctionComponentData(n.w, n.e)
+	return n
+}
+
 // Function retrieves the function component data
 func (n *FnNode) Function() *components.Function {
 	return n.wf.Data()
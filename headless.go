@@ -0,0 +1,88 @@
+package primen
+
+import (
+	"time"
+
+	"github.com/gabstv/primen/core/input"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// HeadlessEngine is an Engine that doesn't open a window. The frames are
+// stepped manually (or by Run, using a fixed delta) so it is suitable for
+// tests and servers.
+//
+// Draw systems are never called by a HeadlessEngine.
+type HeadlessEngine interface {
+	Engine
+	// Step runs a single update frame with a fixed delta (in seconds).
+	// It returns ErrRegularTermination after Exit is called.
+	Step(dt float64) error
+	// StepN runs n update frames with the same fixed delta (in seconds).
+	// It stops at the first error.
+	StepN(n int, dt float64) error
+	// Shutdown unloads the scenes and shuts down the modules (Run calls it
+	// on exit).
+	Shutdown()
+}
+
+// NewHeadlessEngine returns a new HeadlessEngine. The input is the same
+// used by NewEngine; the window related fields are only used to calculate
+// the logical screen size.
+func NewHeadlessEngine(v *NewEngineInput) HeadlessEngine {
+	virtualInput := v == nil || v.InputSource == nil
+	e := newEngine(v)
+	e.headless = true
+	if virtualInput {
+		e.input.SetSource(input.NewVirtualSource())
+	}
+	return e
+}
+
+// Step runs the modules and the world systems once, with a fixed delta.
+//
+// The update clock is virtual, so the same sequence of steps always produces
+// the same sequence of update contexts.
+func (e *engine) Step(dt float64) error {
+	lastt, _ := e.updateInfo.Get()
+	now := lastt.Add(time.Duration(dt * float64(time.Second)))
+	tps := 0.0
+	if dt > 0 {
+		tps = 1 / dt
+	}
+	return e.update(now, dt, tps)
+}
+
+// StepN calls Step n times.
+func (e *engine) StepN(n int, dt float64) error {
+	for i := 0; i < n; i++ {
+		if err := e.Step(dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runHeadless steps the engine at ebiten's max TPS until Exit is called or
+// the run context is canceled.
+func (e *engine) runHeadless() error {
+	tps := ebiten.MaxTPS()
+	if tps <= 0 {
+		tps = ebiten.DefaultTPS
+	}
+	dt := 1 / float64(tps)
+	ticker := time.NewTicker(time.Second / time.Duration(tps))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.runctx.Done():
+			return e.runctx.Err()
+		case <-ticker.C:
+			if err := e.Step(dt); err != nil {
+				if err == ErrRegularTermination {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
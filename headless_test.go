@@ -0,0 +1,748 @@
+package primen
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/audio"
+	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/components/graphics"
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/input"
+	"github.com/gabstv/primen/core/logger"
+	"github.com/gabstv/primen/easing"
+	"github.com/gabstv/primen/geom"
+	"github.com/gabstv/primen/io"
+	osfs "github.com/gabstv/primen/io/os"
+	"github.com/gabstv/primen/io/pb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadlessEngineStep(t *testing.T) {
+	e := NewHeadlessEngine(&NewEngineInput{
+		Width:  320,
+		Height: 240,
+	})
+	readyc := 0
+	e.(*engine).ready = func(e Engine) {
+		readyc++
+	}
+	w := e.NewWorldWithDefaults(0)
+	n := NewRootFnNode(w)
+	frames := 0
+	total := 0.0
+	n.Function().Update = func(ctx core.UpdateCtx, e ecs.Entity) {
+		frames++
+		total += ctx.DT()
+	}
+	ranfn := false
+	e.RunFn(func() {
+		ranfn = true
+	})
+	assert.NoError(t, e.StepN(10, 0.5))
+	assert.Equal(t, 1, readyc)
+	assert.True(t, ranfn)
+	assert.Equal(t, 10, frames)
+	assert.Equal(t, 5.0, total)
+	assert.Equal(t, int64(10), e.UpdateFrame())
+	e.Exit()
+	assert.Equal(t, ErrRegularTermination, e.Step(0.5))
+}
+
+func TestHeadlessEngineInput(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	src := e.Input().Source().(*input.VirtualSource)
+	e.Input().PushContext("gameplay").BindAction("jump", input.Key(ebiten.KeySpace))
+	jumps := 0
+	w := e.NewWorldWithDefaults(0)
+	n := NewRootFnNode(w)
+	n.Function().Update = func(ctx core.UpdateCtx, e ecs.Entity) {
+		if ctx.Engine().Input().JustPressed("jump") {
+			jumps++
+		}
+	}
+	assert.NoError(t, e.Step(0.5))
+	src.SetKey(ebiten.KeySpace, true)
+	assert.NoError(t, e.StepN(3, 0.5))
+	assert.Equal(t, 1, jumps)
+}
+
+func TestHeadlessEngineReplay(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	src := e.Input().Source().(*input.VirtualSource)
+	e.Input().PushContext("gameplay").BindAction("jump", input.Key(ebiten.KeySpace))
+	var jumps []int64
+	total := 0.0
+	w := e.NewWorldWithDefaults(0)
+	n := NewRootFnNode(w)
+	n.Function().Update = func(ctx core.UpdateCtx, e ecs.Entity) {
+		total += ctx.DT()
+		if ctx.Engine().Input().JustPressed("jump") {
+			jumps = append(jumps, ctx.Frame())
+		}
+	}
+	e.RecordInput(7)
+	for i := 0; i < 6; i++ {
+		src.SetKey(ebiten.KeySpace, i%2 == 1)
+		assert.NoError(t, e.Step(0.25))
+	}
+	rec := e.StopInputRecording()
+	assert.Equal(t, 6, len(rec.Frames))
+	assert.Equal(t, []int64{2, 4, 6}, jumps)
+
+	src.SetKey(ebiten.KeySpace, false)
+	jumps = nil
+	total = 0
+	done := e.ReplayInput(rec)
+	// the recorded delta replaces the step delta
+	assert.NoError(t, e.StepN(6, 1))
+	assert.Equal(t, []int64{8, 10, 12}, jumps)
+	assert.Equal(t, 1.5, total)
+	assert.NoError(t, e.Step(1))
+	<-done
+	assert.Equal(t, src, e.Input().Source())
+}
+
+type lifecycleModule struct {
+	name string
+	deps []string
+	log  *[]string
+}
+
+func (m *lifecycleModule) ModuleName() string              { return m.name }
+func (m *lifecycleModule) Dependencies() []string          { return m.deps }
+func (m *lifecycleModule) BeforeUpdate(ctx core.UpdateCtx) {}
+func (m *lifecycleModule) AfterUpdate(ctx core.UpdateCtx)  {}
+func (m *lifecycleModule) BeforeDraw(ctx core.DrawCtx)     {}
+func (m *lifecycleModule) AfterDraw(ctx core.DrawCtx)      {}
+
+func (m *lifecycleModule) Init(engine core.Engine) error {
+	*m.log = append(*m.log, "init "+m.name)
+	return nil
+}
+
+func (m *lifecycleModule) Shutdown() {
+	*m.log = append(*m.log, "shutdown "+m.name)
+}
+
+// plainModule is not a core.NamedModule (the field keeps the instances
+// distinct)
+type plainModule struct {
+	n int
+}
+
+func (m *plainModule) BeforeUpdate(ctx core.UpdateCtx) {}
+func (m *plainModule) AfterUpdate(ctx core.UpdateCtx)  {}
+func (m *plainModule) BeforeDraw(ctx core.DrawCtx)     {}
+func (m *plainModule) AfterDraw(ctx core.DrawCtx)      {}
+
+type lifecycleScene struct {
+	log *[]string
+}
+
+func (s *lifecycleScene) Name() string { return "lifecycle" }
+
+func (s *lifecycleScene) Unload() chan struct{} {
+	*s.log = append(*s.log, "unload")
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func TestHeadlessEngineLifecycle(t *testing.T) {
+	log := make([]string, 0)
+	e := NewHeadlessEngine(nil)
+	audio := &lifecycleModule{name: "audio", log: &log}
+	music := &lifecycleModule{name: "music", deps: []string{"audio"}, log: &log}
+	extra := &lifecycleModule{name: "extra", log: &log}
+	assert.True(t, errors.Is(e.AddModule(music, 0), ErrModuleMissing))
+	assert.NoError(t, e.AddModule(audio, 0))
+	assert.NoError(t, e.AddModule(music, 10))
+	assert.True(t, errors.Is(e.AddModule(audio, 0), ErrModuleExists))
+	assert.True(t, errors.Is(e.AddModule(&lifecycleModule{name: "audio", log: &log}, 0), ErrModuleExists))
+	// unnamed modules are compared by instance
+	plain := &plainModule{}
+	assert.NoError(t, e.AddModule(plain, 0))
+	assert.NoError(t, e.AddModule(&plainModule{}, 0))
+	assert.True(t, errors.Is(e.AddModule(plain, 0), ErrModuleExists))
+	assert.NoError(t, e.AddModule(extra, 0))
+	assert.True(t, errors.Is(e.RemoveModule(audio), ErrModuleRequired))
+	assert.NoError(t, e.RemoveModule(extra))
+	assert.Equal(t, ErrModuleNotFound, e.RemoveModule(extra))
+	assert.Equal(t, []string{"init audio", "init music", "init extra", "shutdown extra"}, log)
+
+	log = log[:0]
+	e.(*engine).scenes = []Scene{&lifecycleScene{log: &log}}
+	e.OnShutdown(func() {
+		log = append(log, "hook")
+	})
+	e.(*engine).ready = func(e Engine) {
+		e.Exit()
+	}
+	assert.NoError(t, e.Run())
+	assert.Equal(t, []string{"unload", "hook", "shutdown music", "shutdown audio"}, log)
+	assert.Equal(t, 0, len(e.SceneStack()))
+}
+
+func TestHeadlessEngineConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "primen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	fs := osfs.New(dir)
+	c, err := LoadConfig(fs, "settings.json")
+	assert.NoError(t, err)
+	assert.Equal(t, 800, c.Window.Width)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "settings.json"), []byte(`{
+		"window": {"width": 640, "height": 360},
+		"scene": "intro",
+		"volumes": {"music": 0.5}
+	}`), 0644))
+	c, err = LoadConfig(fs, "settings.json")
+	assert.NoError(t, err)
+	assert.Equal(t, 640, c.Window.Width)
+	assert.Equal(t, 1.0, c.Window.Scale)
+	assert.Equal(t, "intro", c.Scene)
+
+	os.Setenv("PRIMENTEST_HEIGHT", "480")
+	os.Setenv("PRIMENTEST_DEBUG_FPS", "true")
+	defer os.Unsetenv("PRIMENTEST_HEIGHT")
+	defer os.Unsetenv("PRIMENTEST_DEBUG_FPS")
+	assert.NoError(t, c.ApplyEnv("PRIMENTEST"))
+	assert.NoError(t, c.ApplyFlags([]string{"-height", "720", "-volume", "sfx=0.25", "-scene=", "-fullscreen"}))
+	assert.Equal(t, 720, c.Window.Height)
+	assert.True(t, c.Debug.FPS)
+	assert.True(t, c.Window.Fullscreen)
+	assert.Equal(t, "", c.Scene)
+	assert.Equal(t, map[string]float64{"music": 0.5, "sfx": 0.25}, c.Volumes)
+	assert.Error(t, c.ApplyFlags([]string{"-width", "wide"}))
+
+	// the bus volumes are global
+	for _, bus := range []string{"music", "sfx"} {
+		defer audio.SetBusVolume(bus, audio.BusVolume(bus))
+	}
+	savepath := filepath.Join(dir, "settings.json")
+	e := NewHeadlessEngine(c.NewEngineInput(fs, savepath))
+	e.Input().PushContext("gameplay").BindAction("jump", input.Key(ebiten.KeySpace))
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, 0.25, audio.BusVolume("sfx"))
+	assert.NoError(t, e.UpdateConfig(func(c *Config) {
+		// fn can call the engine
+		assert.Equal(t, 0, len(e.SceneStack()))
+		c.Volumes["music"] = 0.75
+	}))
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, 0.75, audio.BusVolume("music"))
+
+	c2, err := LoadConfig(fs, "settings.json")
+	assert.NoError(t, err)
+	assert.Equal(t, 720, c2.Window.Height)
+	assert.Equal(t, 0.75, c2.Volumes["music"])
+	assert.Contains(t, string(c2.Bindings), "jump")
+
+	// concurrent updates are serialized (no change is lost)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, e.UpdateConfig(func(c *Config) {
+				c.Window.Width++
+			}))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 640+20, e.Config().Window.Width)
+}
+
+func TestHeadlessEngineLogger(t *testing.T) {
+	l := logger.New()
+	e := NewHeadlessEngine(&NewEngineInput{
+		Scene:  "missing-scene",
+		Logger: l,
+	})
+	assert.NoError(t, e.Step(0.5))
+	entries := l.Recent(0)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, logger.LevelError, entries[0].Level)
+	assert.Equal(t, "scene", entries[0].Tag)
+	assert.Equal(t, "missing-scene", entries[0].Field("scene"))
+
+	<-e.NewContainer().Load("missing-file.png")
+	entries = l.Recent(0)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "io", entries[1].Tag)
+	assert.Equal(t, "missing-file.png", entries[1].Field("file"))
+}
+
+func TestHeadlessEngineFixedTimestep(t *testing.T) {
+	e := NewHeadlessEngine(&NewEngineInput{
+		FixedTimestep: 0.1,
+		MaxFixedSteps: 3,
+	})
+	ee := e.(*engine)
+	w := e.NewWorldWithDefaults(0)
+	n := NewRootFnNode(w)
+	n.Transform().SetScale(2, 2)
+	updates := 0
+	total := 0.0
+	n.Function().Update = func(ctx core.UpdateCtx, e ecs.Entity) {
+		updates++
+		total += ctx.DT()
+	}
+	now := time.Now()
+
+	assert.NoError(t, ee.fixedUpdate(now, 0.25))
+	assert.Equal(t, 2, updates)
+	assert.InDelta(t, 0.2, total, 1e-9)
+	assert.InDelta(t, 0.5, ee.alpha, 1e-9)
+
+	// not enough time for a step; the leftover time accumulates
+	assert.NoError(t, ee.fixedUpdate(now, 0.04))
+	assert.Equal(t, 2, updates)
+	assert.InDelta(t, 0.9, ee.alpha, 1e-9)
+
+	// a long hitch is clamped to MaxFixedSteps and the excess is dropped
+	assert.NoError(t, ee.fixedUpdate(now, 1))
+	assert.Equal(t, 5, updates)
+	assert.InDelta(t, 0.5, total, 1e-9)
+	assert.InDelta(t, 0.9, ee.alpha, 1e-9)
+	assert.Equal(t, int64(5), e.UpdateFrame())
+
+	// the interpolated matrix keeps the scale while rotating
+	n.Transform().SetAngle(math.Pi / 2)
+	assert.NoError(t, ee.fixedUpdate(now, 0.02))
+	assert.Equal(t, 6, updates)
+	g := n.Transform().InterpolatedGeoM(0.5)
+	assert.InDelta(t, 2, math.Hypot(g.Element(0, 0), g.Element(1, 0)), 1e-5)
+	assert.InDelta(t, math.Pi/4, math.Atan2(g.Element(1, 0), g.Element(0, 0)), 1e-5)
+	assert.Equal(t, n.Transform().GeoM(), n.Transform().InterpolatedGeoM(1))
+}
+
+func TestHeadlessEngineMessages(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	w := e.NewWorldWithDefaults(0)
+	root := NewRootNode(w)
+	child := NewChildNode(root)
+	leaf := NewChildNode(child)
+	var log []string
+	handler := func(name string) components.MessageFn {
+		return func(msg *components.Message) {
+			log = append(log, name)
+			if msg.Data == "stop" && msg.Current == child.Entity() {
+				msg.Cancel()
+			}
+		}
+	}
+	leaf.OnMessage(components.MessageCatchAll, handler("leaf:*"))
+	leaf.OnMessage("hit", handler("leaf:a"))
+	leaf.OnMessage("hit", handler("leaf:b"))
+	child.OnMessage("hit", handler("child"))
+	root.OnMessage("hit", handler("root"))
+
+	// handlers of the same entity run in registration order (catch-all
+	// last), then the message bubbles to the root
+	assert.True(t, leaf.SendMessage("hit", nil, true))
+	assert.Equal(t, []string{"leaf:a", "leaf:b", "leaf:*", "child", "root"}, log)
+
+	log = nil
+	assert.True(t, leaf.SendMessage("hit", nil, false))
+	assert.Equal(t, []string{"leaf:a", "leaf:b", "leaf:*"}, log)
+
+	// cancelling stops the bubbling
+	log = nil
+	assert.False(t, leaf.SendMessage("hit", "stop", true))
+	assert.Equal(t, []string{"leaf:a", "leaf:b", "leaf:*", "child"}, log)
+
+	// the components version bubbles through the transform parents
+	log = nil
+	assert.True(t, components.SendMessage(w, child.Entity(), "hit", nil, true))
+	assert.Equal(t, []string{"child", "root"}, log)
+
+	// a cyclic chain is delivered once per entity
+	log = nil
+	assert.True(t, components.SendMessageChain(&components.Message{
+		Name:   "hit",
+		Target: child.Entity(),
+		World:  w,
+	}, func(cur ecs.Entity) ecs.Entity {
+		if cur == child.Entity() {
+			return root.Entity()
+		}
+		return child.Entity()
+	}))
+	assert.Equal(t, []string{"child", "root"}, log)
+}
+
+func TestHeadlessEngineTimeScale(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	w := e.NewWorldWithDefaults(0)
+	other := e.NewWorldWithDefaults(1)
+	var dts, realdts, otherdts []float64
+	n := NewRootFnNode(w)
+	n.Function().Update = func(ctx core.UpdateCtx, e ecs.Entity) {
+		dts = append(dts, ctx.DT())
+		realdts = append(realdts, ctx.RealDT())
+	}
+	on := NewRootFnNode(other)
+	on.Function().Update = func(ctx core.UpdateCtx, e ecs.Entity) {
+		otherdts = append(otherdts, ctx.DT())
+	}
+
+	w.SetTimeScale(0.5)
+	assert.NoError(t, e.Step(0.5))
+	w.SetPaused(true)
+	assert.NoError(t, e.Step(0.5))
+	w.SetPaused(false)
+	// the hit-stop counts down in real time (0.75s = two 0.5s frames)
+	w.HitStop(0.75)
+	w.HitStop(0.25)
+	assert.NoError(t, e.StepN(3, 0.5))
+	w.SetTimeScale(-1)
+	assert.Equal(t, 0.0, w.TimeScale())
+	assert.NoError(t, e.Step(0.5))
+
+	assert.Equal(t, []float64{0.25, 0, 0, 0, 0.25, 0}, dts)
+	assert.Equal(t, []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5}, realdts)
+	assert.Equal(t, []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5}, otherdts)
+}
+
+func TestHeadlessEnginePrefab(t *testing.T) {
+	dir, err := ioutil.TempDir("", "primen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"hero.xml": `<prefab name="hero">
+			<node id="hero" tags="player">
+				<transform x="10" y="20" scale="2" />
+				<drawlayer layer="1" z="bottom" />
+				<node id="weapon" prefab="sword.xml">
+					<transform x="4" />
+					<override path="blade" transform.angle="1.5" transform.y="-4" />
+				</node>
+			</node>
+		</prefab>`,
+		"sword.xml": `<prefab name="sword">
+			<node id="sword">
+				<transform x="1" y="2" />
+				<node id="blade">
+					<transform y="-3" />
+				</node>
+			</node>
+		</prefab>`,
+		"a.xml": `<prefab><node><node prefab="b.xml" /></node></prefab>`,
+		"b.xml": `<prefab><node><node prefab="a.xml" /></node></prefab>`,
+	}
+	for name, v := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(v), 0644))
+	}
+	e := NewHeadlessEngine(&NewEngineInput{
+		FS: osfs.New(dir),
+	})
+	w := e.NewWorldWithDefaults(0)
+	l := NewPrefabLoader(e.NewContainer())
+
+	hero, err := l.NewRoot(w, "hero.xml", make(PrefabOverrides).
+		Set("", "transform.x", "30").
+		Set("weapon/blade", "transform.y", "-5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hero", hero.Name())
+	assert.True(t, hero.HasTag("player"))
+	assert.Equal(t, 30.0, hero.Transform().X())
+	assert.Equal(t, 20.0, hero.Transform().Y())
+	sx, sy := hero.Transform().Scale()
+	assert.Equal(t, 2.0, sx)
+	assert.Equal(t, 2.0, sy)
+	dl := graphics.GetDrawLayerComponentData(w, hero.Entity())
+	assert.NotNil(t, dl)
+	assert.Equal(t, Layer1, dl.Layer)
+	assert.Equal(t, graphics.ZIndexBottom, dl.ZIndex)
+
+	// the nested prefab root is renamed after the node that references it;
+	// the node components override the nested prefab
+	weapon := FindChild(hero, "weapon")
+	assert.NotNil(t, weapon)
+	wtr := components.GetTransformComponentData(w, weapon.Entity())
+	assert.Equal(t, hero.Entity(), wtr.Parent())
+	assert.Equal(t, 4.0, wtr.X())
+	assert.Equal(t, 2.0, wtr.Y())
+
+	// <override> elements are applied, the instance overrides win
+	blade := FindChild(hero, "weapon/blade")
+	assert.NotNil(t, blade)
+	btr := components.GetTransformComponentData(w, blade.Entity())
+	assert.Equal(t, weapon.Entity(), btr.Parent())
+	assert.Equal(t, 1.5, btr.Angle())
+	assert.Equal(t, -5.0, btr.Y())
+
+	_, err = l.NewRoot(w, "a.xml", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic reference (a.xml > b.xml > a.xml)")
+	_, err = l.NewRoot(w, "missing.xml", nil)
+	assert.Error(t, err)
+}
+
+func testSnapshotAtlas(t *testing.T) *io.Atlas {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, png.Encode(buf, img))
+	walk := &pb.AnimationClip{
+		Name:     "walk",
+		Fps:      12,
+		ClipMode: pb.AnimationClipMode_LOOP,
+		Frames: []*pb.AnimFrame{
+			{FrameName: "a"},
+			{FrameName: "b"},
+		},
+	}
+	b, err := proto.Marshal(&pb.AtlasFile{
+		Images:  [][]byte{buf.Bytes()},
+		Filters: []pb.ImageFilter{pb.ImageFilter_NEAREST},
+		Frames: map[string]*pb.Frame{
+			"a": {W: 2, H: 2},
+			"b": {X: 2, W: 2, H: 2},
+		},
+		Animations: map[string]*pb.Animation{
+			"hero": {Name: "hero", Clips: []*pb.AnimationClip{walk}},
+		},
+	})
+	assert.NoError(t, err)
+	atlas, err := io.ParseAtlas(b)
+	assert.NoError(t, err)
+	return atlas
+}
+
+func TestHeadlessEngineSnapshot(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	atlas := testSnapshotAtlas(t)
+	assets := io.NewSnapshotAssets().AddAtlas("hero.dat", atlas)
+	w := e.NewWorldWithDefaults(0)
+	root := NewRootNode(w)
+	root.Transform().SetPos(geom.Vec{X: 10, Y: 20}).SetAngle(0.5).SetScale(2, 3)
+	components.SetTrTweeningComponentData(w, root.Entity(), components.NewTrTweening())
+	tw := components.GetTrTweeningComponentData(w, root.Entity())
+	tw.SetTween("move", components.TrTweenY, 0, 100, 2, easing.OutQuad)
+	tw.SetTween("spin", components.TrTweenRotation, 0, 1, 1, easing.Linear)
+	assert.True(t, tw.Play("move"))
+	child := NewChildNode(root)
+	child.Transform().SetX(5)
+	graphics.SetDrawLayerComponentData(w, child.Entity(), graphics.DrawLayer{Layer: Layer2, ZIndex: 7})
+	spr := graphics.NewSprite(0, 0, atlas.GetSubImage("a").Image)
+	spr.SetOrigin(0.5, 0.5)
+	graphics.SetSpriteComponentData(w, child.Entity(), spr)
+	graphics.SetSpriteAnimationComponentData(w, child.Entity(), graphics.NewSpriteAnimation(12, atlas.GetAnimation("hero")))
+	assert.True(t, graphics.GetSpriteAnimationComponentData(w, child.Entity()).PlayClipFrame("walk", 1))
+	assert.NoError(t, e.Step(0.25))
+
+	snap, err := io.SnapshotWorld(w, assets)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(snap.Entities))
+	b, err := io.MarshalWorld(w, assets)
+	assert.NoError(t, err)
+
+	w2 := e.NewWorldWithDefaults(1)
+	ids, err := io.UnmarshalWorld(w2, b, assets)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ids))
+	rchild := ids[uint64(child.Entity())]
+	rroot := ids[uint64(root.Entity())]
+	assert.Equal(t, rroot, components.GetTransformComponentData(w2, rchild).Parent())
+	ra := graphics.GetSpriteAnimationComponentData(w2, rchild)
+	assert.NotNil(t, ra)
+	assert.Equal(t, "walk", ra.ActiveClip().GetName())
+	active, _, playing := components.GetTrTweeningComponentData(w2, rroot).State()
+	assert.Equal(t, "move", active.Name)
+	assert.True(t, playing)
+
+	// the restored world produces the same snapshot (with the new ids)
+	snap2, err := io.SnapshotWorld(w2, assets)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(snap2.Entities))
+	for _, es := range snap.Entities {
+		want := proto.Clone(es).(*pb.EntitySnapshot)
+		want.Id = uint64(ids[es.Id])
+		if want.Transform.Parent != 0 {
+			want.Transform.Parent = uint64(ids[es.Transform.Parent])
+		}
+		found := false
+		for _, es2 := range snap2.Entities {
+			if es2.Id == want.Id {
+				found = true
+				assert.True(t, proto.Equal(want, es2), "entity %d: %v != %v", es.Id, want, es2)
+			}
+		}
+		assert.True(t, found)
+	}
+}
+
+func TestHeadlessEngineSnapshotRestoreError(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	w := e.NewWorldWithDefaults(0)
+	snap := &pb.WorldSnapshot{
+		Version: io.WorldSnapshotVersion,
+		Entities: []*pb.EntitySnapshot{
+			{
+				Id:        1,
+				Transform: &pb.TransformState{Sx: 1, Sy: 1},
+			},
+			{
+				Id:              2,
+				Transform:       &pb.TransformState{Parent: 1, Sx: 1, Sy: 1},
+				Sprite:          &pb.SpriteState{},
+				SpriteAnimation: &pb.SpriteAnimationState{Atlas: "missing.dat", Animation: "walk"},
+			},
+		},
+	}
+	ids, err := io.RestoreWorld(w, snap, io.NewSnapshotAssets())
+	assert.Error(t, err)
+	assert.Nil(t, ids)
+	// the entities created before the error are removed
+	assert.Equal(t, 0, len(components.GetTransformSystem(w).V().Matches()))
+}
+
+type stackScene struct {
+	name string
+	log  *[]string
+}
+
+func (s *stackScene) Name() string { return s.name }
+func (s *stackScene) Suspend()     { *s.log = append(*s.log, "suspend "+s.name) }
+func (s *stackScene) Resume()      { *s.log = append(*s.log, "resume "+s.name) }
+
+func (s *stackScene) Unload() chan struct{} {
+	*s.log = append(*s.log, "unload "+s.name)
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func TestHeadlessEngineSceneStack(t *testing.T) {
+	var log []string
+	e := NewHeadlessEngine(nil)
+	gate := make(chan struct{})
+	newScene := func(name string, loaded chan struct{}) NewSceneFn {
+		return func(engine Engine) (Scene, chan struct{}) {
+			return &stackScene{name: name, log: &log}, loaded
+		}
+	}
+	e.(*engine).sceneldrs = map[string]NewSceneFn{
+		"menu":  newScene("menu", nil),
+		"level": newScene("level", gate),
+		"pause": newScene("pause", nil),
+	}
+	names := func() []string {
+		out := make([]string, 0)
+		for _, s := range e.SceneStack() {
+			out = append(out, s.Name())
+		}
+		return out
+	}
+	isDone := func(ch chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+
+	done, err := e.PushScene("menu", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(0.5))
+	assert.True(t, isDone(done))
+	assert.Equal(t, []string{"menu"}, names())
+
+	// the change waits for the scene to load; other changes are rejected
+	done, err = e.PushScene("level", CrossfadeTransition(1))
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(0.5))
+	assert.False(t, isDone(done))
+	assert.Equal(t, []string{"menu"}, names())
+	_, err = e.PushScene("pause", nil)
+	assert.Equal(t, ErrSceneTransition, err)
+	_, err = e.PopScene(nil)
+	assert.Equal(t, ErrSceneTransition, err)
+	_, _, err = e.LoadScene("pause")
+	assert.Equal(t, ErrSceneTransition, err)
+	close(gate)
+	assert.NoError(t, e.Step(0.5))
+	assert.True(t, isDone(done))
+	assert.Equal(t, []string{"menu", "level"}, names())
+
+	_, err = e.PushScene("pause", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, []string{"menu", "level", "pause"}, names())
+	_, err = e.PopScene(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, []string{"menu", "level"}, names())
+	_, err = e.ReplaceScene("pause", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(0.5))
+	assert.Equal(t, []string{"menu", "pause"}, names())
+	_, err = e.ReplaceScene("missing", nil)
+	assert.Equal(t, ErrSceneNotFound, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = e.PopScene(nil)
+		assert.NoError(t, err)
+		assert.NoError(t, e.Step(0.5))
+	}
+	assert.Equal(t, []string{}, names())
+	_, err = e.PopScene(nil)
+	assert.Equal(t, ErrSceneStackEmpty, err)
+
+	assert.Equal(t, []string{
+		"suspend menu",
+		"suspend level",
+		"unload pause", "resume level",
+		"unload level",
+		"unload pause", "resume menu",
+		"unload menu",
+	}, log)
+}
+
+func TestHeadlessEngineSceneCapture(t *testing.T) {
+	var log []string
+	e := NewHeadlessEngine(nil)
+	ee := e.(*engine)
+	ticks := 0
+	ee.sceneldrs = map[string]NewSceneFn{
+		"level": func(engine Engine) (Scene, chan struct{}) {
+			w := engine.NewWorldWithDefaults(0)
+			components.GetSchedulerSystem(w).Every(0, func() { ticks++ })
+			return &stackScene{name: "level", log: &log}, nil
+		},
+	}
+	// a drawn transition waits for the next Draw to grab the frame
+	ee.headless = false
+	done, err := e.PushScene("level", CrossfadeTransition(0.5))
+	ee.headless = true
+	assert.NoError(t, err)
+	assert.NoError(t, e.StepN(2, 0.25))
+	assert.Equal(t, 0, len(e.SceneStack()))
+	assert.Equal(t, 0, ticks)
+
+	// the frame was grabbed
+	ee.lock.Lock()
+	ee.sceneChange.phase = sceneTrLoading
+	ee.lock.Unlock()
+	assert.NoError(t, e.Step(0.25))
+	assert.Equal(t, 1, len(e.SceneStack()))
+	assert.Equal(t, 0, ticks)
+	assert.NoError(t, e.StepN(2, 0.25))
+	assert.True(t, ticks > 0)
+	<-done
+}
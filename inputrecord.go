@@ -0,0 +1,82 @@
+package primen
+
+import (
+	"github.com/gabstv/primen/components/graphics"
+	"github.com/gabstv/primen/core/input"
+)
+
+// RecordInput starts recording the input (and the delta) of every update
+// frame. The shared particle emitter RNG is seeded with seed, so a replay
+// that uses the same seed spawns the same particles.
+//
+// While recording, the frames read the captured state (not the devices), so
+// they see exactly what is played back later.
+func (e *engine) RecordInput(seed int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	src := e.input.Source()
+	if e.inputRecorder != nil {
+		src = e.inputRecorder.Source()
+	}
+	graphics.SeedEmitterRNG(seed)
+	e.inputRecorder = input.NewRecorder(src, seed)
+}
+
+// StopInputRecording stops recording the input and returns the recording
+// (nil if the engine is not recording)
+func (e *engine) StopInputRecording() *input.Recording {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	r := e.inputRecorder
+	if r == nil {
+		return nil
+	}
+	e.inputRecorder = nil
+	e.input.SetSource(r.Source())
+	return r.Recording()
+}
+
+// ReplayInput feeds a recording to the engine instead of the input devices.
+// The delta of each update frame is also replaced by the recorded delta.
+// The returned channel is closed after the last frame is played (the input
+// devices are restored).
+func (e *engine) ReplayInput(rec *input.Recording) <-chan struct{} {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.inputPlayer != nil {
+		close(e.inputReplayDone)
+	} else {
+		e.inputReplaySrc = e.input.Source()
+	}
+	if e.inputRecorder != nil {
+		e.inputReplaySrc = e.inputRecorder.Source()
+		e.inputRecorder = nil
+	}
+	graphics.SeedEmitterRNG(rec.Seed)
+	e.inputPlayer = input.NewPlayer(rec)
+	e.inputReplayDone = make(chan struct{})
+	return e.inputReplayDone
+}
+
+// updateInput polls the input of an update frame. It returns the delta of
+// the frame (the recorded delta on replays).
+func (e *engine) updateInput(delta float64) float64 {
+	e.lock.Lock()
+	if e.inputPlayer != nil {
+		if f, ok := e.inputPlayer.Next(); ok {
+			e.input.SetSource(f)
+			delta = f.DT
+		} else {
+			e.input.SetSource(e.inputReplaySrc)
+			close(e.inputReplayDone)
+			e.inputPlayer = nil
+			e.inputReplayDone = nil
+			e.inputReplaySrc = nil
+		}
+	} else if e.inputRecorder != nil {
+		e.input.SetSource(e.inputRecorder.Frame(delta))
+	}
+	e.lock.Unlock()
+	e.input.Update()
+	return delta
+}
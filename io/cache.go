@@ -0,0 +1,515 @@
+package io
+
+import (
+	"bytes"
+	"container/list"
+	"image"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// AssetType is the type of a decoded asset of a Cache
+type AssetType int
+
+const (
+	// AssetImage is a decoded image (*ebiten.Image)
+	AssetImage AssetType = 1
+	// AssetAtlas is a parsed atlas (*Atlas)
+	AssetAtlas AssetType = 2
+	// AssetFont is a parsed font (*truetype.Font)
+	AssetFont AssetType = 3
+	// AssetAudio is a decoded audio buffer (PCM bytes)
+	AssetAudio AssetType = 4
+)
+
+func (t AssetType) String() string {
+	switch t {
+	case AssetImage:
+		return "image"
+	case AssetAtlas:
+		return "atlas"
+	case AssetFont:
+		return "font"
+	case AssetAudio:
+		return "audio"
+	}
+	return "unknown"
+}
+
+// CacheStats are the stats of an asset type of a Cache
+type CacheStats struct {
+	Count     int   // assets in the cache
+	Refs      int   // live handles
+	Bytes     int64 // estimated memory of the assets
+	Hits      int64 // handles returned from the cache
+	Misses    int64 // assets decoded
+	Evictions int64 // assets evicted (by the budget or by Purge)
+}
+
+// AssetInfo describes an asset of a Cache (see Cache.Assets)
+type AssetInfo struct {
+	Name  string
+	Type  AssetType
+	Refs  int
+	Bytes int64
+}
+
+type cacheKey struct {
+	t    AssetType
+	name string
+}
+
+type cacheEntry struct {
+	key   cacheKey
+	value interface{}
+	size  int64
+	refs  int
+	elem  *list.Element // position in the LRU list (if unreferenced)
+	ready chan struct{}
+	err   error
+}
+
+// Cache is a cache of decoded assets on top of a Container. The assets are
+// decoded once and shared by reference counted handles; an asset without
+// handles stays in the cache until it is evicted (least recently used
+// first) to keep the cache under the memory budget.
+//
+//	h, err := cache.Image("public/player.png")
+//	if err != nil {
+//		return err
+//	}
+//	defer h.Release()
+//	sprite.SetImage(h.Image())
+type Cache struct {
+	c       Container
+	filter  ebiten.Filter
+	m       sync.Mutex
+	entries map[cacheKey]*cacheEntry
+	lru     *list.List
+	budget  int64
+	size    int64
+	stats   map[AssetType]*CacheStats
+}
+
+// NewCache returns a cache of the assets of c. The budget is the max
+// estimated memory (in bytes) of the cache; only unreferenced assets are
+// evicted, so the cache can go over the budget. A budget <= 0 disables the
+// eviction (see Purge).
+func NewCache(c Container, budget int64) *Cache {
+	return &Cache{
+		c:       c,
+		filter:  ebiten.FilterDefault,
+		entries: make(map[cacheKey]*cacheEntry),
+		lru:     list.New(),
+		budget:  budget,
+		stats:   make(map[AssetType]*CacheStats),
+	}
+}
+
+// Container returns the container of the cache
+func (c *Cache) Container() Container {
+	return c.c
+}
+
+// SetFilter sets the filter of the images decoded after the call
+func (c *Cache) SetFilter(filter ebiten.Filter) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.filter = filter
+}
+
+// SetBudget sets the memory budget (in bytes) and evicts the assets over it
+func (c *Cache) SetBudget(budget int64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.budget = budget
+	c.evict()
+}
+
+// Budget returns the memory budget (in bytes)
+func (c *Cache) Budget() int64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.budget
+}
+
+// Size returns the estimated memory (in bytes) of the cached assets
+func (c *Cache) Size() int64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.size
+}
+
+// Stats returns the stats of each asset type
+func (c *Cache) Stats() map[AssetType]CacheStats {
+	c.m.Lock()
+	defer c.m.Unlock()
+	out := make(map[AssetType]CacheStats, len(c.stats))
+	for k, v := range c.stats {
+		out[k] = *v
+	}
+	return out
+}
+
+// Assets returns the assets of the cache (sorted by type and name). The
+// assets with Refs > 0 after a scene is unloaded are usually leaks.
+func (c *Cache) Assets() []AssetInfo {
+	c.m.Lock()
+	out := make([]AssetInfo, 0, len(c.entries))
+	for k, e := range c.entries {
+		if e.value == nil {
+			continue
+		}
+		out = append(out, AssetInfo{
+			Name:  k.name,
+			Type:  k.t,
+			Refs:  e.refs,
+			Bytes: e.size,
+		})
+	}
+	c.m.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Purge evicts all the unreferenced assets
+func (c *Cache) Purge() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	for c.lru.Len() > 0 {
+		c.remove(c.lru.Back().Value.(*cacheEntry))
+	}
+}
+
+// Image returns a handle of a decoded image
+func (c *Cache) Image(name string) (*ImageHandle, error) {
+	h, err := c.get(AssetImage, name, c.decodeImage)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageHandle{h}, nil
+}
+
+// Atlas returns a handle of a parsed atlas
+func (c *Cache) Atlas(name string) (*AtlasHandle, error) {
+	h, err := c.get(AssetAtlas, name, decodeAtlas)
+	if err != nil {
+		return nil, err
+	}
+	return &AtlasHandle{h}, nil
+}
+
+// Font returns a handle of a parsed TrueType font
+func (c *Cache) Font(name string) (*FontHandle, error) {
+	h, err := c.get(AssetFont, name, decodeFont)
+	if err != nil {
+		return nil, err
+	}
+	return &FontHandle{h}, nil
+}
+
+// Audio returns a handle of a decoded audio buffer (the PCM bytes used by
+// components.NewAudioPlayerInput.RawAudio)
+func (c *Cache) Audio(name string) (*AudioHandle, error) {
+	h, err := c.get(AssetAudio, name, decodeAudio)
+	if err != nil {
+		return nil, err
+	}
+	return &AudioHandle{h}, nil
+}
+
+type decodeFn func(name string, b []byte) (value interface{}, size int64, err error)
+
+func (c *Cache) get(t AssetType, name string, decode decodeFn) (*Handle, error) {
+	key := cacheKey{t, name}
+	c.m.Lock()
+	st := c.typeStats(t)
+	if e, ok := c.entries[key]; ok {
+		c.retain(e)
+		c.m.Unlock()
+		<-e.ready
+		if e.err != nil {
+			c.m.Lock()
+			c.release(e)
+			c.m.Unlock()
+			return nil, e.err
+		}
+		c.m.Lock()
+		st.Hits++
+		c.m.Unlock()
+		return newHandle(c, e), nil
+	}
+	e := &cacheEntry{
+		key:   key,
+		refs:  1,
+		ready: make(chan struct{}),
+	}
+	c.entries[key] = e
+	st.Misses++
+	c.m.Unlock()
+
+	var value interface{}
+	var size int64
+	b, err := c.c.Get(name)
+	if err == nil {
+		value, size, err = decode(name, b)
+	}
+
+	c.m.Lock()
+	if err != nil {
+		e.err = err
+		delete(c.entries, key)
+		close(e.ready)
+		c.m.Unlock()
+		return nil, err
+	}
+	e.value = value
+	e.size = size
+	c.size += size
+	st.Count++
+	st.Refs += e.refs // including the callers waiting for the decode
+	st.Bytes += size
+	close(e.ready)
+	c.evict()
+	c.m.Unlock()
+	return newHandle(c, e), nil
+}
+
+// typeStats must be called with the lock held
+func (c *Cache) typeStats(t AssetType) *CacheStats {
+	st, ok := c.stats[t]
+	if !ok {
+		st = &CacheStats{}
+		c.stats[t] = st
+	}
+	return st
+}
+
+// retain must be called with the lock held
+func (c *Cache) retain(e *cacheEntry) {
+	if e.elem != nil {
+		c.lru.Remove(e.elem)
+		e.elem = nil
+	}
+	e.refs++
+	if e.value != nil {
+		c.typeStats(e.key.t).Refs++
+	}
+}
+
+// release must be called with the lock held
+func (c *Cache) release(e *cacheEntry) {
+	e.refs--
+	if e.value == nil {
+		// failed (or still loading)
+		return
+	}
+	c.typeStats(e.key.t).Refs--
+	if e.refs > 0 {
+		return
+	}
+	if c.entries[e.key] != e {
+		// evicted
+		return
+	}
+	e.elem = c.lru.PushFront(e)
+	c.evict()
+}
+
+// evict removes the least recently used assets without handles until the
+// cache is under the budget. It must be called with the lock held.
+func (c *Cache) evict() {
+	if c.budget <= 0 {
+		return
+	}
+	for c.size > c.budget && c.lru.Len() > 0 {
+		c.remove(c.lru.Back().Value.(*cacheEntry))
+	}
+}
+
+// remove must be called with the lock held
+func (c *Cache) remove(e *cacheEntry) {
+	if e.elem != nil {
+		c.lru.Remove(e.elem)
+		e.elem = nil
+	}
+	delete(c.entries, e.key)
+	c.size -= e.size
+	st := c.typeStats(e.key.t)
+	st.Count--
+	st.Bytes -= e.size
+	st.Evictions++
+	disposeAsset(e.value)
+	e.value = nil
+}
+
+func disposeAsset(v interface{}) {
+	switch x := v.(type) {
+	case *ebiten.Image:
+		_ = x.Dispose()
+	case *Atlas:
+		for _, img := range x.ebimg {
+			_ = img.Dispose()
+		}
+	}
+}
+
+func (c *Cache) decodeImage(name string, b []byte) (interface{}, int64, error) {
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, 0, err
+	}
+	c.m.Lock()
+	filter := c.filter
+	c.m.Unlock()
+	ei, err := ebiten.NewImageFromImage(img, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return ei, imageBytes(ei), nil
+}
+
+func decodeAtlas(name string, b []byte) (interface{}, int64, error) {
+	a, err := ParseAtlas(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	var size int64
+	for _, img := range a.ebimg {
+		size += imageBytes(img)
+	}
+	return a, size, nil
+}
+
+func decodeFont(name string, b []byte) (interface{}, int64, error) {
+	f, err := truetype.Parse(b)
+	if err != nil {
+		return nil, 0, err
+	}
+	return f, int64(len(b)), nil
+}
+
+func decodeAudio(name string, b []byte) (interface{}, int64, error) {
+	stream, err := ParseAudioStream(name, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, stream.Data); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), int64(buf.Len()), nil
+}
+
+// imageBytes is the estimated texture memory of an image (RGBA)
+func imageBytes(img *ebiten.Image) int64 {
+	w, h := img.Size()
+	return int64(w) * int64(h) * 4
+}
+
+// Handle is a reference to an asset of a Cache. The asset can be evicted
+// after all of its handles are released.
+type Handle struct {
+	c        *Cache
+	e        *cacheEntry
+	released int32 // atomic
+}
+
+func newHandle(c *Cache, e *cacheEntry) *Handle {
+	return &Handle{
+		c: c,
+		e: e,
+	}
+}
+
+// Name returns the name of the asset
+func (h *Handle) Name() string {
+	return h.e.key.name
+}
+
+// Type returns the type of the asset
+func (h *Handle) Type() AssetType {
+	return h.e.key.t
+}
+
+// Value returns the decoded asset (nil after Release)
+func (h *Handle) Value() interface{} {
+	if atomic.LoadInt32(&h.released) == 1 {
+		return nil
+	}
+	h.c.m.Lock()
+	defer h.c.m.Unlock()
+	return h.e.value
+}
+
+// Retain returns a new handle of the same asset
+func (h *Handle) Retain() *Handle {
+	h.c.m.Lock()
+	defer h.c.m.Unlock()
+	h.c.retain(h.e)
+	return newHandle(h.c, h.e)
+}
+
+// Release releases the handle. It is safe to call it more than once.
+func (h *Handle) Release() {
+	if !atomic.CompareAndSwapInt32(&h.released, 0, 1) {
+		return
+	}
+	h.c.m.Lock()
+	defer h.c.m.Unlock()
+	h.c.release(h.e)
+}
+
+// ImageHandle is a Handle of a decoded image
+type ImageHandle struct {
+	*Handle
+}
+
+// Image returns the image
+func (h *ImageHandle) Image() *ebiten.Image {
+	img, _ := h.Value().(*ebiten.Image)
+	return img
+}
+
+// AtlasHandle is a Handle of a parsed atlas
+type AtlasHandle struct {
+	*Handle
+}
+
+// Atlas returns the atlas
+func (h *AtlasHandle) Atlas() *Atlas {
+	a, _ := h.Value().(*Atlas)
+	return a
+}
+
+// FontHandle is a Handle of a parsed font
+type FontHandle struct {
+	*Handle
+}
+
+// Font returns the font
+func (h *FontHandle) Font() *truetype.Font {
+	f, _ := h.Value().(*truetype.Font)
+	return f
+}
+
+// AudioHandle is a Handle of a decoded audio buffer
+type AudioHandle struct {
+	*Handle
+}
+
+// Bytes returns the decoded audio (PCM bytes)
+func (h *AudioHandle) Bytes() []byte {
+	b, _ := h.Value().([]byte)
+	return b
+}
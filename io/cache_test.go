@@ -0,0 +1,75 @@
+package io
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	fsfs := &testfs{
+		okfiles: map[string]bool{
+			"a.bin": true,
+			"b.bin": true,
+			"c.bin": true,
+		},
+	}
+	decodes := 0
+	decode := func(name string, b []byte) (interface{}, int64, error) {
+		decodes++
+		return name, int64(len(b)), nil
+	}
+	c := NewCache(NewContainer(context.Background(), fsfs), 8192)
+	a1, err := c.get(AssetAudio, "a.bin", decode)
+	assert.NoError(t, err)
+	a2, err := c.get(AssetAudio, "a.bin", decode)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, decodes)
+	assert.Equal(t, "a.bin", a2.Value())
+	b1, err := c.get(AssetAudio, "b.bin", decode)
+	assert.NoError(t, err)
+	_, err = c.get(AssetAudio, "missing.bin", decode)
+	assert.True(t, os.IsNotExist(err))
+
+	st := c.Stats()[AssetAudio]
+	assert.Equal(t, 2, st.Count)
+	assert.Equal(t, 3, st.Refs)
+	assert.Equal(t, int64(8192), st.Bytes)
+	assert.Equal(t, int64(1), st.Hits)
+	assert.Equal(t, int64(3), st.Misses)
+
+	// referenced assets are not evicted (the cache goes over the budget)
+	c1, err := c.get(AssetAudio, "c.bin", decode)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12288), c.Size())
+
+	a1.Release()
+	a1.Release()
+	assert.Equal(t, 3, len(c.Assets()))
+	b1.Release()
+	// b was released last, but a is still referenced by a2
+	assert.Equal(t, int64(8192), c.Size())
+	assert.Equal(t, []AssetInfo{
+		{Name: "a.bin", Type: AssetAudio, Refs: 1, Bytes: 4096},
+		{Name: "c.bin", Type: AssetAudio, Refs: 1, Bytes: 4096},
+	}, c.Assets())
+	assert.Nil(t, b1.Value())
+
+	c.SetBudget(0)
+	a2.Release()
+	c1.Release()
+	assert.Equal(t, int64(8192), c.Size())
+	c3, err := c.get(AssetAudio, "c.bin", decode)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, decodes)
+	c.Purge()
+	assert.Equal(t, []AssetInfo{
+		{Name: "c.bin", Type: AssetAudio, Refs: 1, Bytes: 4096},
+	}, c.Assets())
+	c3.Release()
+	st = c.Stats()[AssetAudio]
+	assert.Equal(t, 0, st.Refs)
+	assert.Equal(t, int64(2), st.Evictions)
+}
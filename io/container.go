@@ -3,14 +3,13 @@ package io
 import (
 	"bytes"
 	"context"
-	"errors"
 	"image"
 	"io"
-	"log"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/gabstv/primen/core/logger"
 	"github.com/gabstv/primen/dom"
 )
 
@@ -18,8 +17,20 @@ type Container interface {
 	Len() int64
 	FS() Filesystem
 	Load(name string) chan struct{}
+	// LoadWith loads a file with a per-call context and options. If the file
+	// is loaded or loading, the existing result is returned (failed loads are
+	// started again).
+	LoadWith(ctx context.Context, name string, opt LoadOptions) *LoadResult
+	// Result returns the handle of the last load of a file (or nil)
+	Result(name string) *LoadResult
+	// Unload waits for the file to finish loading and unloads it
 	Unload(name string) (bool, error)
+	// UnloadCtx is like Unload, but it stops waiting when ctx is done
+	UnloadCtx(ctx context.Context, name string) (bool, error)
 	LoadAll(names []string) (progress chan float64, done chan struct{})
+	// LoadAllWith loads the files (in order) with a per-call context and
+	// options. The failures of each file are reported by the result.
+	LoadAllWith(ctx context.Context, names []string, opt LoadOptions) *LoadAllResult
 	UnloadAll()
 	Get(name string) ([]byte, error)
 	GetImage(name string) (image.Image, error)
@@ -27,6 +38,9 @@ type Container interface {
 	GetAudioStream(name string) (*AudioStream, error)
 	GetAudioBytes(name string) ([]byte, error)
 	GetXMLDOM(name string) ([]dom.Node, error)
+	GetPrefab(name string) (*Prefab, error)
+	// LoadErr returns the error of the last load of a file (or nil)
+	LoadErr(name string) error
 }
 
 type container struct {
@@ -34,7 +48,7 @@ type container struct {
 	fs           Filesystem
 	m            sync.RWMutex
 	loadedfiles  map[string][]byte
-	loadingfiles map[string]chan struct{}
+	loads        map[string]*LoadResult
 	loadedlen    int64 // atomic
 	loadinglen   int64 // atomic
 	loadingn     int32 // atomic
@@ -50,105 +64,207 @@ func (c *container) FS() Filesystem {
 }
 
 func (c *container) Load(name string) chan struct{} {
-	c.m.RLock()
-	xch, ok := c.loadingfiles[name]
-	c.m.RUnlock()
-	if ok {
+	return c.LoadWith(c.ctx, name, LoadOptions{}).done
+}
+
+func (c *container) LoadWith(ctx context.Context, name string, opt LoadOptions) *LoadResult {
+	if ctx == nil {
+		ctx = c.ctx
+	}
+	c.m.Lock()
+	if r, ok := c.loads[name]; ok && !r.failed() {
 		// already loading/loaded
-		return xch
+		c.m.Unlock()
+		return r
 	}
+	r := newLoadResult(name)
+	c.loads[name] = r
+	c.m.Unlock()
 	atomic.AddInt32(&c.loadingn, 1)
-	ldch := make(chan struct{}, 1)
-	go func() {
-		defer close(ldch)
-		c.m.Lock()
-		c.loadingfiles[name] = ldch
-		c.m.Unlock()
-		defer atomic.AddInt32(&c.loadingn, -1)
-		f, err := c.fs.Open(name)
-		if err != nil {
-			log.Println("container io error: " + err.Error())
-			return
+	go c.load(ctx, r, opt)
+	return r
+}
+
+func (c *container) load(ctx context.Context, r *LoadResult, opt LoadOptions) {
+	defer atomic.AddInt32(&c.loadingn, -1)
+	ctx, cancel := mergeCtx(ctx, c.ctx)
+	defer cancel()
+	var b []byte
+	var err error
+	for {
+		r.attempts++
+		if b, err = c.read(ctx, r.name, opt.Timeout); err == nil {
+			break
 		}
-		defer f.Close()
-		fi, err := f.Stat()
-		if err != nil {
-			log.Println("container stat error: " + err.Error())
-			return
+		if r.attempts > opt.Retries || !retryable(err) || ctx.Err() != nil {
+			break
 		}
-		atomic.AddInt64(&c.loadinglen, fi.Size())
-		defer atomic.AddInt64(&c.loadinglen, -fi.Size())
-		buf := new(bytes.Buffer)
-		buf.Grow(int(fi.Size()))
-		if _, err := buf.ReadFrom(f); err != nil {
-			log.Println("container io error: " + err.Error())
-			return
+		c.log().Warn("load failed, retrying", logger.F("file", r.name), logger.F("attempt", r.attempts), logger.Err(err))
+		t := time.NewTimer(opt.retryDelay())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			err = ctx.Err()
+		case <-t.C:
+			continue
 		}
-		c.m.Lock()
-		c.loadedfiles[name] = buf.Bytes()
-		c.m.Unlock()
-		atomic.AddInt64(&c.loadedlen, int64(buf.Len()))
+		break
+	}
+	if err != nil {
+		c.log().Error("load failed", logger.F("file", r.name), logger.F("attempts", r.attempts), logger.Err(err))
+		r.err = err
+		close(r.done)
+		return
+	}
+	r.size = int64(len(b))
+	c.m.Lock()
+	c.loadedfiles[r.name] = b
+	c.m.Unlock()
+	atomic.AddInt64(&c.loadedlen, r.size)
+	close(r.done)
+}
+
+// read reads a file. The read is abandoned when ctx is done or after the
+// timeout.
+func (c *container) read(ctx context.Context, name string, timeout time.Duration) ([]byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type readResult struct {
+		b   []byte
+		err error
+	}
+	ch := make(chan readResult, 1)
+	go func() {
+		b, err := c.readFile(ctx, name)
+		ch <- readResult{b, err}
 	}()
-	return ldch
+	select {
+	case rr := <-ch:
+		return rr.b, rr.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
-func (c *container) Unload(name string) (bool, error) {
+func (c *container) readFile(ctx context.Context, name string) ([]byte, error) {
+	f, err := c.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&c.loadinglen, fi.Size())
+	defer atomic.AddInt64(&c.loadinglen, -fi.Size())
+	buf := new(bytes.Buffer)
+	buf.Grow(int(fi.Size()))
+	if _, err := buf.ReadFrom(&ctxReader{ctx: ctx, r: f}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// log returns the logger of the container context (see logger.NewContext)
+func (c *container) log() *logger.Logger {
+	return logger.FromContext(c.ctx).Tag("io")
+}
+
+func (c *container) Result(name string) *LoadResult {
 	c.m.RLock()
-	loadingch, ok := c.loadingfiles[name]
-	c.m.RUnlock()
-	if !ok {
+	defer c.m.RUnlock()
+	return c.loads[name]
+}
+
+func (c *container) LoadErr(name string) error {
+	if r := c.Result(name); r != nil {
+		return r.Err()
+	}
+	return nil
+}
+
+func (c *container) Unload(name string) (bool, error) {
+	return c.UnloadCtx(context.Background(), name)
+}
+
+func (c *container) UnloadCtx(ctx context.Context, name string) (bool, error) {
+	r := c.Result(name)
+	if r == nil {
 		return false, nil
 	}
 	select {
-	case <-time.After(time.Microsecond * 500):
-		return false, errors.New("resource is loading")
-	case <-loadingch:
+	case <-r.done:
+	case <-ctx.Done():
+		return false, ctx.Err()
 	}
 	c.m.Lock()
-	delete(c.loadingfiles, name)
+	if c.loads[name] != r {
+		// loaded again (after a failure) while waiting
+		c.m.Unlock()
+		return false, nil
+	}
+	delete(c.loads, name)
 	x := len(c.loadedfiles[name])
 	delete(c.loadedfiles, name)
 	c.m.Unlock()
-	atomic.AddInt64(&c.loadedlen, int64(x))
+	atomic.AddInt64(&c.loadedlen, -int64(x))
 	return true, nil
 }
 
 func (c *container) LoadAll(names []string) (progress chan float64, done chan struct{}) {
-	progress = make(chan float64, 64)
-	done = make(chan struct{})
-	if len(names) < 1 {
-		close(done)
-		return
+	r := c.LoadAllWith(c.ctx, names, LoadOptions{})
+	return r.progress, r.done
+}
+
+func (c *container) LoadAllWith(ctx context.Context, names []string, opt LoadOptions) *LoadAllResult {
+	if ctx == nil {
+		ctx = c.ctx
+	}
+	r := &LoadAllResult{
+		progress: make(chan float64, len(names)),
+		done:     make(chan struct{}),
+		results:  make([]*LoadResult, len(names)),
 	}
 	go func() {
-		defer close(done)
-		defer func() {
-			select {
-			case <-c.ctx.Done():
-			case <-time.After(time.Second):
-			}
-			close(progress)
-		}()
+		defer close(r.done)
+		defer close(r.progress)
 		var step float64 = 1 / float64(len(names))
 		var current float64
-		for _, name := range names {
-			done1 := c.Load(name)
+		for i, name := range names {
+			if err := ctx.Err(); err != nil {
+				r.results[i] = failedLoadResult(name, err)
+				continue
+			}
+			lr := c.LoadWith(ctx, name, opt)
+			r.results[i] = lr
 			select {
-			case <-c.ctx.Done():
-				return
-			case <-done1:
+			case <-ctx.Done():
+				// canceled (the progress is not reported)
+				continue
+			case <-lr.done:
 			}
 			current += step
-			progress <- current
+			r.progress <- current
+		}
+		// wait for the loads that were canceled
+		for _, lr := range r.results {
+			<-lr.done
 		}
 	}()
-	return
+	return r
 }
 
 func (c *container) UnloadAll() {
 	c.m.RLock()
-	names := make([]string, 0, len(c.loadingfiles))
-	for name, _ := range c.loadingfiles {
+	names := make([]string, 0, len(c.loads))
+	for name := range c.loads {
 		names = append(names, name)
 	}
 	c.m.RUnlock()
@@ -157,6 +273,8 @@ func (c *container) UnloadAll() {
 	}
 }
 
+// Get returns the contents of a file. If the file is not loaded, it is
+// loaded (and the load error is returned).
 func (c *container) Get(name string) ([]byte, error) {
 	c.m.RLock()
 	fd, ok := c.loadedfiles[name]
@@ -164,30 +282,14 @@ func (c *container) Get(name string) ([]byte, error) {
 	if ok {
 		return fd, nil
 	}
-	c.m.RLock()
-	fch, ok := c.loadingfiles[name]
-	c.m.RUnlock()
-	if ok {
-		<-fch
-		c.m.RLock()
-		fd = c.loadedfiles[name]
-		c.m.RUnlock()
-		if fd == nil {
-			return nil, errors.New("x not found")
-		}
-		return fd, nil
-	}
-	// check if exists
-	if _, err := c.fs.Stat(name); err != nil {
+	if err := c.LoadWith(c.ctx, name, LoadOptions{}).Wait(); err != nil {
 		return nil, err
 	}
-	ch := c.Load(name)
-	<-ch
 	c.m.RLock()
-	fd = c.loadedfiles[name]
+	fd, ok = c.loadedfiles[name]
 	c.m.RUnlock()
-	if fd == nil {
-		return nil, errors.New("x not found")
+	if !ok {
+		return nil, ErrNotLoaded
 	}
 	return fd, nil
 }
@@ -239,12 +341,23 @@ func (c *container) GetXMLDOM(name string) ([]dom.Node, error) {
 	return dom.ParseXMLString(string(b))
 }
 
+func (c *container) GetPrefab(name string) (*Prefab, error) {
+	b, err := c.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrefab(b)
+}
+
 func NewContainer(ctx context.Context, fs Filesystem) Container {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	c := &container{
-		ctx:          ctx,
-		fs:           fs,
-		loadedfiles:  make(map[string][]byte),
-		loadingfiles: make(map[string]chan struct{}),
+		ctx:         ctx,
+		fs:          fs,
+		loadedfiles: make(map[string][]byte),
+		loads:       make(map[string]*LoadResult),
 	}
 	return c
 }
@@ -2,9 +2,12 @@ package io
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -106,3 +109,96 @@ func TestContainer(t *testing.T) {
 	assert.InEpsilon(t, 0.9999999, f3, 0.01)
 	<-donech
 }
+
+func TestContainerLoadErr(t *testing.T) {
+	fsfs := &testfs{
+		okfiles: map[string]bool{
+			"a.txt": true,
+		},
+	}
+	c := NewContainer(context.Background(), fsfs)
+	_, donech := c.LoadAll([]string{"a.txt", "missing.txt"})
+	<-donech
+	assert.NoError(t, c.LoadErr("a.txt"))
+	assert.True(t, os.IsNotExist(c.LoadErr("missing.txt")))
+	_, err := c.Unload("missing.txt")
+	assert.NoError(t, err)
+	assert.NoError(t, c.LoadErr("missing.txt"))
+}
+
+// flakyfs fails the first opens of a file and delays the others
+type flakyfs struct {
+	testfs
+	m     sync.Mutex
+	fails map[string]int
+	delay time.Duration
+}
+
+func (fs *flakyfs) Open(name string) (File, error) {
+	fs.m.Lock()
+	n := fs.fails[name]
+	if n > 0 {
+		fs.fails[name] = n - 1
+	}
+	fs.m.Unlock()
+	if n > 0 {
+		return nil, errors.New("device busy")
+	}
+	time.Sleep(fs.delay)
+	return fs.testfs.Open(name)
+}
+
+func TestContainerLoadWith(t *testing.T) {
+	fsfs := &flakyfs{
+		testfs: testfs{
+			okfiles: map[string]bool{
+				"a.txt": true,
+				"b.txt": true,
+				"c.txt": true,
+			},
+		},
+		fails: map[string]int{
+			"a.txt": 2,
+			"b.txt": 5,
+		},
+	}
+	c := NewContainer(context.Background(), fsfs)
+	opt := LoadOptions{
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+	}
+	r := c.LoadWith(context.Background(), "a.txt", opt)
+	assert.NoError(t, r.Wait())
+	assert.Equal(t, 3, r.Attempts())
+	assert.Equal(t, int64(4096), r.Size())
+	assert.True(t, c.Result("a.txt").Loaded())
+
+	all := c.LoadAllWith(context.Background(), []string{"a.txt", "b.txt", "missing.txt"}, opt)
+	err := all.Wait()
+	assert.Error(t, err)
+	assert.Equal(t, "b.txt", err.(*FileError).Name)
+	errs := all.Errs()
+	assert.Equal(t, 2, len(errs))
+	assert.EqualError(t, errs["b.txt"], "device busy")
+	assert.True(t, os.IsNotExist(errs["missing.txt"]))
+	_, err = c.Get("missing.txt")
+	assert.True(t, os.IsNotExist(err))
+
+	fsfs.delay = time.Millisecond * 50
+	r = c.LoadWith(context.Background(), "c.txt", LoadOptions{
+		Timeout: time.Millisecond,
+	})
+	assert.Equal(t, context.DeadlineExceeded, r.Wait())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r = c.LoadWith(ctx, "c.txt", LoadOptions{})
+	cancel()
+	assert.Equal(t, context.Canceled, r.Wait())
+
+	r = c.LoadWith(context.Background(), "c.txt", LoadOptions{})
+	ok, err := c.Unload("c.txt")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, r.Loaded())
+	assert.Equal(t, int64(4096), c.Len())
+}
@@ -8,4 +8,5 @@ func (e Error) Error() string {
 
 const (
 	ErrUnsupportedAudioType Error = "unsupported audio type and/or extension"
+	ErrNotLoaded            Error = "file is not loaded"
 )
@@ -0,0 +1,245 @@
+package io
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// DefaultRetryDelay is the delay between the attempts of a load (when
+// LoadOptions.RetryDelay is 0)
+const DefaultRetryDelay = time.Millisecond * 100
+
+// LoadOptions are the options of Container.LoadWith and
+// Container.LoadAllWith
+type LoadOptions struct {
+	// Retries is the number of times a failed load is retried. Missing files
+	// and canceled loads are not retried.
+	Retries int
+	// RetryDelay is the delay between the attempts (default: 100ms)
+	RetryDelay time.Duration
+	// Timeout is the max duration of each attempt (0 = no timeout)
+	Timeout time.Duration
+}
+
+func (o LoadOptions) retryDelay() time.Duration {
+	if o.RetryDelay <= 0 {
+		return DefaultRetryDelay
+	}
+	return o.RetryDelay
+}
+
+// LoadResult is the handle of a file load
+type LoadResult struct {
+	name     string
+	done     chan struct{}
+	err      error
+	size     int64
+	attempts int
+}
+
+func newLoadResult(name string) *LoadResult {
+	return &LoadResult{
+		name: name,
+		done: make(chan struct{}),
+	}
+}
+
+// failedLoadResult returns a result that is already done
+func failedLoadResult(name string, err error) *LoadResult {
+	r := newLoadResult(name)
+	r.err = err
+	close(r.done)
+	return r
+}
+
+// Name returns the name of the file
+func (r *LoadResult) Name() string {
+	return r.name
+}
+
+// Done is closed when the load ends (successfully or not)
+func (r *LoadResult) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait waits for the load to end and returns its error
+func (r *LoadResult) Wait() error {
+	<-r.done
+	return r.err
+}
+
+// WaitCtx is like Wait, but it stops waiting when ctx is done (the load is
+// not canceled)
+func (r *LoadResult) WaitCtx(ctx context.Context) error {
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Err returns the error of the load (nil if it succeeded or if it is still
+// loading)
+func (r *LoadResult) Err() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
+
+// Loaded returns true if the load ended without errors
+func (r *LoadResult) Loaded() bool {
+	select {
+	case <-r.done:
+		return r.err == nil
+	default:
+		return false
+	}
+}
+
+// Size returns the size of the loaded file
+func (r *LoadResult) Size() int64 {
+	select {
+	case <-r.done:
+		return r.size
+	default:
+		return 0
+	}
+}
+
+// Attempts returns the number of attempts made to load the file (after the
+// load ends)
+func (r *LoadResult) Attempts() int {
+	select {
+	case <-r.done:
+		return r.attempts
+	default:
+		return 0
+	}
+}
+
+func (r *LoadResult) failed() bool {
+	select {
+	case <-r.done:
+		return r.err != nil
+	default:
+		return false
+	}
+}
+
+// FileError is the error of a file of Container.LoadAllWith
+type FileError struct {
+	Name string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// LoadAllResult is the handle of Container.LoadAllWith
+type LoadAllResult struct {
+	progress chan float64
+	done     chan struct{}
+	results  []*LoadResult
+}
+
+// Progress receives the progress (0 to 1) after each file; it is closed
+// before Done
+func (r *LoadAllResult) Progress() <-chan float64 {
+	return r.progress
+}
+
+// Done is closed when all the loads end
+func (r *LoadAllResult) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait waits for all the loads to end. It returns the first error (a
+// *FileError).
+func (r *LoadAllResult) Wait() error {
+	<-r.done
+	for _, v := range r.results {
+		if v.err != nil {
+			return &FileError{
+				Name: v.name,
+				Err:  v.err,
+			}
+		}
+	}
+	return nil
+}
+
+// Results returns the result of each file (in the order of the names).
+// Call it after Done.
+func (r *LoadAllResult) Results() []*LoadResult {
+	<-r.done
+	out := make([]*LoadResult, len(r.results))
+	copy(out, r.results)
+	return out
+}
+
+// Errs returns the errors of the files that failed to load (nil if all the
+// files were loaded). It waits for all the loads to end.
+func (r *LoadAllResult) Errs() map[string]error {
+	<-r.done
+	var out map[string]error
+	for _, v := range r.results {
+		if v.err != nil {
+			if out == nil {
+				out = make(map[string]error)
+			}
+			out[v.name] = v.err
+		}
+	}
+	return out
+}
+
+// retryable returns false for errors that will not change on a new attempt
+func retryable(err error) bool {
+	return !os.IsNotExist(err) && err != context.Canceled
+}
+
+// mergeCtx returns a context that is canceled when a or b is done
+func mergeCtx(a, b context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(a)
+	if b == nil || b == a || b.Done() == nil {
+		return ctx, cancel
+	}
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-b.Done():
+			cancel()
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		close(stop)
+		cancel()
+	}
+}
+
+// ctxReader stops reading when the context is done
+type ctxReader struct {
+	ctx context.Context
+	r   interface {
+		Read(p []byte) (int, error)
+	}
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
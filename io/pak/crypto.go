@@ -0,0 +1,87 @@
+package pak
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+)
+
+// KeyFromString derives a 32 byte key from a passphrase
+func KeyFromString(passphrase string) []byte {
+	sum := sha256.Sum256([]byte(passphrase))
+	return sum[:]
+}
+
+// keys are the content encryption key and the manifest signature key
+// (derived from the key given to Open or to the Writer)
+type keys struct {
+	aead cipher.AEAD
+	mac  []byte
+}
+
+func deriveKeys(key []byte) (*keys, error) {
+	enc := sha256.Sum256(append([]byte("primen-pak-enc:"), key...))
+	mac := sha256.Sum256(append([]byte("primen-pak-mac:"), key...))
+	block, err := aes.NewCipher(enc[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &keys{
+		aead: aead,
+		mac:  mac[:],
+	}, nil
+}
+
+func (k *keys) sign(b []byte) []byte {
+	h := hmac.New(sha256.New, k.mac)
+	_, _ = h.Write(b)
+	return h.Sum(nil)
+}
+
+// encrypt returns nonce + ciphertext
+func (k *keys) encrypt(b []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return k.aead.Seal(nonce, nonce, b, nil), nil
+}
+
+func (k *keys) decrypt(b []byte) ([]byte, error) {
+	ns := k.aead.NonceSize()
+	if len(b) < ns {
+		return nil, errors.New("pak: encrypted entry too short")
+	}
+	return k.aead.Open(nil, b[:ns], b[ns:], nil)
+}
+
+func deflate(b []byte, level int) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w, err := flate.NewWriter(buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func inflate(b []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(b))
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
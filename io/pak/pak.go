@@ -0,0 +1,361 @@
+// Package pak is an io.Filesystem backed by a pak archive.
+//
+// A pak is a zip archive with a manifest of the SHA-256 checksums of all
+// the entries; the checksums are verified when the pak is opened. The
+// entries can be compressed (deflate) and encrypted (AES-GCM). A pak built
+// with a key also has a signature (HMAC-SHA256) of the manifest, so the
+// entries can't be replaced or added without the key. A pak built without a
+// key has no tamper protection: the checksums only detect corruption.
+//
+// Build a pak with a Writer (or with BuildDir and the cmd/primenpak tool)
+// and open it at startup:
+//
+//	fs, err := pak.Open("game.pak", pak.KeyFromString(key))
+//	if err != nil {
+//		panic(err)
+//	}
+//	engine := primen.NewEngine(&primen.NewEngineInput{
+//		FS: fs,
+//	})
+package pak
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gabstv/primen/io"
+)
+
+// Error is a pak error
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrNoManifest is returned when the archive is not a pak
+	ErrNoManifest Error = "pak: manifest not found"
+	// ErrKeyRequired is returned when an encrypted pak is opened without a key
+	ErrKeyRequired Error = "pak: the archive is encrypted (key required)"
+	// ErrBadSignature is returned when the manifest signature doesn't match
+	// (wrong key or modified manifest)
+	ErrBadSignature Error = "pak: invalid manifest signature"
+	// ErrChecksum is returned when the contents of an entry don't match the
+	// manifest
+	ErrChecksum Error = "pak: checksum mismatch"
+	// ErrUnlisted is returned when the archive has entries that are not in
+	// the manifest
+	ErrUnlisted Error = "pak: entry not in the manifest"
+	// ErrVersion is returned when the pak was built with an unsupported
+	// version of the format
+	ErrVersion Error = "pak: unsupported version"
+	// ErrBadChecksum is returned when a checksum of the manifest is not a
+	// SHA-256 hex string
+	ErrBadChecksum Error = "pak: invalid checksum in the manifest"
+)
+
+const (
+	manifestName  = "_pak/manifest.json"
+	signatureName = "_pak/manifest.sig"
+	// Version is the version of the pak format
+	Version = 1
+)
+
+// Manifest lists the entries of a pak
+type Manifest struct {
+	Version   int              `json:"version"`
+	Encrypted bool             `json:"encrypted"`
+	Signed    bool             `json:"signed"`
+	Entries   map[string]Entry `json:"entries"`
+}
+
+// Entry is a file of a pak
+type Entry struct {
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	Compressed bool   `json:"compressed"`
+}
+
+// FS is a pak archive. It implements io.Filesystem.
+type FS struct {
+	closer   func() error
+	files    map[string]*zip.File
+	dirs     map[string]bool
+	manifest Manifest
+	keys     *keys
+	modTime  time.Time
+}
+
+var _ io.Filesystem = (*FS)(nil)
+
+// Open opens a pak file. The key is required if the pak is encrypted (see
+// KeyFromString). If a key is given, the pak must be signed with it.
+func Open(name string, key []byte) (*FS, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	fs, err := OpenReader(f, fi.Size(), key)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	fs.closer = f.Close
+	fs.modTime = fi.ModTime()
+	return fs, nil
+}
+
+// OpenReader opens a pak from a reader (an embedded pak). The manifest, its
+// signature and the checksums of all the entries are verified (see Verify).
+// The checksum of an entry is verified again when the entry is opened.
+func OpenReader(r interface {
+	ReadAt(p []byte, off int64) (int, error)
+}, size int64, key []byte) (*FS, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	fs := &FS{
+		files: make(map[string]*zip.File),
+		dirs:  map[string]bool{"": true},
+	}
+	var mfile, sfile *zip.File
+	for _, f := range zr.File {
+		switch f.Name {
+		case manifestName:
+			mfile = f
+		case signatureName:
+			sfile = f
+		default:
+			if !strings.HasSuffix(f.Name, "/") {
+				fs.files[f.Name] = f
+			}
+		}
+	}
+	if mfile == nil {
+		return nil, ErrNoManifest
+	}
+	mraw, err := readZipFile(mfile)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(mraw, &fs.manifest); err != nil {
+		return nil, err
+	}
+	if fs.manifest.Version != Version {
+		return nil, ErrVersion
+	}
+	if fs.manifest.Encrypted && len(key) == 0 {
+		return nil, ErrKeyRequired
+	}
+	if len(key) > 0 {
+		// a pak opened with a key must be signed with it, even if the
+		// entries are not encrypted
+		if fs.keys, err = deriveKeys(key); err != nil {
+			return nil, err
+		}
+		if sfile == nil {
+			return nil, ErrBadSignature
+		}
+		sig, err := readZipFile(sfile)
+		if err != nil {
+			return nil, err
+		}
+		want, err := hex.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || !hmac.Equal(want, fs.keys.sign(mraw)) {
+			return nil, ErrBadSignature
+		}
+	}
+	for name, entry := range fs.manifest.Entries {
+		if b, err := hex.DecodeString(entry.SHA256); err != nil || len(b) != sha256.Size {
+			return nil, &os.PathError{Op: "open", Path: name, Err: ErrBadChecksum}
+		}
+	}
+	for name := range fs.files {
+		if _, ok := fs.manifest.Entries[name]; !ok {
+			return nil, &os.PathError{Op: "open", Path: name, Err: ErrUnlisted}
+		}
+	}
+	for name := range fs.manifest.Entries {
+		if _, ok := fs.files[name]; !ok {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			fs.dirs[dir] = true
+		}
+	}
+	if err := fs.Verify(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Close closes the pak file (if opened with Open)
+func (fs *FS) Close() error {
+	if fs.closer != nil {
+		return fs.closer()
+	}
+	return nil
+}
+
+// Manifest returns the manifest of the pak
+func (fs *FS) Manifest() Manifest {
+	m := fs.manifest
+	m.Entries = make(map[string]Entry, len(fs.manifest.Entries))
+	for k, v := range fs.manifest.Entries {
+		m.Entries[k] = v
+	}
+	return m
+}
+
+// Names returns the names of the files (sorted)
+func (fs *FS) Names() []string {
+	out := make([]string, 0, len(fs.files))
+	for name := range fs.files {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Verify reads all the entries and verifies their checksums
+func (fs *FS) Verify() error {
+	for _, name := range fs.Names() {
+		if _, err := fs.ReadFile(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile returns the contents of a file (decrypted and decompressed). The
+// checksum is verified.
+func (fs *FS) ReadFile(name string) ([]byte, error) {
+	name = cleanName(name)
+	f, ok := fs.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	entry := fs.manifest.Entries[name]
+	b, err := readZipFile(f)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	if fs.manifest.Encrypted {
+		if b, err = fs.keys.decrypt(b); err != nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		if entry.Compressed {
+			if b, err = inflate(b); err != nil {
+				return nil, &os.PathError{Op: "open", Path: name, Err: err}
+			}
+		}
+	}
+	sum := sha256.Sum256(b)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 || int64(len(b)) != entry.Size {
+		return nil, &os.PathError{Op: "open", Path: name, Err: ErrChecksum}
+	}
+	return b, nil
+}
+
+// Open implements io.Filesystem. The file is read into memory.
+func (fs *FS) Open(name string) (io.File, error) {
+	b, err := fs.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &file{
+		Reader: bytes.NewReader(b),
+		info: fileInfo{
+			name:    path.Base(cleanName(name)),
+			size:    int64(len(b)),
+			modTime: fs.modTime,
+		},
+	}, nil
+}
+
+// Stat implements io.Filesystem
+func (fs *FS) Stat(name string) (io.FileInfo, error) {
+	name = cleanName(name)
+	if entry, ok := fs.manifest.Entries[name]; ok {
+		return fileInfo{
+			name:    path.Base(name),
+			size:    entry.Size,
+			modTime: fs.modTime,
+		}, nil
+	}
+	if fs.dirs[name] {
+		return fileInfo{
+			name:    path.Base(name),
+			dir:     true,
+			modTime: fs.modTime,
+		}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+func cleanName(name string) string {
+	name = path.Clean("/" + strings.Replace(name, "\\", "/", -1))
+	return strings.TrimPrefix(name, "/")
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+type file struct {
+	*bytes.Reader
+	info fileInfo
+}
+
+func (f *file) Close() error {
+	return nil
+}
+
+func (f *file) Stat() (io.FileInfo, error) {
+	return f.info, nil
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	dir     bool
+	modTime time.Time
+}
+
+func (fi fileInfo) Name() string {
+	return fi.name
+}
+
+func (fi fileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fi fileInfo) IsDir() bool {
+	return fi.dir
+}
+
+func (fi fileInfo) ModTime() time.Time {
+	return fi.modTime
+}
@@ -0,0 +1,110 @@
+package pak
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gabstv/primen/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestPak(t *testing.T, opt WriterOptions, edit ...func(m *Manifest)) []byte {
+	buf := new(bytes.Buffer)
+	w, err := NewWriter(buf, opt)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Add("config.json", bytes.Repeat([]byte(`{"a":1}`), 64)))
+	assert.NoError(t, w.Add("img/player.png", []byte("not really a png")))
+	assert.Error(t, w.Add("/img/player.png", []byte("dup")))
+	for _, fn := range edit {
+		fn(&w.manifest)
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestPak(t *testing.T) {
+	for _, opt := range []WriterOptions{
+		{},
+		{Key: KeyFromString("secret")},
+		{Key: KeyFromString("secret"), NoEncryption: true},
+	} {
+		b := buildTestPak(t, opt)
+		fs, err := OpenReader(bytes.NewReader(b), int64(len(b)), opt.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"config.json", "img/player.png"}, fs.Names())
+		assert.Equal(t, opt.Key != nil && !opt.NoEncryption, fs.Manifest().Encrypted)
+		assert.Equal(t, opt.Key != nil, fs.Manifest().Signed)
+		assert.NoError(t, fs.Verify())
+
+		data, err := io.ReadFile("img/player.png", fs)
+		assert.NoError(t, err)
+		assert.Equal(t, "not really a png", string(data))
+		data, err = io.ReadFile("./config.json", fs)
+		assert.NoError(t, err)
+		assert.Equal(t, 7*64, len(data))
+
+		fi, err := fs.Stat("img")
+		assert.NoError(t, err)
+		assert.True(t, fi.IsDir())
+		fi, err = fs.Stat("img/player.png")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(16), fi.Size())
+		_, err = fs.Open("missing.txt")
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestPakIntegrity(t *testing.T) {
+	key := KeyFromString("secret")
+	b := buildTestPak(t, WriterOptions{Key: key})
+
+	_, err := OpenReader(bytes.NewReader(b), int64(len(b)), nil)
+	assert.Equal(t, ErrKeyRequired, err)
+	_, err = OpenReader(bytes.NewReader(b), int64(len(b)), KeyFromString("wrong"))
+	assert.Equal(t, ErrBadSignature, err)
+
+	// replace the contents of an entry (same size)
+	i := bytes.Index(b, []byte("img/player.png"))
+	assert.True(t, i > 0)
+	tampered := make([]byte, len(b))
+	copy(tampered, b)
+	j := i + len("img/player.png")
+	tampered[j+4] ^= 0xff
+	_, err = OpenReader(bytes.NewReader(tampered), int64(len(tampered)), key)
+	assert.Error(t, err)
+
+	// signed paks (not encrypted) are verified with the key
+	b = buildTestPak(t, WriterOptions{Key: key, NoEncryption: true})
+	_, err = OpenReader(bytes.NewReader(b), int64(len(b)), KeyFromString("wrong"))
+	assert.Equal(t, ErrBadSignature, err)
+	_, err = OpenReader(bytes.NewReader(b), int64(len(b)), nil)
+	assert.NoError(t, err)
+
+	// unsigned paks detect corruption with the checksums
+	b = buildTestPak(t, WriterOptions{})
+	_, err = OpenReader(bytes.NewReader(b), int64(len(b)), key)
+	assert.Equal(t, ErrBadSignature, err)
+	i = bytes.Index(b, []byte("not really a png"))
+	assert.True(t, i > 0)
+	b[i] = 'N'
+	_, err = OpenReader(bytes.NewReader(b), int64(len(b)), nil)
+	assert.Error(t, err)
+}
+
+func TestPakManifest(t *testing.T) {
+	b := buildTestPak(t, WriterOptions{}, func(m *Manifest) {
+		m.Version = Version + 1
+	})
+	_, err := OpenReader(bytes.NewReader(b), int64(len(b)), nil)
+	assert.Equal(t, ErrVersion, err)
+
+	b = buildTestPak(t, WriterOptions{}, func(m *Manifest) {
+		e := m.Entries["config.json"]
+		e.SHA256 = "abc"
+		m.Entries["config.json"] = e
+	})
+	_, err = OpenReader(bytes.NewReader(b), int64(len(b)), nil)
+	assert.True(t, errors.Is(err, ErrBadChecksum))
+}
@@ -0,0 +1,232 @@
+package pak
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WriterOptions are the options of NewWriter
+type WriterOptions struct {
+	// Key signs the manifest and encrypts the entries (nil = no signature
+	// and no encryption)
+	Key []byte
+	// NoEncryption only signs the manifest with the Key; the entries are
+	// not encrypted
+	NoEncryption bool
+	// Level is the deflate level (default: flate.DefaultCompression)
+	Level int
+	// Compress returns true if the entry should be compressed (default:
+	// DefaultCompress)
+	Compress func(name string) bool
+}
+
+// DefaultCompress compresses all the files except the ones that are already
+// compressed (images, audio and archives)
+func DefaultCompress(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".ogg", ".mp3", ".zip", ".gz", ".pak":
+		return false
+	}
+	return true
+}
+
+// Writer builds a pak
+type Writer struct {
+	zw       *zip.Writer
+	opt      WriterOptions
+	keys     *keys
+	manifest Manifest
+	closed   bool
+}
+
+// NewWriter returns a Writer that writes a pak to w. Close must be called to
+// write the manifest.
+func NewWriter(w io.Writer, opt WriterOptions) (*Writer, error) {
+	pw := &Writer{
+		zw:  zip.NewWriter(w),
+		opt: opt,
+		manifest: Manifest{
+			Version:   Version,
+			Encrypted: len(opt.Key) > 0 && !opt.NoEncryption,
+			Signed:    len(opt.Key) > 0,
+			Entries:   make(map[string]Entry),
+		},
+	}
+	if pw.opt.Level == 0 {
+		pw.opt.Level = flate.DefaultCompression
+	}
+	if pw.opt.Compress == nil {
+		pw.opt.Compress = DefaultCompress
+	}
+	if pw.manifest.Signed {
+		k, err := deriveKeys(opt.Key)
+		if err != nil {
+			return nil, err
+		}
+		pw.keys = k
+	}
+	if pw.opt.Level != flate.DefaultCompression {
+		level := pw.opt.Level
+		pw.zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, level)
+		})
+	}
+	return pw, nil
+}
+
+// Add adds a file to the pak
+func (w *Writer) Add(name string, data []byte) error {
+	if w.closed {
+		return errors.New("pak: writer is closed")
+	}
+	name = cleanName(name)
+	if name == "" || strings.HasPrefix(name, "_pak/") {
+		return &os.PathError{Op: "add", Path: name, Err: os.ErrInvalid}
+	}
+	if _, ok := w.manifest.Entries[name]; ok {
+		return &os.PathError{Op: "add", Path: name, Err: os.ErrExist}
+	}
+	sum := sha256.Sum256(data)
+	entry := Entry{
+		Size:       int64(len(data)),
+		SHA256:     hex.EncodeToString(sum[:]),
+		Compressed: w.opt.Compress(name),
+	}
+	method := zip.Store
+	if w.manifest.Encrypted {
+		// encrypted data can't be compressed, so the data is compressed
+		// before the encryption and the zip entry is stored
+		var err error
+		if entry.Compressed {
+			if data, err = deflate(data, w.opt.Level); err != nil {
+				return err
+			}
+		}
+		if data, err = w.keys.encrypt(data); err != nil {
+			return err
+		}
+	} else if entry.Compressed {
+		method = zip.Deflate
+	}
+	if err := w.write(name, method, data); err != nil {
+		return err
+	}
+	w.manifest.Entries[name] = entry
+	return nil
+}
+
+// AddFile adds a file of the OS filesystem to the pak
+func (w *Writer) AddFile(name, filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return w.Add(name, data)
+}
+
+// Close writes the manifest (and its signature) and closes the pak. It
+// doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	mraw, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := w.write(manifestName, zip.Deflate, mraw); err != nil {
+		return err
+	}
+	if w.keys != nil {
+		sig := hex.EncodeToString(w.keys.sign(mraw))
+		if err := w.write(signatureName, zip.Store, []byte(sig)); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) write(name string, method uint16, data []byte) error {
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: method,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// BuildDir builds a pak (outfile) with all the files of dir. Hidden files
+// (names starting with ".") are skipped. It returns the number of files.
+func BuildDir(dir, outfile string, opt WriterOptions) (int, error) {
+	f, err := os.Create(outfile)
+	if err != nil {
+		return 0, err
+	}
+	n, err := buildDir(f, dir, outfile, opt)
+	if err != nil {
+		f.Close()
+		os.Remove(outfile)
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func buildDir(f io.Writer, dir, outfile string, opt WriterOptions) (int, error) {
+	w, err := NewWriter(f, opt)
+	if err != nil {
+		return 0, err
+	}
+	outabs, _ := filepath.Abs(outfile)
+	n := 0
+	err = filepath.Walk(dir, func(fpath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fpath != dir && strings.HasPrefix(fi.Name(), ".") {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		if abs, _ := filepath.Abs(fpath); abs == outabs {
+			// the pak is being written inside dir
+			return nil
+		}
+		rel, err := filepath.Rel(dir, fpath)
+		if err != nil {
+			return err
+		}
+		if err := w.AddFile(filepath.ToSlash(rel), fpath); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
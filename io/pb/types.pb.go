@@ -452,6 +452,808 @@ func (m *AnimationEvent) GetValue() string {
 	return ""
 }
 
+// WorldSnapshot is a serialized world (save games and quick-saves)
+type WorldSnapshot struct {
+	// version of the snapshot format
+	Version              uint32            `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Entities             []*EntitySnapshot `protobuf:"bytes,2,rep,name=entities,proto3" json:"entities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *WorldSnapshot) Reset()         { *m = WorldSnapshot{} }
+func (m *WorldSnapshot) String() string { return proto.CompactTextString(m) }
+func (*WorldSnapshot) ProtoMessage()    {}
+func (*WorldSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{6}
+}
+
+func (m *WorldSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorldSnapshot.Unmarshal(m, b)
+}
+func (m *WorldSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorldSnapshot.Marshal(b, m, deterministic)
+}
+func (m *WorldSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorldSnapshot.Merge(m, src)
+}
+func (m *WorldSnapshot) XXX_Size() int {
+	return xxx_messageInfo_WorldSnapshot.Size(m)
+}
+func (m *WorldSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorldSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorldSnapshot proto.InternalMessageInfo
+
+func (m *WorldSnapshot) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *WorldSnapshot) GetEntities() []*EntitySnapshot {
+	if m != nil {
+		return m.Entities
+	}
+	return nil
+}
+
+// EntitySnapshot holds the component data of an entity
+type EntitySnapshot struct {
+	// id of the entity when the snapshot was taken (restored entities get
+	// new ids)
+	Id              uint64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Transform       *TransformState       `protobuf:"bytes,2,opt,name=transform,proto3" json:"transform,omitempty"`
+	DrawLayer       *DrawLayerState       `protobuf:"bytes,3,opt,name=draw_layer,json=drawLayer,proto3" json:"draw_layer,omitempty"`
+	Sprite          *SpriteState          `protobuf:"bytes,4,opt,name=sprite,proto3" json:"sprite,omitempty"`
+	SpriteAnimation *SpriteAnimationState `protobuf:"bytes,5,opt,name=sprite_animation,json=spriteAnimation,proto3" json:"sprite_animation,omitempty"`
+	TrTweening      *TrTweeningState      `protobuf:"bytes,6,opt,name=tr_tweening,json=trTweening,proto3" json:"tr_tweening,omitempty"`
+	ParticleEmitter *ParticleEmitterState `protobuf:"bytes,7,opt,name=particle_emitter,json=particleEmitter,proto3" json:"particle_emitter,omitempty"`
+	// custom components (encoded by registered snapshot codecs)
+	Custom               map[string][]byte `protobuf:"bytes,8,rep,name=custom,proto3" json:"custom,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *EntitySnapshot) Reset()         { *m = EntitySnapshot{} }
+func (m *EntitySnapshot) String() string { return proto.CompactTextString(m) }
+func (*EntitySnapshot) ProtoMessage()    {}
+func (*EntitySnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{7}
+}
+
+func (m *EntitySnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EntitySnapshot.Unmarshal(m, b)
+}
+func (m *EntitySnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EntitySnapshot.Marshal(b, m, deterministic)
+}
+func (m *EntitySnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntitySnapshot.Merge(m, src)
+}
+func (m *EntitySnapshot) XXX_Size() int {
+	return xxx_messageInfo_EntitySnapshot.Size(m)
+}
+func (m *EntitySnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntitySnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntitySnapshot proto.InternalMessageInfo
+
+func (m *EntitySnapshot) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *EntitySnapshot) GetTransform() *TransformState {
+	if m != nil {
+		return m.Transform
+	}
+	return nil
+}
+
+func (m *EntitySnapshot) GetDrawLayer() *DrawLayerState {
+	if m != nil {
+		return m.DrawLayer
+	}
+	return nil
+}
+
+func (m *EntitySnapshot) GetSprite() *SpriteState {
+	if m != nil {
+		return m.Sprite
+	}
+	return nil
+}
+
+func (m *EntitySnapshot) GetSpriteAnimation() *SpriteAnimationState {
+	if m != nil {
+		return m.SpriteAnimation
+	}
+	return nil
+}
+
+func (m *EntitySnapshot) GetTrTweening() *TrTweeningState {
+	if m != nil {
+		return m.TrTweening
+	}
+	return nil
+}
+
+func (m *EntitySnapshot) GetParticleEmitter() *ParticleEmitterState {
+	if m != nil {
+		return m.ParticleEmitter
+	}
+	return nil
+}
+
+func (m *EntitySnapshot) GetCustom() map[string][]byte {
+	if m != nil {
+		return m.Custom
+	}
+	return nil
+}
+
+type TransformState struct {
+	// parent entity id (0 = no parent)
+	Parent               uint64   `protobuf:"varint,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	X                    float64  `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    float64  `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	Angle                float64  `protobuf:"fixed64,4,opt,name=angle,proto3" json:"angle,omitempty"`
+	Sx                   float64  `protobuf:"fixed64,5,opt,name=sx,proto3" json:"sx,omitempty"`
+	Sy                   float64  `protobuf:"fixed64,6,opt,name=sy,proto3" json:"sy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransformState) Reset()         { *m = TransformState{} }
+func (m *TransformState) String() string { return proto.CompactTextString(m) }
+func (*TransformState) ProtoMessage()    {}
+func (*TransformState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{8}
+}
+
+func (m *TransformState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransformState.Unmarshal(m, b)
+}
+func (m *TransformState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransformState.Marshal(b, m, deterministic)
+}
+func (m *TransformState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransformState.Merge(m, src)
+}
+func (m *TransformState) XXX_Size() int {
+	return xxx_messageInfo_TransformState.Size(m)
+}
+func (m *TransformState) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransformState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransformState proto.InternalMessageInfo
+
+func (m *TransformState) GetParent() uint64 {
+	if m != nil {
+		return m.Parent
+	}
+	return 0
+}
+
+func (m *TransformState) GetX() float64 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *TransformState) GetY() float64 {
+	if m != nil {
+		return m.Y
+	}
+	return 0
+}
+
+func (m *TransformState) GetAngle() float64 {
+	if m != nil {
+		return m.Angle
+	}
+	return 0
+}
+
+func (m *TransformState) GetSx() float64 {
+	if m != nil {
+		return m.Sx
+	}
+	return 0
+}
+
+func (m *TransformState) GetSy() float64 {
+	if m != nil {
+		return m.Sy
+	}
+	return 0
+}
+
+type DrawLayerState struct {
+	Layer                int64    `protobuf:"varint,1,opt,name=layer,proto3" json:"layer,omitempty"`
+	ZIndex               int64    `protobuf:"varint,2,opt,name=z_index,json=zIndex,proto3" json:"z_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DrawLayerState) Reset()         { *m = DrawLayerState{} }
+func (m *DrawLayerState) String() string { return proto.CompactTextString(m) }
+func (*DrawLayerState) ProtoMessage()    {}
+func (*DrawLayerState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{9}
+}
+
+func (m *DrawLayerState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DrawLayerState.Unmarshal(m, b)
+}
+func (m *DrawLayerState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DrawLayerState.Marshal(b, m, deterministic)
+}
+func (m *DrawLayerState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DrawLayerState.Merge(m, src)
+}
+func (m *DrawLayerState) XXX_Size() int {
+	return xxx_messageInfo_DrawLayerState.Size(m)
+}
+func (m *DrawLayerState) XXX_DiscardUnknown() {
+	xxx_messageInfo_DrawLayerState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DrawLayerState proto.InternalMessageInfo
+
+func (m *DrawLayerState) GetLayer() int64 {
+	if m != nil {
+		return m.Layer
+	}
+	return 0
+}
+
+func (m *DrawLayerState) GetZIndex() int64 {
+	if m != nil {
+		return m.ZIndex
+	}
+	return 0
+}
+
+// ImageRef references a frame of an atlas
+type ImageRef struct {
+	Atlas                string   `protobuf:"bytes,1,opt,name=atlas,proto3" json:"atlas,omitempty"`
+	Frame                string   `protobuf:"bytes,2,opt,name=frame,proto3" json:"frame,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImageRef) Reset()         { *m = ImageRef{} }
+func (m *ImageRef) String() string { return proto.CompactTextString(m) }
+func (*ImageRef) ProtoMessage()    {}
+func (*ImageRef) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{10}
+}
+
+func (m *ImageRef) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageRef.Unmarshal(m, b)
+}
+func (m *ImageRef) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageRef.Marshal(b, m, deterministic)
+}
+func (m *ImageRef) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageRef.Merge(m, src)
+}
+func (m *ImageRef) XXX_Size() int {
+	return xxx_messageInfo_ImageRef.Size(m)
+}
+func (m *ImageRef) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageRef.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageRef proto.InternalMessageInfo
+
+func (m *ImageRef) GetAtlas() string {
+	if m != nil {
+		return m.Atlas
+	}
+	return ""
+}
+
+func (m *ImageRef) GetFrame() string {
+	if m != nil {
+		return m.Frame
+	}
+	return ""
+}
+
+type SpriteState struct {
+	Image                *ImageRef `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	OriginX              float64   `protobuf:"fixed64,2,opt,name=origin_x,json=originX,proto3" json:"origin_x,omitempty"`
+	OriginY              float64   `protobuf:"fixed64,3,opt,name=origin_y,json=originY,proto3" json:"origin_y,omitempty"`
+	OffsetX              float64   `protobuf:"fixed64,4,opt,name=offset_x,json=offsetX,proto3" json:"offset_x,omitempty"`
+	OffsetY              float64   `protobuf:"fixed64,5,opt,name=offset_y,json=offsetY,proto3" json:"offset_y,omitempty"`
+	Disabled             bool      `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SpriteState) Reset()         { *m = SpriteState{} }
+func (m *SpriteState) String() string { return proto.CompactTextString(m) }
+func (*SpriteState) ProtoMessage()    {}
+func (*SpriteState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{11}
+}
+
+func (m *SpriteState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SpriteState.Unmarshal(m, b)
+}
+func (m *SpriteState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SpriteState.Marshal(b, m, deterministic)
+}
+func (m *SpriteState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpriteState.Merge(m, src)
+}
+func (m *SpriteState) XXX_Size() int {
+	return xxx_messageInfo_SpriteState.Size(m)
+}
+func (m *SpriteState) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpriteState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpriteState proto.InternalMessageInfo
+
+func (m *SpriteState) GetImage() *ImageRef {
+	if m != nil {
+		return m.Image
+	}
+	return nil
+}
+
+func (m *SpriteState) GetOriginX() float64 {
+	if m != nil {
+		return m.OriginX
+	}
+	return 0
+}
+
+func (m *SpriteState) GetOriginY() float64 {
+	if m != nil {
+		return m.OriginY
+	}
+	return 0
+}
+
+func (m *SpriteState) GetOffsetX() float64 {
+	if m != nil {
+		return m.OffsetX
+	}
+	return 0
+}
+
+func (m *SpriteState) GetOffsetY() float64 {
+	if m != nil {
+		return m.OffsetY
+	}
+	return 0
+}
+
+func (m *SpriteState) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+type SpriteAnimationState struct {
+	Atlas                string   `protobuf:"bytes,1,opt,name=atlas,proto3" json:"atlas,omitempty"`
+	Animation            string   `protobuf:"bytes,2,opt,name=animation,proto3" json:"animation,omitempty"`
+	Fps                  float64  `protobuf:"fixed64,3,opt,name=fps,proto3" json:"fps,omitempty"`
+	Playing              bool     `protobuf:"varint,4,opt,name=playing,proto3" json:"playing,omitempty"`
+	Clip                 string   `protobuf:"bytes,5,opt,name=clip,proto3" json:"clip,omitempty"`
+	Frame                int32    `protobuf:"varint,6,opt,name=frame,proto3" json:"frame,omitempty"`
+	T                    float64  `protobuf:"fixed64,7,opt,name=t,proto3" json:"t,omitempty"`
+	Reversed             bool     `protobuf:"varint,8,opt,name=reversed,proto3" json:"reversed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SpriteAnimationState) Reset()         { *m = SpriteAnimationState{} }
+func (m *SpriteAnimationState) String() string { return proto.CompactTextString(m) }
+func (*SpriteAnimationState) ProtoMessage()    {}
+func (*SpriteAnimationState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{12}
+}
+
+func (m *SpriteAnimationState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SpriteAnimationState.Unmarshal(m, b)
+}
+func (m *SpriteAnimationState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SpriteAnimationState.Marshal(b, m, deterministic)
+}
+func (m *SpriteAnimationState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpriteAnimationState.Merge(m, src)
+}
+func (m *SpriteAnimationState) XXX_Size() int {
+	return xxx_messageInfo_SpriteAnimationState.Size(m)
+}
+func (m *SpriteAnimationState) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpriteAnimationState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpriteAnimationState proto.InternalMessageInfo
+
+func (m *SpriteAnimationState) GetAtlas() string {
+	if m != nil {
+		return m.Atlas
+	}
+	return ""
+}
+
+func (m *SpriteAnimationState) GetAnimation() string {
+	if m != nil {
+		return m.Animation
+	}
+	return ""
+}
+
+func (m *SpriteAnimationState) GetFps() float64 {
+	if m != nil {
+		return m.Fps
+	}
+	return 0
+}
+
+func (m *SpriteAnimationState) GetPlaying() bool {
+	if m != nil {
+		return m.Playing
+	}
+	return false
+}
+
+func (m *SpriteAnimationState) GetClip() string {
+	if m != nil {
+		return m.Clip
+	}
+	return ""
+}
+
+func (m *SpriteAnimationState) GetFrame() int32 {
+	if m != nil {
+		return m.Frame
+	}
+	return 0
+}
+
+func (m *SpriteAnimationState) GetT() float64 {
+	if m != nil {
+		return m.T
+	}
+	return 0
+}
+
+func (m *SpriteAnimationState) GetReversed() bool {
+	if m != nil {
+		return m.Reversed
+	}
+	return false
+}
+
+type TrTweenState struct {
+	Name     string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type     int32   `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	From     float64 `protobuf:"fixed64,3,opt,name=from,proto3" json:"from,omitempty"`
+	To       float64 `protobuf:"fixed64,4,opt,name=to,proto3" json:"to,omitempty"`
+	Duration float64 `protobuf:"fixed64,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	// easing function name (see easing.Name)
+	Easing               string   `protobuf:"bytes,6,opt,name=easing,proto3" json:"easing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrTweenState) Reset()         { *m = TrTweenState{} }
+func (m *TrTweenState) String() string { return proto.CompactTextString(m) }
+func (*TrTweenState) ProtoMessage()    {}
+func (*TrTweenState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{13}
+}
+
+func (m *TrTweenState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrTweenState.Unmarshal(m, b)
+}
+func (m *TrTweenState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrTweenState.Marshal(b, m, deterministic)
+}
+func (m *TrTweenState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrTweenState.Merge(m, src)
+}
+func (m *TrTweenState) XXX_Size() int {
+	return xxx_messageInfo_TrTweenState.Size(m)
+}
+func (m *TrTweenState) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrTweenState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrTweenState proto.InternalMessageInfo
+
+func (m *TrTweenState) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TrTweenState) GetType() int32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *TrTweenState) GetFrom() float64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *TrTweenState) GetTo() float64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *TrTweenState) GetDuration() float64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *TrTweenState) GetEasing() string {
+	if m != nil {
+		return m.Easing
+	}
+	return ""
+}
+
+type TrTweeningState struct {
+	Tweens               []*TrTweenState `protobuf:"bytes,1,rep,name=tweens,proto3" json:"tweens,omitempty"`
+	Disabled             bool            `protobuf:"varint,2,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	Playing              bool            `protobuf:"varint,3,opt,name=playing,proto3" json:"playing,omitempty"`
+	Active               *TrTweenState   `protobuf:"bytes,4,opt,name=active,proto3" json:"active,omitempty"`
+	T                    float64         `protobuf:"fixed64,5,opt,name=t,proto3" json:"t,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *TrTweeningState) Reset()         { *m = TrTweeningState{} }
+func (m *TrTweeningState) String() string { return proto.CompactTextString(m) }
+func (*TrTweeningState) ProtoMessage()    {}
+func (*TrTweeningState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{14}
+}
+
+func (m *TrTweeningState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrTweeningState.Unmarshal(m, b)
+}
+func (m *TrTweeningState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrTweeningState.Marshal(b, m, deterministic)
+}
+func (m *TrTweeningState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrTweeningState.Merge(m, src)
+}
+func (m *TrTweeningState) XXX_Size() int {
+	return xxx_messageInfo_TrTweeningState.Size(m)
+}
+func (m *TrTweeningState) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrTweeningState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrTweeningState proto.InternalMessageInfo
+
+func (m *TrTweeningState) GetTweens() []*TrTweenState {
+	if m != nil {
+		return m.Tweens
+	}
+	return nil
+}
+
+func (m *TrTweeningState) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *TrTweeningState) GetPlaying() bool {
+	if m != nil {
+		return m.Playing
+	}
+	return false
+}
+
+func (m *TrTweeningState) GetActive() *TrTweenState {
+	if m != nil {
+		return m.Active
+	}
+	return nil
+}
+
+func (m *TrTweeningState) GetT() float64 {
+	if m != nil {
+		return m.T
+	}
+	return 0
+}
+
+type ParticleEmitterState struct {
+	Max             int32  `protobuf:"varint,1,opt,name=max,proto3" json:"max,omitempty"`
+	Strategy        int32  `protobuf:"varint,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Disabled        bool   `protobuf:"varint,3,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	CompositeMode   int32  `protobuf:"varint,4,opt,name=composite_mode,json=compositeMode,proto3" json:"composite_mode,omitempty"`
+	ParentLevel     uint32 `protobuf:"varint,5,opt,name=parent_level,json=parentLevel,proto3" json:"parent_level,omitempty"`
+	LockedParticles bool   `protobuf:"varint,6,opt,name=locked_particles,json=lockedParticles,proto3" json:"locked_particles,omitempty"`
+	// float64 fields of ParticleProps (by field name)
+	Props map[string]float64 `protobuf:"bytes,7,rep,name=props,proto3" json:"props,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// RGBA
+	InitColor uint32 `protobuf:"fixed32,8,opt,name=init_color,json=initColor,proto3" json:"init_color,omitempty"`
+	// RGBA
+	EndColor             uint32      `protobuf:"fixed32,9,opt,name=end_color,json=endColor,proto3" json:"end_color,omitempty"`
+	Source               []*ImageRef `protobuf:"bytes,10,rep,name=source,proto3" json:"source,omitempty"`
+	EmissionEnabled      bool        `protobuf:"varint,11,opt,name=emission_enabled,json=emissionEnabled,proto3" json:"emission_enabled,omitempty"`
+	EmissionT0           float64     `protobuf:"fixed64,12,opt,name=emission_t0,json=emissionT0,proto3" json:"emission_t0,omitempty"`
+	EmissionT1           float64     `protobuf:"fixed64,13,opt,name=emission_t1,json=emissionT1,proto3" json:"emission_t1,omitempty"`
+	EmissionN0           int32       `protobuf:"varint,14,opt,name=emission_n0,json=emissionN0,proto3" json:"emission_n0,omitempty"`
+	EmissionN1           int32       `protobuf:"varint,15,opt,name=emission_n1,json=emissionN1,proto3" json:"emission_n1,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ParticleEmitterState) Reset()         { *m = ParticleEmitterState{} }
+func (m *ParticleEmitterState) String() string { return proto.CompactTextString(m) }
+func (*ParticleEmitterState) ProtoMessage()    {}
+func (*ParticleEmitterState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{15}
+}
+
+func (m *ParticleEmitterState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParticleEmitterState.Unmarshal(m, b)
+}
+func (m *ParticleEmitterState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParticleEmitterState.Marshal(b, m, deterministic)
+}
+func (m *ParticleEmitterState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParticleEmitterState.Merge(m, src)
+}
+func (m *ParticleEmitterState) XXX_Size() int {
+	return xxx_messageInfo_ParticleEmitterState.Size(m)
+}
+func (m *ParticleEmitterState) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParticleEmitterState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParticleEmitterState proto.InternalMessageInfo
+
+func (m *ParticleEmitterState) GetMax() int32 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetStrategy() int32 {
+	if m != nil {
+		return m.Strategy
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *ParticleEmitterState) GetCompositeMode() int32 {
+	if m != nil {
+		return m.CompositeMode
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetParentLevel() uint32 {
+	if m != nil {
+		return m.ParentLevel
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetLockedParticles() bool {
+	if m != nil {
+		return m.LockedParticles
+	}
+	return false
+}
+
+func (m *ParticleEmitterState) GetProps() map[string]float64 {
+	if m != nil {
+		return m.Props
+	}
+	return nil
+}
+
+func (m *ParticleEmitterState) GetInitColor() uint32 {
+	if m != nil {
+		return m.InitColor
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetEndColor() uint32 {
+	if m != nil {
+		return m.EndColor
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetSource() []*ImageRef {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (m *ParticleEmitterState) GetEmissionEnabled() bool {
+	if m != nil {
+		return m.EmissionEnabled
+	}
+	return false
+}
+
+func (m *ParticleEmitterState) GetEmissionT0() float64 {
+	if m != nil {
+		return m.EmissionT0
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetEmissionT1() float64 {
+	if m != nil {
+		return m.EmissionT1
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetEmissionN0() int32 {
+	if m != nil {
+		return m.EmissionN0
+	}
+	return 0
+}
+
+func (m *ParticleEmitterState) GetEmissionN1() int32 {
+	if m != nil {
+		return m.EmissionN1
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("pb.ImageFilter", ImageFilter_name, ImageFilter_value)
 	proto.RegisterEnum("pb.AnimationClipMode", AnimationClipMode_name, AnimationClipMode_value)
@@ -464,46 +1266,110 @@ func init() {
 	proto.RegisterType((*AnimationClip)(nil), "pb.AnimationClip")
 	proto.RegisterType((*AnimFrame)(nil), "pb.AnimFrame")
 	proto.RegisterType((*AnimationEvent)(nil), "pb.AnimationEvent")
+	proto.RegisterType((*WorldSnapshot)(nil), "pb.WorldSnapshot")
+	proto.RegisterType((*EntitySnapshot)(nil), "pb.EntitySnapshot")
+	proto.RegisterMapType((map[string][]byte)(nil), "pb.EntitySnapshot.CustomEntry")
+	proto.RegisterType((*TransformState)(nil), "pb.TransformState")
+	proto.RegisterType((*DrawLayerState)(nil), "pb.DrawLayerState")
+	proto.RegisterType((*ImageRef)(nil), "pb.ImageRef")
+	proto.RegisterType((*SpriteState)(nil), "pb.SpriteState")
+	proto.RegisterType((*SpriteAnimationState)(nil), "pb.SpriteAnimationState")
+	proto.RegisterType((*TrTweenState)(nil), "pb.TrTweenState")
+	proto.RegisterType((*TrTweeningState)(nil), "pb.TrTweeningState")
+	proto.RegisterType((*ParticleEmitterState)(nil), "pb.ParticleEmitterState")
+	proto.RegisterMapType((map[string]float64)(nil), "pb.ParticleEmitterState.PropsEntry")
 }
 
 func init() { proto.RegisterFile("types.proto", fileDescriptor_d938547f84707355) }
 
 var fileDescriptor_d938547f84707355 = []byte{
-	// 573 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x51, 0x6f, 0xda, 0x30,
-	0x10, 0xae, 0x1d, 0x42, 0x9b, 0x4b, 0x43, 0x53, 0xab, 0x9b, 0xbc, 0x4a, 0xd5, 0xa2, 0x4c, 0xd3,
-	0xb2, 0x3e, 0x20, 0x2d, 0xbc, 0x4c, 0x95, 0xf6, 0x80, 0x68, 0xe8, 0xd0, 0x28, 0x20, 0x8f, 0xed,
-	0x15, 0x85, 0x62, 0xd6, 0x68, 0x90, 0x20, 0x92, 0xb6, 0xe4, 0x07, 0xee, 0x07, 0xec, 0x1f, 0x4d,
-	0xb6, 0x03, 0x4b, 0x56, 0xf6, 0x76, 0x9f, 0xbf, 0xfb, 0x3e, 0x9f, 0xef, 0x2e, 0x01, 0x33, 0xcb,
-	0x57, 0x3c, 0x6d, 0xae, 0xd6, 0x49, 0x96, 0x10, 0xbc, 0x9a, 0xba, 0xbf, 0x35, 0x30, 0xda, 0xd9,
-	0x22, 0x4c, 0xbb, 0xd1, 0x82, 0x93, 0x97, 0x50, 0x8f, 0x96, 0xe1, 0x0f, 0x9e, 0x52, 0xe4, 0x68,
-	0xde, 0x31, 0x2b, 0x10, 0x79, 0x0f, 0x87, 0xf3, 0x68, 0x91, 0xf1, 0x75, 0x4a, 0xb1, 0xa3, 0x79,
-	0x0d, 0xff, 0xa4, 0xb9, 0x9a, 0x36, 0x7b, 0x82, 0xec, 0xca, 0x73, 0xb6, 0xe5, 0xc9, 0x07, 0xa8,
-	0xcf, 0xd7, 0xe1, 0x92, 0xa7, 0x54, 0x73, 0x34, 0xcf, 0xf4, 0x5f, 0x89, 0xcc, 0xdd, 0x0d, 0xcd,
-	0xae, 0xe4, 0x82, 0x38, 0x5b, 0xe7, 0xac, 0x48, 0x24, 0x4d, 0xd0, 0xef, 0x16, 0xd1, 0x2a, 0xa5,
-	0x35, 0xa9, 0xa0, 0x55, 0x45, 0x47, 0x50, 0x4a, 0xa0, 0xd2, 0xc8, 0x27, 0x80, 0x30, 0x8e, 0x96,
-	0x61, 0x16, 0x25, 0x71, 0x4a, 0x75, 0x29, 0xba, 0xa8, 0x8a, 0xda, 0x3b, 0x5e, 0x29, 0x4b, 0x82,
-	0xf3, 0x6b, 0x30, 0x4b, 0x55, 0x10, 0x1b, 0xb4, 0x9f, 0x3c, 0xa7, 0xc8, 0x41, 0x9e, 0xc1, 0x44,
-	0x48, 0x5e, 0x83, 0xfe, 0x18, 0x2e, 0x1e, 0x38, 0xc5, 0x0e, 0xf2, 0x4c, 0xdf, 0x10, 0xd6, 0x52,
-	0xc1, 0xd4, 0xf9, 0x15, 0xfe, 0x88, 0xce, 0xbf, 0x00, 0xfc, 0xad, 0x6c, 0x8f, 0xc9, 0xbb, 0xaa,
-	0xc9, 0xa9, 0xac, 0x6f, 0x5b, 0x84, 0x50, 0x96, 0xcd, 0xfa, 0x70, 0xf2, 0x4f, 0xc5, 0x7b, 0x1c,
-	0xdf, 0x54, 0x1d, 0xad, 0x8a, 0x63, 0xc9, 0xcd, 0x7d, 0x00, 0x5d, 0x96, 0x4b, 0xce, 0x40, 0x97,
-	0x03, 0x94, 0x2e, 0x16, 0x53, 0x80, 0x1c, 0x03, 0xda, 0x48, 0x0f, 0x8b, 0xa1, 0x8d, 0x40, 0x39,
-	0xd5, 0x14, 0xca, 0x05, 0x7a, 0xa2, 0x35, 0x85, 0x9e, 0x04, 0xba, 0xa7, 0xba, 0x42, 0xf7, 0xa4,
-	0x01, 0x38, 0xd9, 0xd0, 0xba, 0x83, 0x3c, 0x9d, 0xe1, 0x64, 0x23, 0x71, 0x4e, 0x0f, 0x0b, 0x9c,
-	0xbb, 0x9f, 0xc1, 0xd8, 0x95, 0x43, 0x08, 0xd4, 0xe2, 0x70, 0xc9, 0x8b, 0xfa, 0x65, 0x2c, 0x5a,
-	0xa2, 0xe6, 0x8c, 0xe5, 0xc8, 0xf6, 0xb5, 0x44, 0xf2, 0xee, 0x2f, 0x04, 0x56, 0x85, 0xd8, 0x6b,
-	0x67, 0x83, 0x36, 0x97, 0x66, 0xc8, 0xc3, 0x4c, 0x84, 0xc4, 0x07, 0x43, 0x18, 0x4c, 0x96, 0xc9,
-	0x8c, 0xcb, 0x37, 0x35, 0xfc, 0x17, 0xcf, 0x2e, 0xb9, 0x4d, 0x66, 0x9c, 0x1d, 0xdd, 0x15, 0x11,
-	0x79, 0xbb, 0xdb, 0x57, 0xb5, 0x7d, 0xbb, 0xb6, 0xaa, 0x89, 0x6f, 0x77, 0xb4, 0x05, 0x26, 0x8f,
-	0x67, 0x7c, 0x36, 0xe1, 0x8f, 0x3c, 0xce, 0x64, 0x53, 0x4c, 0x9f, 0x54, 0xcc, 0x03, 0xc1, 0x30,
-	0x90, 0x69, 0x32, 0x76, 0xc7, 0xaa, 0x23, 0x6a, 0x18, 0x17, 0x00, 0xd2, 0x6b, 0x52, 0x7a, 0x88,
-	0x21, 0x4f, 0x06, 0x82, 0xf6, 0x40, 0x57, 0xd6, 0xf8, 0xbf, 0xd6, 0x2a, 0xc1, 0xbd, 0x82, 0x46,
-	0x95, 0xd8, 0xdb, 0x9d, 0xb3, 0xf2, 0xb6, 0x18, 0xc5, 0x7a, 0x5c, 0xb6, 0xc0, 0x2c, 0x7d, 0xb5,
-	0xc4, 0x84, 0xc3, 0xeb, 0xa0, 0xdb, 0xfe, 0xd6, 0x1f, 0xdb, 0x07, 0x02, 0x0c, 0x82, 0x36, 0x0b,
-	0xbe, 0x8e, 0x6d, 0x44, 0x00, 0xea, 0xfd, 0x9e, 0x80, 0x36, 0xbe, 0xec, 0xc1, 0xe9, 0xb3, 0x0e,
-	0x92, 0x23, 0xa8, 0x0d, 0x07, 0x9d, 0xc0, 0x3e, 0x10, 0x51, 0x7f, 0x38, 0x1c, 0xd9, 0x88, 0x58,
-	0x60, 0x8c, 0x7a, 0x83, 0x9b, 0xc9, 0x68, 0x38, 0xb8, 0xb1, 0x31, 0x39, 0x05, 0xab, 0xd3, 0x6f,
-	0xdf, 0x8e, 0x26, 0xdd, 0x21, 0x0b, 0xbe, 0x07, 0xcc, 0xae, 0x4d, 0xeb, 0xf2, 0xcf, 0xd3, 0xfa,
-	0x13, 0x00, 0x00, 0xff, 0xff, 0xbb, 0x47, 0xf5, 0x1b, 0x88, 0x04, 0x00, 0x00,
+	// 1393 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x85, 0x57, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x2e, 0xf5, 0xaf, 0xa1, 0x25, 0xcb, 0x5b, 0xb7, 0x65, 0xdc, 0xa6, 0x3f, 0x4c, 0x83, 0xba,
+	0x39, 0x08, 0xb6, 0x52, 0x04, 0x49, 0x80, 0xa2, 0x30, 0x1c, 0x39, 0x35, 0xaa, 0xd8, 0xc6, 0xda,
+	0x6d, 0x93, 0x13, 0x41, 0x4b, 0x2b, 0x87, 0x08, 0x45, 0x0a, 0xe4, 0xfa, 0x47, 0x79, 0x88, 0xbe,
+	0x47, 0x1f, 0xa1, 0x0f, 0xd0, 0x4b, 0x6f, 0x3d, 0xf7, 0xd4, 0x37, 0xe9, 0xec, 0xec, 0x92, 0x22,
+	0x1d, 0x05, 0xbd, 0xed, 0x37, 0x7f, 0x9c, 0x9d, 0x99, 0xfd, 0x76, 0x09, 0xb6, 0x5c, 0xcc, 0x45,
+	0xda, 0x9f, 0x27, 0xb1, 0x8c, 0x59, 0x65, 0x7e, 0xee, 0xfe, 0x5d, 0x85, 0xf6, 0x9e, 0x0c, 0xfd,
+	0xf4, 0x20, 0x08, 0x05, 0xfb, 0x18, 0x1a, 0xc1, 0xcc, 0xbf, 0x10, 0xa9, 0x63, 0x7d, 0x59, 0xdd,
+	0x5e, 0xe3, 0x06, 0xb1, 0x6f, 0xa1, 0x39, 0x0d, 0x42, 0x29, 0x92, 0xd4, 0xa9, 0xa0, 0xa2, 0x3b,
+	0x58, 0xef, 0xcf, 0xcf, 0xfb, 0x87, 0x4a, 0x79, 0x40, 0x72, 0x9e, 0xe9, 0xd9, 0x2e, 0x34, 0xa6,
+	0x89, 0x3f, 0xc3, 0x10, 0x55, 0xb4, 0xb4, 0x07, 0x77, 0x94, 0x65, 0xfe, 0x85, 0xfe, 0x01, 0xe9,
+	0x86, 0x91, 0x4c, 0x16, 0xdc, 0x18, 0xb2, 0x3e, 0xd4, 0xc7, 0x61, 0x30, 0x4f, 0x9d, 0x1a, 0x79,
+	0x38, 0x65, 0x8f, 0x7d, 0xa5, 0xd2, 0x0e, 0xda, 0x8c, 0x7d, 0x0f, 0xe0, 0x47, 0x98, 0x99, 0x0c,
+	0xe2, 0x28, 0x75, 0xea, 0xe4, 0x74, 0xb7, 0xec, 0xb4, 0x97, 0xeb, 0xb5, 0x67, 0xc1, 0x61, 0xeb,
+	0x19, 0xd8, 0x85, 0x2c, 0x58, 0x0f, 0xaa, 0x6f, 0xc4, 0x02, 0x37, 0x6c, 0x6d, 0xb7, 0xb9, 0x5a,
+	0xb2, 0x2f, 0xa0, 0x7e, 0xe5, 0x87, 0x97, 0x02, 0xf7, 0x6a, 0x61, 0xe8, 0xb6, 0x0a, 0x4d, 0x1e,
+	0x5c, 0xcb, 0x9f, 0x56, 0x1e, 0x5b, 0x5b, 0x3f, 0x01, 0x2c, 0x33, 0x5b, 0x11, 0xe4, 0x9b, 0x72,
+	0x90, 0x0d, 0xca, 0x2f, 0x4b, 0x42, 0x79, 0x16, 0x83, 0x8d, 0x60, 0xfd, 0x56, 0xc6, 0x2b, 0x22,
+	0xde, 0x2b, 0x47, 0xec, 0x94, 0x22, 0x16, 0xa2, 0xb9, 0x97, 0x50, 0xa7, 0x74, 0xd9, 0x26, 0xd4,
+	0xa9, 0x81, 0x14, 0xa5, 0xc3, 0x35, 0x60, 0x6b, 0x60, 0xdd, 0x50, 0x8c, 0x0e, 0xb7, 0x6e, 0x14,
+	0x5a, 0x60, 0xab, 0x08, 0x2d, 0x14, 0xba, 0xc6, 0x36, 0x10, 0xba, 0x56, 0xe8, 0x35, 0xd6, 0x97,
+	0xd0, 0x6b, 0xd6, 0x85, 0x4a, 0x7c, 0xe3, 0x34, 0x10, 0xd6, 0x39, 0xae, 0x08, 0x2f, 0x9c, 0xa6,
+	0xc1, 0x0b, 0xf7, 0x47, 0x9c, 0xa4, 0x2c, 0x1d, 0xc6, 0xa0, 0x16, 0x61, 0x0a, 0x26, 0x7f, 0x5a,
+	0xab, 0x92, 0xe8, 0x3e, 0x57, 0xa8, 0x65, 0xab, 0x4a, 0x42, 0x7a, 0xf7, 0x4f, 0x0b, 0x3a, 0x25,
+	0xc5, 0xca, 0x70, 0x58, 0xa1, 0x29, 0x05, 0xb3, 0xb6, 0x2b, 0x5c, 0x2d, 0xd9, 0x00, 0xda, 0x2a,
+	0x80, 0x37, 0x8b, 0x27, 0x82, 0xf6, 0xd4, 0x1d, 0x7c, 0xf4, 0xce, 0x47, 0x5e, 0xa0, 0x92, 0xb7,
+	0xc6, 0x66, 0xc5, 0xee, 0xe7, 0xf3, 0xaa, 0xa7, 0x2f, 0x2f, 0xab, 0xee, 0x78, 0x36, 0xa3, 0x0f,
+	0xc1, 0x16, 0xd1, 0x44, 0x4c, 0x3c, 0x71, 0x25, 0x22, 0x49, 0x45, 0xb1, 0x07, 0xac, 0x14, 0x7c,
+	0xa8, 0x34, 0x1c, 0xc8, 0x8c, 0xd6, 0xee, 0x99, 0xae, 0x88, 0x6e, 0xc6, 0x5d, 0x00, 0x8a, 0xe5,
+	0x15, 0x36, 0xd2, 0x26, 0xc9, 0x91, 0x52, 0x6f, 0x43, 0x5d, 0x87, 0xae, 0xbc, 0x37, 0xb4, 0x36,
+	0x70, 0x9f, 0x42, 0xb7, 0xac, 0x58, 0x59, 0x9d, 0xcd, 0xe2, 0xb4, 0xb4, 0xcd, 0x78, 0xb8, 0xaf,
+	0xa0, 0xf3, 0x6b, 0x9c, 0x84, 0x93, 0xd3, 0xc8, 0x9f, 0xa7, 0xaf, 0x63, 0xc9, 0x1c, 0x68, 0x5e,
+	0xe1, 0xb1, 0xc5, 0x50, 0x66, 0x48, 0x32, 0x88, 0xa7, 0xb2, 0x85, 0xb1, 0x03, 0x19, 0x88, 0xac,
+	0x61, 0x94, 0xd3, 0x50, 0xc9, 0x16, 0x99, 0x3f, 0xcf, 0x6d, 0xdc, 0x7f, 0xab, 0xd0, 0x2d, 0x2b,
+	0xd5, 0x84, 0x04, 0x13, 0x8a, 0x5b, 0xe3, 0xb8, 0x62, 0x3b, 0xd0, 0x96, 0x89, 0x1f, 0xa5, 0xd3,
+	0x38, 0x99, 0x15, 0xf7, 0x79, 0x96, 0x09, 0x4f, 0xa5, 0x2f, 0x05, 0x5f, 0x1a, 0x21, 0x9b, 0xc0,
+	0x24, 0xf1, 0xaf, 0xbd, 0xd0, 0x5f, 0x88, 0x84, 0x5a, 0x6a, 0x5c, 0x9e, 0xa1, 0x74, 0xa4, 0x84,
+	0xc6, 0x65, 0x92, 0x61, 0x9c, 0xb2, 0x46, 0x3a, 0x4f, 0x02, 0x29, 0x68, 0x8e, 0x6d, 0x4d, 0x55,
+	0xa7, 0x24, 0xd1, 0xb6, 0x46, 0xcd, 0xf6, 0xa1, 0xa7, 0x57, 0x5e, 0x4e, 0x0e, 0xa6, 0xaf, 0xce,
+	0xd2, 0x25, 0xaf, 0xb4, 0xf6, 0x5d, 0x4f, 0xcb, 0x52, 0xf6, 0x1d, 0xd8, 0x32, 0xf1, 0xe4, 0xb5,
+	0x10, 0x51, 0x10, 0x5d, 0xd0, 0xe9, 0xb0, 0x07, 0x1f, 0xea, 0x4d, 0x9d, 0x19, 0xa9, 0x76, 0x05,
+	0x99, 0x0b, 0xd4, 0xa7, 0xe7, 0x7e, 0x22, 0x83, 0x71, 0x28, 0x3c, 0x31, 0x0b, 0x24, 0x32, 0x27,
+	0x1d, 0x24, 0xf3, 0xe9, 0x13, 0xa3, 0x1b, 0x6a, 0x95, 0xf9, 0xf4, 0xbc, 0x2c, 0x65, 0x8f, 0xa0,
+	0x31, 0xbe, 0x4c, 0x65, 0x3c, 0x73, 0x5a, 0xd4, 0x9e, 0xcf, 0xdf, 0x6d, 0x4f, 0x7f, 0x9f, 0x0c,
+	0x0c, 0xdd, 0x6a, 0xeb, 0xad, 0x27, 0x60, 0x17, 0xc4, 0x2b, 0x88, 0xa6, 0x34, 0x3a, 0x6b, 0x45,
+	0x66, 0x79, 0x0b, 0xdd, 0x72, 0xaf, 0xd4, 0x8d, 0x81, 0x79, 0xa9, 0xb9, 0xd5, 0x6d, 0x36, 0x68,
+	0x49, 0x32, 0x56, 0x89, 0x64, 0x2c, 0x45, 0x32, 0x18, 0xdf, 0x8f, 0x2e, 0x42, 0xdd, 0x20, 0x8b,
+	0x6b, 0xa0, 0x86, 0x25, 0xbd, 0xa1, 0x06, 0x58, 0x1c, 0x57, 0x84, 0x17, 0x54, 0x50, 0x85, 0x17,
+	0xee, 0x0f, 0xd0, 0x2d, 0x37, 0x5d, 0xc5, 0xd1, 0x73, 0xa1, 0x3e, 0x5d, 0xe5, 0x1a, 0xb0, 0x4f,
+	0xa0, 0xf9, 0xd6, 0x0b, 0xf0, 0x10, 0xea, 0xef, 0x57, 0x79, 0xe3, 0xed, 0xa1, 0x42, 0xee, 0x23,
+	0x68, 0xd1, 0x8d, 0xc5, 0xc5, 0x94, 0x52, 0x50, 0x97, 0x85, 0xd9, 0xb6, 0x06, 0x4a, 0x4a, 0x07,
+	0x32, 0x3b, 0x33, 0x04, 0xdc, 0x3f, 0x2c, 0xb0, 0x0b, 0xf3, 0xc3, 0xdc, 0x22, 0xab, 0xda, 0x83,
+	0xb5, 0xfc, 0x2a, 0xc4, 0xc0, 0x19, 0xc7, 0xde, 0x81, 0x56, 0x9c, 0x04, 0x17, 0x41, 0xe4, 0x65,
+	0x55, 0x68, 0x6a, 0xfc, 0xb2, 0xa0, 0xca, 0x4a, 0x62, 0x54, 0xaf, 0x48, 0x35, 0x9d, 0xa6, 0x42,
+	0xa2, 0x57, 0xcd, 0xa8, 0x08, 0xbf, 0x2c, 0xa8, 0x16, 0xa6, 0x46, 0x46, 0xf5, 0x8a, 0x6d, 0x41,
+	0x6b, 0x12, 0xa4, 0xfe, 0x79, 0x28, 0x26, 0x54, 0xae, 0x16, 0xcf, 0xb1, 0xfb, 0x97, 0x05, 0x9b,
+	0xab, 0x06, 0xf9, 0x3d, 0x05, 0xf8, 0x0c, 0xda, 0xcb, 0xb3, 0xa0, 0x8b, 0xb0, 0x14, 0x64, 0x84,
+	0xab, 0x93, 0x26, 0xc2, 0x45, 0xf6, 0x98, 0x63, 0xd5, 0xd5, 0xe4, 0xd7, 0xe8, 0xcb, 0x19, 0x54,
+	0x94, 0xa4, 0x28, 0x96, 0x72, 0x45, 0x4a, 0x52, 0xeb, 0x65, 0x79, 0xf5, 0x1d, 0xa2, 0x81, 0x9a,
+	0x0d, 0x49, 0xc3, 0x8f, 0xb3, 0x21, 0xd5, 0x66, 0x12, 0xa1, 0x28, 0x08, 0x37, 0xd3, 0xd2, 0x9b,
+	0xc9, 0xb0, 0xfb, 0x9b, 0x05, 0x6b, 0xe6, 0x54, 0xe9, 0x4d, 0xac, 0xe2, 0x3d, 0x94, 0xa9, 0x37,
+	0x0e, 0x65, 0x5f, 0xe7, 0xb4, 0x56, 0xb2, 0x69, 0x82, 0xe7, 0x44, 0x67, 0x4e, 0x6b, 0x35, 0x5e,
+	0x32, 0x36, 0x55, 0xc6, 0x15, 0x55, 0xf1, 0x32, 0x59, 0xb2, 0x80, 0xc5, 0x73, 0xac, 0x86, 0x5c,
+	0xf8, 0x69, 0x76, 0xbe, 0xdb, 0xdc, 0x20, 0xf7, 0x77, 0x0b, 0xd6, 0x6f, 0x1d, 0x73, 0xe4, 0xf1,
+	0x06, 0xb1, 0x81, 0x7e, 0x42, 0xd9, 0x83, 0x5e, 0x81, 0x0b, 0x0c, 0xff, 0x68, 0x7d, 0xa9, 0x6f,
+	0x95, 0x72, 0xdf, 0x8a, 0x85, 0xad, 0x96, 0x0b, 0x8b, 0xf1, 0xfd, 0xb1, 0x0c, 0xae, 0x32, 0x7a,
+	0x5b, 0x11, 0x5f, 0xeb, 0x75, 0x61, 0xeb, 0xa6, 0xb0, 0xee, 0x3f, 0x35, 0xd8, 0x5c, 0xc5, 0x2b,
+	0xaa, 0xab, 0x33, 0xff, 0x86, 0x6a, 0x58, 0xe7, 0x6a, 0xa9, 0x12, 0x4b, 0x91, 0x82, 0xa5, 0xb8,
+	0x58, 0x98, 0x32, 0xe6, 0xb8, 0x94, 0x74, 0xf5, 0x56, 0xd2, 0xf7, 0xa1, 0x3b, 0x8e, 0x67, 0xf3,
+	0x38, 0x55, 0x9c, 0x4a, 0x77, 0x70, 0x8d, 0xbc, 0x3b, 0xb9, 0x94, 0x6e, 0xdc, 0xaf, 0x60, 0x4d,
+	0x93, 0x84, 0x17, 0x62, 0x67, 0x43, 0xf3, 0xc0, 0xb0, 0xb5, 0x6c, 0xa4, 0x44, 0xf8, 0xde, 0xec,
+	0x85, 0xf1, 0xf8, 0x0d, 0x5e, 0xb7, 0x19, 0xe9, 0xa5, 0x66, 0xb4, 0xd7, 0xb5, 0x3c, 0xdb, 0x49,
+	0xca, 0x9e, 0x40, 0x1d, 0x5f, 0xb3, 0x38, 0x96, 0x4d, 0x2a, 0xf7, 0xbd, 0xf7, 0xf1, 0x67, 0xff,
+	0x44, 0x59, 0x99, 0x77, 0x24, 0x79, 0xa8, 0x1b, 0x39, 0x88, 0x02, 0xe9, 0x8d, 0xe3, 0x30, 0x4e,
+	0x68, 0xda, 0x9a, 0xbc, 0xad, 0x24, 0xfb, 0x4a, 0xc0, 0x3e, 0x85, 0x36, 0xde, 0xe5, 0x46, 0xdb,
+	0x26, 0x2d, 0xde, 0x76, 0x13, 0xad, 0xfc, 0x1a, 0x6f, 0x99, 0xf8, 0x32, 0x19, 0x0b, 0x07, 0xe8,
+	0xbb, 0x65, 0x16, 0x30, 0x3a, 0xb5, 0x0f, 0xa4, 0xf7, 0x54, 0xdd, 0xa7, 0x9e, 0x88, 0x74, 0xd5,
+	0x6c, 0xbd, 0x8f, 0x4c, 0x3e, 0xd4, 0x62, 0x7c, 0x74, 0xda, 0xb9, 0xa9, 0xdc, 0x71, 0xd6, 0xa8,
+	0x6f, 0x90, 0x89, 0xce, 0x76, 0xca, 0x06, 0xbb, 0x4e, 0xe7, 0x96, 0xc1, 0x6e, 0xc9, 0x20, 0xda,
+	0x71, 0xba, 0x54, 0xfb, 0xdc, 0xe0, 0xa8, 0x1c, 0x21, 0xda, 0x75, 0xd6, 0x6f, 0x19, 0xec, 0x6e,
+	0x3d, 0x06, 0x58, 0x56, 0xe9, 0xff, 0x2e, 0x06, 0xab, 0x70, 0x31, 0x3c, 0xc0, 0xe7, 0x51, 0xe1,
+	0x6f, 0x80, 0xd9, 0xd0, 0x7c, 0x36, 0x3c, 0xd8, 0xfb, 0x79, 0x74, 0xd6, 0xfb, 0x40, 0x81, 0xa3,
+	0xe1, 0x1e, 0x1f, 0x9e, 0x9e, 0xf5, 0x2c, 0x06, 0xd0, 0x18, 0x1d, 0x2a, 0xd8, 0xab, 0x3c, 0x38,
+	0x84, 0x8d, 0x77, 0x5e, 0x66, 0xac, 0x05, 0xb5, 0xe3, 0xa3, 0xfd, 0x21, 0xfa, 0xe1, 0x6a, 0x74,
+	0x7c, 0x7c, 0x82, 0x4e, 0x1d, 0x68, 0x9f, 0x1c, 0x1e, 0x3d, 0xf7, 0x4e, 0x8e, 0x8f, 0x9e, 0xf7,
+	0x2a, 0x6c, 0x03, 0x3a, 0xfb, 0xa3, 0xbd, 0x17, 0x27, 0xde, 0xc1, 0x31, 0x1f, 0xfe, 0x32, 0xe4,
+	0xbd, 0xda, 0x79, 0x83, 0xfe, 0x68, 0x1e, 0xfe, 0x07, 0xd3, 0xdc, 0x68, 0x8f, 0xe0, 0x0c, 0x00,
+	0x00,
 }
@@ -0,0 +1,327 @@
+package io
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gabstv/primen/dom"
+)
+
+// Prefab component names (the XML tag names of the components)
+const (
+	PrefabTransform       = "transform"
+	PrefabDrawLayer       = "drawlayer"
+	PrefabSprite          = "sprite"
+	PrefabSpriteAnimation = "animation"
+	PrefabParticleEmitter = "particles"
+	PrefabAudioPlayer     = "audio"
+)
+
+var prefabComponents = map[string]bool{
+	PrefabTransform:       true,
+	PrefabDrawLayer:       true,
+	PrefabSprite:          true,
+	PrefabSpriteAnimation: true,
+	PrefabParticleEmitter: true,
+	PrefabAudioPlayer:     true,
+}
+
+// Prefab is a reusable definition of a node tree.
+//
+// Example:
+//
+//	<prefab name="hero" atlas="people.dat">
+//	  <node tags="player">
+//	    <transform x="10" y="20" sx="2" sy="2" />
+//	    <drawlayer layer="1" z="top" />
+//	    <animation anim="boy" fps="12" clip="idle" />
+//	    <node id="shadow">
+//	      <transform y="8" />
+//	      <sprite frame="shadow" ox=".5" oy=".5" />
+//	    </node>
+//	    <node id="weapon" prefab="sword.xml">
+//	      <transform x="4" />
+//	      <override path="blade" sprite.frame="sword_fire" />
+//	    </node>
+//	  </node>
+//	</prefab>
+type Prefab struct {
+	Name string
+	// Atlas is the default atlas file of the sprite frames and animations
+	Atlas string
+	Root  *PrefabNode
+}
+
+// PrefabNode is a node of a prefab
+type PrefabNode struct {
+	// ID is used to build the path of the node (see PrefabOverrides). It's
+	// also the name of the instantiated node.
+	ID string
+	// Tags of the instantiated node (the "tags" attribute, separated by
+	// spaces)
+	Tags []string
+	// Prefab is the file of a nested prefab. If set, the nested prefab root
+	// is created in place of this node, and the components of this node are
+	// applied as overrides to it.
+	Prefab string
+	// Components maps a component name (transform, sprite...) to its
+	// properties
+	Components map[string]dom.Attributes
+	// Overrides of a nested prefab
+	Overrides PrefabOverrides
+	Children  []*PrefabNode
+}
+
+// segment returns the path segment of the node. Nodes without an ID are
+// addressed by their index.
+func (n *PrefabNode) segment(index int) string {
+	if n.ID != "" {
+		return n.ID
+	}
+	return strconv.Itoa(index)
+}
+
+// PrefabOverrides are per-instance property overrides. The key is the path
+// of the node (the node IDs separated by "/", relative to the prefab root,
+// which is ""), and the value maps "component.property" to the new value
+// (e.g. "sprite.frame": "sword_fire").
+type PrefabOverrides map[string]map[string]string
+
+// Set adds an override
+func (o PrefabOverrides) Set(path, property, value string) PrefabOverrides {
+	if o[path] == nil {
+		o[path] = make(map[string]string)
+	}
+	o[path][property] = value
+	return o
+}
+
+// Sub returns the overrides of the subtree at path (with the paths relative
+// to it).
+func (o PrefabOverrides) Sub(path string) PrefabOverrides {
+	out := make(PrefabOverrides)
+	if path == "" {
+		for k, v := range o {
+			out[k] = v
+		}
+		return out
+	}
+	for k, v := range o {
+		switch {
+		case k == path:
+			out[""] = v
+		case strings.HasPrefix(k, path+"/"):
+			out[k[len(path)+1:]] = v
+		}
+	}
+	return out
+}
+
+// Merge returns a copy of o with the values of other (other wins)
+func (o PrefabOverrides) Merge(other PrefabOverrides) PrefabOverrides {
+	out := make(PrefabOverrides)
+	for _, src := range [2]PrefabOverrides{o, other} {
+		for path, props := range src {
+			for k, v := range props {
+				out.Set(path, k, v)
+			}
+		}
+	}
+	return out
+}
+
+// PrefabNodeFn is called for each node of the prefab tree by Walk
+type PrefabNodeFn func(path string, node *PrefabNode) bool
+
+// Walk calls fn for each node (depth first). It stops if fn returns false.
+func (p *Prefab) Walk(fn PrefabNodeFn) {
+	if p.Root == nil {
+		return
+	}
+	walkPrefabNode("", p.Root, fn)
+}
+
+func walkPrefabNode(path string, node *PrefabNode, fn PrefabNodeFn) bool {
+	if !fn(path, node) {
+		return false
+	}
+	for i, child := range node.Children {
+		if !walkPrefabNode(PrefabChildPath(path, child.segment(i)), child, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// PrefabChildPath joins a node path and a child segment
+func PrefabChildPath(parent, segment string) string {
+	if parent == "" {
+		return segment
+	}
+	return parent + "/" + segment
+}
+
+// ChildPath returns the path of the child at index i
+func (n *PrefabNode) ChildPath(path string, i int) string {
+	return PrefabChildPath(path, n.Children[i].segment(i))
+}
+
+// ComponentNames returns the component names of the node, sorted by the
+// order they must be applied.
+func (n *PrefabNode) ComponentNames(overrides map[string]string) []string {
+	set := make(map[string]struct{}, len(n.Components))
+	for k := range n.Components {
+		set[k] = struct{}{}
+	}
+	for k := range overrides {
+		// an override may add a component to the node
+		if cname, _ := SplitPrefabProperty(k); prefabComponents[cname] {
+			set[cname] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(set))
+	for k := range set {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return prefabComponentOrder(names[i]) < prefabComponentOrder(names[j])
+	})
+	return names
+}
+
+// Props returns the properties of a component with the overrides applied
+func (n *PrefabNode) Props(component string, overrides map[string]string) dom.Attributes {
+	out := make(dom.Attributes)
+	for k, v := range n.Components[component] {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		if cname, prop := SplitPrefabProperty(k); cname == component {
+			out[prop] = v
+		}
+	}
+	return out
+}
+
+// SplitPrefabProperty splits "component.property"
+func SplitPrefabProperty(v string) (component, property string) {
+	i := strings.Index(v, ".")
+	if i < 0 {
+		return v, ""
+	}
+	return v[:i], v[i+1:]
+}
+
+func prefabComponentOrder(name string) int {
+	switch name {
+	case PrefabTransform:
+		return 0
+	case PrefabDrawLayer:
+		return 1
+	case PrefabSprite:
+		return 2
+	case PrefabSpriteAnimation:
+		return 3
+	case PrefabParticleEmitter:
+		return 4
+	case PrefabAudioPlayer:
+		return 5
+	}
+	return 6
+}
+
+// ParsePrefab parses a XML prefab definition
+func ParsePrefab(b []byte) (*Prefab, error) {
+	nodes, err := dom.ParseXMLString(string(b))
+	if err != nil {
+		return nil, err
+	}
+	var root dom.ElementNode
+	for _, v := range nodes {
+		if v.Type() == dom.NodeElement && v.(dom.ElementNode).TagName() == "prefab" {
+			root = v.(dom.ElementNode)
+			break
+		}
+	}
+	if root == nil {
+		return nil, errors.New("prefab: <prefab> element not found")
+	}
+	p := &Prefab{
+		Name:  root.Attributes().String("name"),
+		Atlas: root.Attributes().String("atlas"),
+	}
+	for _, v := range root.Children() {
+		if v.Type() != dom.NodeElement {
+			continue
+		}
+		el := v.(dom.ElementNode)
+		if el.TagName() != "node" {
+			return nil, fmt.Errorf("prefab: unexpected <%s> element", el.TagName())
+		}
+		if p.Root != nil {
+			return nil, errors.New("prefab: only one root <node> is allowed")
+		}
+		if p.Root, err = parsePrefabNode(el); err != nil {
+			return nil, err
+		}
+	}
+	if p.Root == nil {
+		return nil, errors.New("prefab: root <node> not found")
+	}
+	return p, nil
+}
+
+func parsePrefabNode(el dom.ElementNode) (*PrefabNode, error) {
+	n := &PrefabNode{
+		ID:         el.Attributes().String("id"),
+		Prefab:     el.Attributes().String("prefab"),
+		Tags:       strings.Fields(el.Attributes().String("tags")),
+		Components: make(map[string]dom.Attributes),
+	}
+	if strings.Contains(n.ID, "/") {
+		return nil, fmt.Errorf("prefab: invalid node id '%s'", n.ID)
+	}
+	for _, v := range el.Children() {
+		if v.Type() != dom.NodeElement {
+			continue
+		}
+		child := v.(dom.ElementNode)
+		tag := child.TagName()
+		switch {
+		case tag == "node":
+			cn, err := parsePrefabNode(child)
+			if err != nil {
+				return nil, err
+			}
+			n.Children = append(n.Children, cn)
+		case tag == "override":
+			if n.Prefab == "" {
+				return nil, errors.New("prefab: <override> is only allowed on nested prefab nodes")
+			}
+			if n.Overrides == nil {
+				n.Overrides = make(PrefabOverrides)
+			}
+			path := child.Attributes().String("path")
+			for k, v := range child.Attributes() {
+				if k != "path" {
+					n.Overrides.Set(path, k, v)
+				}
+			}
+		case prefabComponents[tag]:
+			if _, ok := n.Components[tag]; ok {
+				return nil, fmt.Errorf("prefab: duplicate <%s> in node '%s'", tag, n.ID)
+			}
+			attrs := make(dom.Attributes)
+			for k, v := range child.Attributes() {
+				attrs[k] = v
+			}
+			n.Components[tag] = attrs
+		default:
+			return nil, fmt.Errorf("prefab: unknown component <%s>", tag)
+		}
+	}
+	return n, nil
+}
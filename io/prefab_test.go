@@ -0,0 +1,54 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPrefab = `<prefab name="hero" atlas="people.dat">
+	<node>
+		<transform x="10" y="20" />
+		<animation anim="boy" clip="idle" />
+		<node id="shadow">
+			<sprite frame="shadow" ox=".5" />
+		</node>
+		<node id="weapon" prefab="sword.xml">
+			<transform x="4" />
+			<override path="blade" sprite.frame="sword_fire" />
+		</node>
+	</node>
+</prefab>`
+
+func TestParsePrefab(t *testing.T) {
+	p, err := ParsePrefab([]byte(testPrefab))
+	require.NoError(t, err)
+	assert.Equal(t, "hero", p.Name)
+	assert.Equal(t, "people.dat", p.Atlas)
+	paths := make([]string, 0)
+	p.Walk(func(path string, node *PrefabNode) bool {
+		paths = append(paths, path)
+		return true
+	})
+	assert.Equal(t, []string{"", "shadow", "weapon"}, paths)
+	assert.Equal(t, []string{PrefabTransform, PrefabSpriteAnimation}, p.Root.ComponentNames(nil))
+	weapon := p.Root.Children[1]
+	assert.Equal(t, "sword.xml", weapon.Prefab)
+	assert.Equal(t, "sword_fire", weapon.Overrides["blade"]["sprite.frame"])
+
+	ov := make(PrefabOverrides).Set("shadow", "sprite.oy", "1").Set("shadow", "transform.y", "2")
+	shadow := p.Root.Children[0]
+	assert.Equal(t, []string{PrefabTransform, PrefabSprite}, shadow.ComponentNames(ov["shadow"]))
+	props := shadow.Props(PrefabSprite, ov["shadow"])
+	assert.Equal(t, "shadow", props["frame"])
+	assert.Equal(t, "1", props["oy"])
+
+	sub := make(PrefabOverrides).Set("weapon", "transform.x", "8").Set("weapon/blade", "sprite.frame", "x").Sub("weapon")
+	assert.Equal(t, "8", sub[""]["transform.x"])
+	assert.Equal(t, "x", sub["blade"]["sprite.frame"])
+	assert.Equal(t, "x", weapon.Overrides.Merge(sub)["blade"]["sprite.frame"])
+
+	_, err = ParsePrefab([]byte(`<prefab><node><foo /></node></prefab>`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,481 @@
+package io
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/components/graphics"
+	"github.com/gabstv/primen/easing"
+	"github.com/gabstv/primen/io/pb"
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// WorldSnapshotVersion is the current version of the world snapshot format
+const WorldSnapshotVersion = 1
+
+// SnapshotAssets resolves the atlas frames and animations referenced by the
+// sprites, sprite animations and particle emitters of a world snapshot.
+// Images that don't belong to a registered atlas are not saved.
+type SnapshotAssets struct {
+	atlases map[string]*Atlas
+	images  map[*ebiten.Image]*pb.ImageRef
+	anims   map[graphics.Animation][2]string
+}
+
+// NewSnapshotAssets creates an empty asset registry
+func NewSnapshotAssets() *SnapshotAssets {
+	return &SnapshotAssets{
+		atlases: make(map[string]*Atlas),
+		images:  make(map[*ebiten.Image]*pb.ImageRef),
+		anims:   make(map[graphics.Animation][2]string),
+	}
+}
+
+// AddAtlas registers an atlas by name (usually the file name)
+func (a *SnapshotAssets) AddAtlas(name string, atlas *Atlas) *SnapshotAssets {
+	a.atlases[name] = atlas
+	for k, v := range atlas.frames {
+		a.images[v.Image] = &pb.ImageRef{
+			Atlas: name,
+			Frame: k,
+		}
+	}
+	for k, v := range atlas.anims {
+		a.anims[v] = [2]string{name, k}
+	}
+	return a
+}
+
+// ImageRef returns the atlas frame of an image (or nil)
+func (a *SnapshotAssets) ImageRef(img *ebiten.Image) *pb.ImageRef {
+	if a == nil || img == nil {
+		return nil
+	}
+	return a.images[img]
+}
+
+// Image returns the image of an atlas frame (or nil)
+func (a *SnapshotAssets) Image(ref *pb.ImageRef) *ebiten.Image {
+	if a == nil || ref == nil {
+		return nil
+	}
+	atlas := a.atlases[ref.Atlas]
+	if atlas == nil {
+		return nil
+	}
+	if spr := atlas.GetSubImage(ref.Frame); spr != nil {
+		return spr.Image
+	}
+	return nil
+}
+
+// Animation returns an animation of an atlas (or nil)
+func (a *SnapshotAssets) Animation(atlas, name string) graphics.Animation {
+	if a == nil || a.atlases[atlas] == nil {
+		return nil
+	}
+	if anim, ok := a.atlases[atlas].anims[name]; ok {
+		return anim
+	}
+	return nil
+}
+
+// SnapshotCodec encodes and decodes the data of a custom component
+type SnapshotCodec struct {
+	// Name is the key of the component data (EntitySnapshot.Custom)
+	Name string
+	// Entities returns the entities with the component
+	Entities func(w ecs.BaseWorld) []ecs.Entity
+	Encode   func(w ecs.BaseWorld, e ecs.Entity) ([]byte, error)
+	// Decode restores the component data of an entity. Use remap to get the
+	// restored entity of an entity id stored in the data.
+	Decode func(w ecs.BaseWorld, e ecs.Entity, data []byte, remap func(id uint64) ecs.Entity) error
+}
+
+var snapshotCodecs []SnapshotCodec
+var snapshotCodecsM sync.Mutex
+
+// RegisterSnapshotCodec registers the encode/decode hooks of a custom
+// component
+func RegisterSnapshotCodec(codec SnapshotCodec) {
+	snapshotCodecsM.Lock()
+	defer snapshotCodecsM.Unlock()
+	for _, v := range snapshotCodecs {
+		if v.Name == codec.Name {
+			panic(codec.Name + " snapshot codec already registered")
+		}
+	}
+	snapshotCodecs = append(snapshotCodecs, codec)
+}
+
+func registeredSnapshotCodecs() []SnapshotCodec {
+	snapshotCodecsM.Lock()
+	defer snapshotCodecsM.Unlock()
+	return append([]SnapshotCodec(nil), snapshotCodecs...)
+}
+
+// SnapshotWorld serializes the entities of a world. The built-in components
+// are saved for every entity with a Transform (the entities of the
+// TransformSystem view); the custom components are listed by their codecs.
+func SnapshotWorld(w ecs.BaseWorld, assets *SnapshotAssets) (*pb.WorldSnapshot, error) {
+	entities := make(map[ecs.Entity]*pb.EntitySnapshot)
+	get := func(e ecs.Entity) *pb.EntitySnapshot {
+		if es, ok := entities[e]; ok {
+			return es
+		}
+		es := &pb.EntitySnapshot{
+			Id: uint64(e),
+		}
+		entities[e] = es
+		return es
+	}
+	for _, v := range components.GetTransformSystem(w).V().Matches() {
+		e, t := v.Entity, v.Transform
+		get(e).Transform = &pb.TransformState{
+			Parent: uint64(t.Parent()),
+			X:      t.X(),
+			Y:      t.Y(),
+			Angle:  t.Angle(),
+			Sx:     t.ScaleX(),
+			Sy:     t.ScaleY(),
+		}
+		if d := graphics.GetDrawLayerComponentData(w, e); d != nil {
+			get(e).DrawLayer = &pb.DrawLayerState{
+				Layer:  int64(d.Layer),
+				ZIndex: d.ZIndex,
+			}
+		}
+		if s := graphics.GetSpriteComponentData(w, e); s != nil {
+			ox, oy := s.Origin()
+			offx, offy := s.Offset()
+			get(e).Sprite = &pb.SpriteState{
+				Image:    assets.ImageRef(s.Image()),
+				OriginX:  ox,
+				OriginY:  oy,
+				OffsetX:  offx,
+				OffsetY:  offy,
+				Disabled: !s.Enabled(),
+			}
+		}
+		if a := graphics.GetSpriteAnimationComponentData(w, e); a != nil {
+			get(e).SpriteAnimation = snapshotSpriteAnimation(a, assets)
+		}
+		if tw := components.GetTrTweeningComponentData(w, e); tw != nil {
+			get(e).TrTweening = snapshotTrTweening(tw)
+		}
+		if p := graphics.GetParticleEmitterComponentData(w, e); p != nil {
+			get(e).ParticleEmitter = snapshotParticleEmitter(p, assets)
+		}
+	}
+	for _, codec := range registeredSnapshotCodecs() {
+		for _, e := range codec.Entities(w) {
+			b, err := codec.Encode(w, e)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot codec %s: %w", codec.Name, err)
+			}
+			es := get(e)
+			if es.Custom == nil {
+				es.Custom = make(map[string][]byte)
+			}
+			es.Custom[codec.Name] = b
+		}
+	}
+	snap := &pb.WorldSnapshot{
+		Version:  WorldSnapshotVersion,
+		Entities: make([]*pb.EntitySnapshot, 0, len(entities)),
+	}
+	for _, v := range entities {
+		snap.Entities = append(snap.Entities, v)
+	}
+	sort.Slice(snap.Entities, func(i, j int) bool {
+		return snap.Entities[i].Id < snap.Entities[j].Id
+	})
+	return snap, nil
+}
+
+func snapshotSpriteAnimation(a *graphics.SpriteAnimation, assets *SnapshotAssets) *pb.SpriteAnimationState {
+	st := &pb.SpriteAnimationState{
+		Fps:      a.FPS(),
+		Playing:  a.Playing(),
+		Frame:    int32(a.ActiveFrame()),
+		T:        a.ClipTime(),
+		Reversed: a.Reversed(),
+	}
+	if assets != nil && a.Animation() != nil {
+		if ref, ok := assets.anims[a.Animation()]; ok {
+			st.Atlas, st.Animation = ref[0], ref[1]
+		}
+	}
+	if clip := a.ActiveClip(); clip != nil {
+		st.Clip = clip.GetName()
+	}
+	return st
+}
+
+func snapshotTrTween(v components.TrTweenTuple) *pb.TrTweenState {
+	return &pb.TrTweenState{
+		Name:     v.Name,
+		Type:     int32(v.Tween.Type),
+		From:     v.Tween.From,
+		To:       v.Tween.To,
+		Duration: v.Tween.Duration,
+		Easing:   easing.Name(v.Tween.Easing),
+	}
+}
+
+func snapshotTrTweening(t *components.TrTweening) *pb.TrTweeningState {
+	active, progress, playing := t.State()
+	st := &pb.TrTweeningState{
+		Disabled: !t.Enabled(),
+		Playing:  playing,
+		Active:   snapshotTrTween(active),
+		T:        progress,
+	}
+	for _, v := range t.Tweens() {
+		st.Tweens = append(st.Tweens, snapshotTrTween(v))
+	}
+	return st
+}
+
+func snapshotParticleEmitter(p *graphics.ParticleEmitter, assets *SnapshotAssets) *pb.ParticleEmitterState {
+	props := p.Props()
+	em := p.EmissionProp()
+	st := &pb.ParticleEmitterState{
+		Max:             int32(p.MaxParticles()),
+		Strategy:        int32(p.Strategy()),
+		Disabled:        !p.Enabled(),
+		CompositeMode:   int32(p.CompositeMode()),
+		ParentLevel:     uint32(p.ParentLevel()),
+		LockedParticles: p.LockedParticles(),
+		Props:           make(map[string]float64),
+		InitColor:       packRGBA(props.InitColor),
+		EndColor:        packRGBA(props.EndColor),
+		EmissionEnabled: em.Enabled,
+		EmissionT0:      em.T0,
+		EmissionT1:      em.T1,
+		EmissionN0:      int32(em.N0),
+		EmissionN1:      int32(em.N1),
+	}
+	pv := reflect.ValueOf(props)
+	for i := 0; i < pv.NumField(); i++ {
+		if pv.Field(i).Kind() == reflect.Float64 {
+			st.Props[pv.Type().Field(i).Name] = pv.Field(i).Float()
+		}
+	}
+	for _, img := range props.Source {
+		if ref := assets.ImageRef(img); ref != nil {
+			st.Source = append(st.Source, ref)
+		}
+	}
+	return st
+}
+
+// RestoreWorld creates the entities of a snapshot in w. The entities get new
+// ids; the returned map translates the snapshot ids to the new entities.
+//
+// Only the ECS data is restored (the Node wrappers are not recreated). If an
+// error is returned, the entities created by RestoreWorld are removed.
+func RestoreWorld(w ecs.BaseWorld, snap *pb.WorldSnapshot, assets *SnapshotAssets) (ids map[uint64]ecs.Entity, err error) {
+	if snap.GetVersion() > WorldSnapshotVersion {
+		return nil, fmt.Errorf("world snapshot version %d is not supported (max %d)", snap.GetVersion(), WorldSnapshotVersion)
+	}
+	ids = make(map[uint64]ecs.Entity, len(snap.GetEntities()))
+	for _, es := range snap.GetEntities() {
+		ids[es.GetId()] = w.NewEntity()
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, e := range ids {
+			w.RemoveEntity(e)
+		}
+		ids = nil
+	}()
+	remap := func(id uint64) ecs.Entity {
+		return ids[id]
+	}
+	// transforms first (the parents must exist)
+	for _, es := range snap.GetEntities() {
+		if t := es.GetTransform(); t != nil {
+			tr := components.NewTransform(t.GetX(), t.GetY())
+			tr.SetAngle(t.GetAngle()).SetScale(t.GetSx(), t.GetSy())
+			components.SetTransformComponentData(w, ids[es.GetId()], tr)
+		}
+	}
+	for _, es := range snap.GetEntities() {
+		if t := es.GetTransform(); t != nil && t.GetParent() != 0 {
+			tr := components.GetTransformComponentData(w, ids[es.GetId()])
+			if err := tr.SetParent(ids[t.GetParent()]); err != nil {
+				return nil, fmt.Errorf("entity %d: transform parent %d: %w", es.GetId(), t.GetParent(), err)
+			}
+		}
+	}
+	for _, es := range snap.GetEntities() {
+		e := ids[es.GetId()]
+		if d := es.GetDrawLayer(); d != nil {
+			graphics.SetDrawLayerComponentData(w, e, graphics.DrawLayer{
+				Layer:  graphics.LayerIndex(d.GetLayer()),
+				ZIndex: d.GetZIndex(),
+			})
+		}
+		if s := es.GetSprite(); s != nil {
+			img := assets.Image(s.GetImage())
+			if img == nil {
+				img = transparentImage()
+			}
+			spr := graphics.NewSprite(0, 0, img)
+			spr.SetOrigin(s.GetOriginX(), s.GetOriginY()).SetOffset(s.GetOffsetX(), s.GetOffsetY()).SetEnabled(!s.GetDisabled())
+			graphics.SetSpriteComponentData(w, e, spr)
+		}
+		if a := es.GetSpriteAnimation(); a != nil {
+			if err := restoreSpriteAnimation(w, e, a, assets); err != nil {
+				return nil, fmt.Errorf("entity %d: %w", es.GetId(), err)
+			}
+		}
+		if t := es.GetTrTweening(); t != nil {
+			components.SetTrTweeningComponentData(w, e, restoreTrTweening(t))
+		}
+		if p := es.GetParticleEmitter(); p != nil {
+			graphics.SetParticleEmitterComponentData(w, e, graphics.NewParticleEmitter(w))
+			restoreParticleEmitter(graphics.GetParticleEmitterComponentData(w, e), p, assets)
+		}
+	}
+	for _, codec := range registeredSnapshotCodecs() {
+		for _, es := range snap.GetEntities() {
+			b, ok := es.GetCustom()[codec.Name]
+			if !ok {
+				continue
+			}
+			if err := codec.Decode(w, ids[es.GetId()], b, remap); err != nil {
+				return nil, fmt.Errorf("snapshot codec %s: %w", codec.Name, err)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func restoreSpriteAnimation(w ecs.BaseWorld, e ecs.Entity, st *pb.SpriteAnimationState, assets *SnapshotAssets) error {
+	anim := assets.Animation(st.GetAtlas(), st.GetAnimation())
+	if anim == nil {
+		return fmt.Errorf("animation %s (%s) not found", st.GetAnimation(), st.GetAtlas())
+	}
+	graphics.SetSpriteAnimationComponentData(w, e, graphics.NewSpriteAnimation(st.GetFps(), anim))
+	sa := graphics.GetSpriteAnimationComponentData(w, e)
+	if !sa.RestoreState(st.GetClip(), int(st.GetFrame()), st.GetT(), st.GetPlaying(), st.GetReversed()) {
+		return fmt.Errorf("invalid clip %s (frame %d)", st.GetClip(), st.GetFrame())
+	}
+	if spr := graphics.GetSpriteComponentData(w, e); spr != nil && sa.ActiveClip() != nil {
+		spr.SetImage(sa.ActiveClip().GetImage(sa.ActiveFrame()))
+		spr.SetOffset(sa.ActiveClip().GetOffset(sa.ActiveFrame()))
+	}
+	return nil
+}
+
+func restoreTrTween(st *pb.TrTweenState) components.TrTweenTuple {
+	fn := easing.ByName(st.GetEasing())
+	if fn == nil {
+		fn = easing.Linear
+	}
+	return components.TrTweenTuple{
+		Name: st.GetName(),
+		Tween: components.TrTween{
+			Easing:   fn,
+			From:     st.GetFrom(),
+			To:       st.GetTo(),
+			Duration: st.GetDuration(),
+			Type:     components.TrTweenType(st.GetType()),
+		},
+	}
+}
+
+func restoreTrTweening(st *pb.TrTweeningState) components.TrTweening {
+	t := components.NewTrTweening()
+	for _, v := range st.GetTweens() {
+		tt := restoreTrTween(v)
+		t.SetTween(tt.Name, tt.Tween.Type, tt.Tween.From, tt.Tween.To, tt.Tween.Duration, tt.Tween.Easing)
+	}
+	t.SetEnabled(!st.GetDisabled())
+	if st.GetActive() != nil {
+		t.RestoreState(restoreTrTween(st.GetActive()), st.GetT(), st.GetPlaying())
+	}
+	return t
+}
+
+func restoreParticleEmitter(p *graphics.ParticleEmitter, st *pb.ParticleEmitterState, assets *SnapshotAssets) {
+	props := p.Props()
+	pv := reflect.ValueOf(&props).Elem()
+	for k, v := range st.GetProps() {
+		if f := pv.FieldByName(k); f.IsValid() && f.Kind() == reflect.Float64 {
+			f.SetFloat(v)
+		}
+	}
+	props.InitColor = unpackRGBA(st.GetInitColor())
+	props.EndColor = unpackRGBA(st.GetEndColor())
+	props.Source = nil
+	for _, ref := range st.GetSource() {
+		if img := assets.Image(ref); img != nil {
+			props.Source = append(props.Source, img)
+		}
+	}
+	em := p.EmissionProp()
+	em.Enabled = st.GetEmissionEnabled()
+	em.T0, em.T1 = st.GetEmissionT0(), st.GetEmissionT1()
+	em.N0, em.N1 = int(st.GetEmissionN0()), int(st.GetEmissionN1())
+	p.SetProps(props).
+		SetEmissionProp(em).
+		SetMaxParticles(int(st.GetMax())).
+		SetStrategy(graphics.SpawnStrategy(st.GetStrategy())).
+		SetEnabled(!st.GetDisabled()).
+		SetCompositeMode(ebiten.CompositeMode(st.GetCompositeMode())).
+		SetParentLevel(uint(st.GetParentLevel())).
+		SetLockedParticles(st.GetLockedParticles())
+}
+
+// MarshalWorld serializes a world snapshot
+func MarshalWorld(w ecs.BaseWorld, assets *SnapshotAssets) ([]byte, error) {
+	snap, err := SnapshotWorld(w, assets)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(snap)
+}
+
+// UnmarshalWorld restores a serialized world snapshot into w (see
+// RestoreWorld)
+func UnmarshalWorld(w ecs.BaseWorld, b []byte, assets *SnapshotAssets) (map[uint64]ecs.Entity, error) {
+	snap := &pb.WorldSnapshot{}
+	if err := proto.Unmarshal(b, snap); err != nil {
+		return nil, err
+	}
+	return RestoreWorld(w, snap, assets)
+}
+
+func packRGBA(c color.RGBA) uint32 {
+	return uint32(c.R)<<24 | uint32(c.G)<<16 | uint32(c.B)<<8 | uint32(c.A)
+}
+
+func unpackRGBA(v uint32) color.RGBA {
+	return color.RGBA{
+		R: uint8(v >> 24),
+		G: uint8(v >> 16),
+		B: uint8(v >> 8),
+		A: uint8(v),
+	}
+}
+
+var snapshotEmptyImage *ebiten.Image
+var snapshotEmptyImageOnce sync.Once
+
+func transparentImage() *ebiten.Image {
+	snapshotEmptyImageOnce.Do(func() {
+		snapshotEmptyImage, _ = ebiten.NewImage(1, 1, ebiten.FilterDefault)
+	})
+	return snapshotEmptyImage
+}
@@ -0,0 +1,22 @@
+package io
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/gabstv/primen/io/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotColor(t *testing.T) {
+	c := color.RGBA{R: 1, G: 2, B: 3, A: 255}
+	assert.Equal(t, uint32(0x010203ff), packRGBA(c))
+	assert.Equal(t, c, unpackRGBA(packRGBA(c)))
+}
+
+func TestRestoreWorldVersion(t *testing.T) {
+	_, err := RestoreWorld(nil, &pb.WorldSnapshot{
+		Version: WorldSnapshotVersion + 1,
+	}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,278 @@
+package primen
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"sync"
+
+	"github.com/gabstv/primen/io"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+// NewAssetSceneFn builds a scene after all of its assets are loaded. The
+// container (with the loaded assets) is owned by the new scene, so it
+// should unload it when the scene is unloaded (see SceneBase.SetupWithContainer).
+type NewAssetSceneFn func(engine Engine, assets io.Container) (Scene, chan struct{})
+
+// RegisterAssetScene registers a scene that declares its assets. When it's
+// loaded, a LoadingScene is shown while the assets are loaded, and the scene
+// is only built (by fn) after everything is loaded. The LoadingScene is then
+// replaced by the new scene (in the same position of the scene stack).
+func RegisterAssetScene(name string, assets []string, fn NewAssetSceneFn) {
+	files := make([]string, len(assets))
+	copy(files, assets)
+	RegisterScene(name, func(engine Engine) (Scene, chan struct{}) {
+		return newLoadingScene(engine, name, files, fn)
+	})
+}
+
+// LoadingState is the state of a LoadingScene
+type LoadingState struct {
+	Scene    string
+	Progress float64
+	Loaded   int
+	Total    int
+	// Err is the first load error. The scene is not built if a file fails to
+	// load.
+	Err error
+}
+
+// LoadingView draws the loading screen
+type LoadingView interface {
+	DrawLoading(ctx DrawCtx, state LoadingState)
+}
+
+// LoadingViewFunc is a function that implements LoadingView
+type LoadingViewFunc func(ctx DrawCtx, state LoadingState)
+
+// DrawLoading implements LoadingView
+func (fn LoadingViewFunc) DrawLoading(ctx DrawCtx, state LoadingState) {
+	fn(ctx, state)
+}
+
+// DefaultLoadingView draws a progress bar and a label
+type DefaultLoadingView struct {
+	Background    color.Color
+	BarColor      color.Color
+	BarBackground color.Color
+	ErrorColor    color.Color
+	// Label is the format of the label (the progress percentage is the
+	// argument)
+	Label string
+}
+
+// DrawLoading implements LoadingView
+func (v *DefaultLoadingView) DrawLoading(ctx DrawCtx, state LoadingState) {
+	screen := ctx.Renderer().Screen()
+	w, h := screen.Size()
+	fw, fh := float64(w), float64(h)
+	screen.Fill(v.Background)
+	bw := fw * .6
+	bh := 12.0
+	bx := (fw - bw) / 2
+	by := fh/2 - bh/2
+	ebitenutil.DrawRect(screen, bx, by, bw, bh, v.BarBackground)
+	if state.Err != nil {
+		ebitenutil.DrawRect(screen, bx, by, bw*state.Progress, bh, v.ErrorColor)
+		ebitenutil.DebugPrintAt(screen, "ERROR: "+state.Err.Error(), int(bx), int(by+bh+8))
+		return
+	}
+	ebitenutil.DrawRect(screen, bx, by, bw*state.Progress, bh, v.BarColor)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(v.Label, state.Progress*100), int(bx), int(by+bh+8))
+}
+
+var (
+	loadingView LoadingView = &DefaultLoadingView{
+		Background:    color.RGBA{0, 0, 0, 255},
+		BarColor:      color.RGBA{255, 255, 255, 255},
+		BarBackground: color.RGBA{64, 64, 64, 255},
+		ErrorColor:    color.RGBA{200, 40, 40, 255},
+		Label:         "Loading... %.0f%%",
+	}
+	loadingViewM sync.Mutex
+)
+
+// SetLoadingView sets the view used by the loading scenes
+func SetLoadingView(v LoadingView) {
+	loadingViewM.Lock()
+	defer loadingViewM.Unlock()
+	loadingView = v
+}
+
+func getLoadingView() LoadingView {
+	loadingViewM.Lock()
+	defer loadingViewM.Unlock()
+	return loadingView
+}
+
+// LoadingDrawPriority is the priority of the loading screen draw function
+// (see Engine.AddTempDrawFn)
+const LoadingDrawPriority = 1000
+
+// LoadingScene is the scene that is shown while the assets of a scene
+// (registered with RegisterAssetScene) are loading.
+type LoadingScene struct {
+	engine    Engine
+	name      string
+	assets    []string
+	container io.Container
+	build     NewAssetSceneFn
+	view      LoadingView
+	cancel    context.CancelFunc
+	m         sync.Mutex
+	state     LoadingState
+	target    Scene
+	unloaded  bool
+	ready     chan struct{}
+}
+
+func newLoadingScene(engine Engine, name string, assets []string, fn NewAssetSceneFn) (*LoadingScene, chan struct{}) {
+	ctx, cancel := context.WithCancel(engine.Ctx())
+	s := &LoadingScene{
+		engine:    engine,
+		name:      name,
+		assets:    assets,
+		container: io.NewContainer(ctx, engine.FS()),
+		build:     fn,
+		view:      getLoadingView(),
+		cancel:    cancel,
+		ready:     make(chan struct{}),
+		state: LoadingState{
+			Scene: name,
+			Total: len(assets),
+		},
+	}
+	engine.AddTempDrawFn(LoadingDrawPriority, s.draw)
+	go s.load()
+	ch := make(chan struct{})
+	close(ch)
+	return s, ch
+}
+
+// Name returns the name of the scene that is being loaded
+func (s *LoadingScene) Name() string {
+	return s.name
+}
+
+// State returns the current loading state
+func (s *LoadingScene) State() LoadingState {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.state
+}
+
+// Ready is closed when the target scene is built and loaded
+func (s *LoadingScene) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Target returns the scene that was built after loading (or nil)
+func (s *LoadingScene) Target() Scene {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.target
+}
+
+// Unload cancels the loading (if it's still loading)
+func (s *LoadingScene) Unload() chan struct{} {
+	s.m.Lock()
+	s.unloaded = true
+	built := s.target != nil
+	s.m.Unlock()
+	s.cancel()
+	if !built {
+		s.container.UnloadAll()
+	}
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func (s *LoadingScene) load() {
+	progress, done := s.container.LoadAll(s.assets)
+	for p := range progress {
+		s.m.Lock()
+		s.state.Progress = p
+		s.state.Loaded = int(p*float64(s.state.Total) + .5)
+		s.m.Unlock()
+	}
+	<-done
+	var err error
+	for _, name := range s.assets {
+		if err = s.container.LoadErr(name); err != nil {
+			err = fmt.Errorf("%s: %w", name, err)
+			break
+		}
+	}
+	s.m.Lock()
+	unloaded := s.unloaded
+	if err == nil && !unloaded && s.state.Loaded < s.state.Total {
+		// canceled by the engine context
+		err = context.Canceled
+	}
+	s.state.Err = err
+	if err == nil {
+		s.state.Progress = 1
+		s.state.Loaded = s.state.Total
+	}
+	s.m.Unlock()
+	if err != nil || unloaded {
+		return
+	}
+	// the scene must be built on the main thread
+	s.engine.RunFn(s.buildTarget)
+}
+
+func (s *LoadingScene) buildTarget() {
+	s.m.Lock()
+	if s.unloaded {
+		s.m.Unlock()
+		return
+	}
+	s.m.Unlock()
+	target, ch := s.build(s.engine, s.container)
+	s.m.Lock()
+	s.target = target
+	s.m.Unlock()
+	if sw, ok := s.engine.(sceneSwapper); ok {
+		sw.swapScene(s, target)
+	}
+	go func() {
+		if ch != nil {
+			<-ch
+		}
+		close(s.ready)
+	}()
+}
+
+func (s *LoadingScene) draw(ctx DrawCtx) bool {
+	s.m.Lock()
+	state := s.state
+	keep := s.target == nil && !s.unloaded
+	s.m.Unlock()
+	if !keep {
+		return false
+	}
+	s.view.DrawLoading(ctx, state)
+	return true
+}
+
+type sceneSwapper interface {
+	swapScene(old, scene Scene)
+}
+
+// swapScene replaces a scene of the stack (or of a pending scene change)
+// without unloading it
+func (e *engine) swapScene(old, scene Scene) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for i, v := range e.scenes {
+		if v == old {
+			e.scenes[i] = scene
+		}
+	}
+	if c := e.sceneChange; c != nil && c.scene == old {
+		c.scene = scene
+	}
+}
@@ -0,0 +1,101 @@
+package primen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabstv/primen/io"
+	osfs "github.com/gabstv/primen/io/os"
+	"github.com/stretchr/testify/assert"
+)
+
+// stepUntil steps the engine until cond returns true (or it times out)
+func stepUntil(t *testing.T, e Engine, cond func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Error("timeout")
+			return false
+		}
+		assert.NoError(t, e.Step(1.0/60))
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestLoadingScene(t *testing.T) {
+	dir, err := ioutil.TempDir("", "primen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("A"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.txt"), []byte("B"), 0644))
+
+	var log []string
+	built := 0
+	build := func(engine Engine, assets io.Container) (Scene, chan struct{}) {
+		built++
+		// all the assets are loaded before the scene is built
+		a, err := assets.Get("a.txt")
+		assert.NoError(t, err)
+		b, err := assets.Get("b.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "AB", string(a)+string(b))
+		return &stackScene{name: "game", log: &log}, nil
+	}
+	e := NewHeadlessEngine(&NewEngineInput{
+		FS: osfs.New(dir),
+	})
+	e.(*engine).sceneldrs = map[string]NewSceneFn{
+		"game": func(engine Engine) (Scene, chan struct{}) {
+			return newLoadingScene(engine, "game", []string{"a.txt", "b.txt"}, build)
+		},
+		"broken": func(engine Engine) (Scene, chan struct{}) {
+			return newLoadingScene(engine, "broken", []string{"a.txt", "missing.txt"}, build)
+		},
+	}
+
+	_, err = e.PushScene("game", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(1.0/60))
+	ls, ok := e.SceneStack()[0].(*LoadingScene)
+	assert.True(t, ok)
+	assert.Equal(t, "game", ls.Name())
+	// the scene is built on a later frame (on the main thread)
+	assert.Equal(t, 0, built)
+	stepUntil(t, e, func() bool {
+		select {
+		case <-ls.Ready():
+			return true
+		default:
+			return false
+		}
+	})
+	assert.Equal(t, 1, built)
+	assert.Equal(t, LoadingState{Scene: "game", Progress: 1, Loaded: 2, Total: 2}, ls.State())
+	// the loading scene was replaced (without unloading the target)
+	stack := e.SceneStack()
+	assert.Equal(t, 1, len(stack))
+	assert.Equal(t, ls.Target(), stack[0])
+	assert.Equal(t, "game", stack[0].Name())
+	assert.Equal(t, 0, len(log))
+
+	// a failed load is reported and the scene is not built
+	_, err = e.ReplaceScene("broken", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, e.Step(1.0/60))
+	ls, ok = e.SceneStack()[0].(*LoadingScene)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"unload game"}, log)
+	stepUntil(t, e, func() bool {
+		return ls.State().Err != nil
+	})
+	assert.Contains(t, ls.State().Err.Error(), "missing.txt")
+	assert.NoError(t, e.StepN(4, 1.0/60))
+	assert.Equal(t, 1, built)
+	assert.Nil(t, ls.Target())
+	assert.Equal(t, Scene(ls), e.SceneStack()[0])
+	ls.Unload()
+}
@@ -0,0 +1,68 @@
+package primen
+
+import (
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
+)
+
+// Message is an entity-targeted message
+type Message = components.Message
+
+// SendMessage delivers a message to the handlers of an object. If bubble is
+// true, the message is also delivered to the ObjectContainer parents (from
+// the closest to the root) until a handler cancels it.
+//
+// It returns false if the message was cancelled.
+func SendMessage(obj Object, name string, data interface{}, bubble bool) bool {
+	if obj == nil || obj.World() == nil {
+		return true
+	}
+	chain := []ecs.Entity{obj.Entity()}
+	if bubble {
+		for p := obj.Parent(); p != nil; p = p.Parent() {
+			if containsEntity(chain, p.Entity()) {
+				// cyclic hierarchy
+				break
+			}
+			chain = append(chain, p.Entity())
+		}
+	}
+	i := 0
+	return components.SendMessageChain(&components.Message{
+		Name:   name,
+		Data:   data,
+		Target: obj.Entity(),
+		World:  obj.World(),
+	}, func(cur ecs.Entity) ecs.Entity {
+		i++
+		if i >= len(chain) {
+			return 0
+		}
+		return chain[i]
+	})
+}
+
+func containsEntity(list []ecs.Entity, e ecs.Entity) bool {
+	for _, v := range list {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// OnMessage adds a message handler to the node.
+// Use components.MessageCatchAll to receive every message.
+func (t *Node) OnMessage(name string, fn components.MessageFn) components.MessageHandlerID {
+	return components.AddMessageHandler(t.w, t.e, name, fn)
+}
+
+// RemoveMessageHandler removes a message handler of the node.
+func (t *Node) RemoveMessageHandler(id components.MessageHandlerID) bool {
+	return components.RemoveMessageHandler(t.w, t.e, id)
+}
+
+// SendMessage sends a message to the node. See SendMessage.
+func (t *Node) SendMessage(name string, data interface{}, bubble bool) bool {
+	return SendMessage(t, name, data, bubble)
+}
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"github.com/gabstv/primen/core/logger"
 	"github.com/gabstv/primen/dom"
 	"github.com/gabstv/primen/internal/z"
 	"github.com/inkyblackness/imgui-go/v2"
@@ -17,17 +18,16 @@ func button(ctx *Context, node dom.ElementNode) {
 		if jsv := attrs["onclick"]; jsv != "" {
 			rv, err := ctx.JS.RunString(jsv)
 			if err != nil {
-				println("js err: " + err.Error())
+				ctx.Draw.Engine().Logger().Tag("js").Error("onclick failed", logger.F("id", node.ID()), logger.Err(err))
 				bubbles = false
 			} else {
-				println("js fn ok")
 				if rv == nil || !rv.ToBoolean() {
 					bubbles = false
 				}
 			}
 		}
 		if bubbles {
-			println("bubbles: button click!!!")
+			ctx.log().Debug("button click", logger.F("id", node.ID()))
 		}
 	}
 }
@@ -3,6 +3,7 @@ package renderer
 import (
 	"github.com/dop251/goja"
 	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/logger"
 	"github.com/gabstv/primen/dom"
 	"github.com/gabstv/primen/modules/imgui/style"
 	"github.com/inkyblackness/imgui-go/v2"
@@ -14,6 +15,11 @@ type Context struct {
 	Stack *style.Stack
 }
 
+// log returns the imgui logger of the engine
+func (ctx *Context) log() *logger.Logger {
+	return ctx.Draw.Engine().Logger().Tag("imgui")
+}
+
 func NewContext(ctx core.DrawCtx, jsvm *goja.Runtime) *Context {
 	return &Context{
 		Draw:  ctx,
@@ -12,7 +12,7 @@ import (
 func window(ctx *Context, node dom.ElementNode) {
 	attrs := node.Attributes()
 	if node.ID() == "" {
-		println("warning: window didn't have an ID")
+		ctx.log().Warn("window without an ID")
 		node.SetAttribute("id", z.Rs())
 	}
 	wname := attrs["name"]
@@ -38,7 +38,7 @@ func window(ctx *Context, node dom.ElementNode) {
 
 func demoWindow(ctx *Context, node dom.ElementNode) {
 	if node.ID() == "" {
-		println("warning: demo window didn't have an ID")
+		ctx.log().Warn("demo window without an ID")
 		node.SetAttribute("id", z.Rs())
 	}
 	show := node.Attributes().BoolD("visible", true)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dop251/goja"
 	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/logger"
 	"github.com/gabstv/primen/internal/z"
 	"github.com/gabstv/primen/modules/imgui/common"
 	"github.com/inkyblackness/imgui-go/v2"
@@ -137,7 +138,7 @@ var stparser = map[string]styleUpdateFn{
 
 func Push(attributes map[string]string) (styles, colors int) {
 	if attributes["id"] == "" {
-		println("warning: [pushStyles] element didn't have an ID")
+		logger.Default.Tag("imgui").Warn("element without an ID (Push)")
 		attributes["id"] = z.Rs()
 	}
 	for name, rawval := range attributes {
@@ -0,0 +1,90 @@
+package primen
+
+import (
+	"strings"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
+)
+
+// SetName sets the name of the object (see components.SetName)
+func (o *mObject) SetName(name string) {
+	components.SetName(o.w, o.e, name)
+}
+
+// Name returns the name of the object
+func (o *mObject) Name() string {
+	if o.w == nil {
+		return ""
+	}
+	if d := components.GetNameComponentData(o.w, o.e); d != nil {
+		return d.Name()
+	}
+	return ""
+}
+
+// AddTags adds tags to the object
+func (o *mObject) AddTags(tags ...string) {
+	components.AddTags(o.w, o.e, tags...)
+}
+
+// RemoveTags removes tags from the object
+func (o *mObject) RemoveTags(tags ...string) {
+	components.RemoveTags(o.w, o.e, tags...)
+}
+
+// HasTag returns true if the object has the tag
+func (o *mObject) HasTag(tag string) bool {
+	if o.w == nil {
+		return false
+	}
+	if d := components.GetNameComponentData(o.w, o.e); d != nil {
+		return d.HasTag(tag)
+	}
+	return false
+}
+
+// Find returns the entity at path ("level/enemies/boss/weapon"). The path
+// follows the Transform hierarchy of the world (see components.FindPath).
+func Find(w World, path string) ecs.Entity {
+	return components.FindPath(w, path)
+}
+
+// FindByName returns the first entity of the world with the name
+func FindByName(w World, name string) ecs.Entity {
+	return components.FindByName(w, name)
+}
+
+// FindAllByTag returns all the entities of the world with the tag
+func FindAllByTag(w World, tag string) []ecs.Entity {
+	return components.FindAllByTag(w, tag)
+}
+
+// FindChild returns the object at path ("enemies/boss/weapon"), relative to
+// parent. The path follows the ObjectContainer children.
+func FindChild(parent ObjectContainer, path string) Object {
+	var cur Object = parent
+	for _, name := range strings.Split(path, "/") {
+		if name == "" {
+			continue
+		}
+		c, ok := cur.(ObjectContainer)
+		if !ok {
+			return nil
+		}
+		cur = nil
+		for _, child := range c.Children() {
+			if child == nil || child.World() == nil {
+				continue
+			}
+			if d := components.GetNameComponentData(child.World(), child.Entity()); d != nil && d.Name() == name {
+				cur = child
+				break
+			}
+		}
+		if cur == nil {
+			return nil
+		}
+	}
+	return cur
+}
@@ -3,6 +3,7 @@ package primen
 import (
 	"github.com/gabstv/ecs/v2"
 	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/core/logger"
 )
 
 type TransformGetter interface {
@@ -12,8 +13,11 @@ type TransformGetter interface {
 
 type Node struct {
 	*mObjectContainer
-	wtr    components.WatchTransform
-	wtrtwn components.WatchTrTweening
+	wtr       components.WatchTransform
+	wtrtwn    components.WatchTrTweening
+	onEnable  []func()
+	onDisable []func()
+	onDestroy []func()
 }
 
 func NewRootNode(w World) *Node {
@@ -60,30 +64,146 @@ func (t *Node) TrTweening() *components.TrTweening {
 	return t.wtrtwn.Data()
 }
 
+// SetParent changes the parent of the node. If the parent is the node itself
+// or one of its children (components.ErrCyclicTransform), or if the parent
+// entity has no transform (components.ErrTransformNotFound), the error is
+// logged and the parent is left unchanged (see SetParentKeepWorld).
 func (t *Node) SetParent(parent ObjectContainer) {
-	if t.parent != nil {
-		if _, ok := t.parent.(TransformGetter); ok {
-			t.wtr.Data().SetParent(0)
-		}
-		t.parent.RemoveChild(t)
+	if err := t.setParent(parent, false); err != nil {
+		nodeLogger(t.World()).Error("SetParent failed", logger.F("entity", t.Entity()), logger.Err(err))
 	}
-	if parent == nil {
-		t.parent = nil
-		return
+}
+
+// SetParentKeepWorld changes the parent of the node, keeping the world
+// position, angle and scale. It returns components.ErrCyclicTransform if the
+// parent is the node itself or one of its children.
+func (t *Node) SetParentKeepWorld(parent ObjectContainer) error {
+	return t.setParent(parent, true)
+}
+
+// nodeLogger returns the logger of the engine of the world
+func nodeLogger(w World) *logger.Logger {
+	if w != nil && w.Engine() != nil {
+		return w.Engine().Logger().Tag("node")
 	}
+	return logger.Default.Tag("node")
+}
+
+func (t *Node) setParent(parent ObjectContainer, keepWorld bool) error {
+	prev := t.ActiveInHierarchy()
+	var pe ecs.Entity
 	if p, ok := parent.(TransformGetter); ok {
-		t.wtr.Data().SetParent(p.Entity())
+		pe = p.Entity()
+	}
+	var err error
+	if keepWorld {
+		err = t.wtr.Data().SetParentKeepWorld(pe)
+	} else {
+		err = t.wtr.Data().SetParent(pe)
+	}
+	if err != nil {
+		return err
+	}
+	if t.parent != nil {
+		t.parent.RemoveChild(t)
 	}
 	t.mObject.SetParent(parent)
+	if parent != nil {
+		parent.AddChild(t)
+	}
+	if cur := t.ActiveInHierarchy(); cur != prev {
+		t.activeChanged(cur)
+	}
+	return nil
+}
+
+// SetActive activates or deactivates the node. Inactive nodes (and their
+// children) are not updated or drawn. OnEnable/OnDisable are called on the
+// nodes whose ActiveInHierarchy changed.
+func (t *Node) SetActive(v bool) {
+	prev := t.ActiveInHierarchy()
+	t.wtr.Data().SetActive(v)
+	if cur := t.ActiveInHierarchy(); cur != prev {
+		t.activeChanged(cur)
+	}
+}
+
+// Active returns the active flag of the node (see ActiveInHierarchy)
+func (t *Node) Active() bool {
+	return t.wtr.Data().Active()
 }
 
+// ActiveInHierarchy returns true if the node and all its parents are active
+func (t *Node) ActiveInHierarchy() bool {
+	return t.wtr.Data().ActiveInHierarchy()
+}
+
+// SetVisible shows or hides the node. Hidden nodes (and their children) are
+// updated, but not drawn.
+func (t *Node) SetVisible(v bool) {
+	t.wtr.Data().SetVisible(v)
+}
+
+// Visible returns the visible flag of the node (see VisibleInHierarchy)
+func (t *Node) Visible() bool {
+	return t.wtr.Data().Visible()
+}
+
+// VisibleInHierarchy returns true if the node and all its parents are active
+// and visible
+func (t *Node) VisibleInHierarchy() bool {
+	return t.wtr.Data().VisibleInHierarchy()
+}
+
+// OnEnable adds a function that is called when the node becomes active in
+// the hierarchy
+func (t *Node) OnEnable(fn func()) {
+	t.onEnable = append(t.onEnable, fn)
+}
+
+// OnDisable adds a function that is called when the node becomes inactive
+// in the hierarchy
+func (t *Node) OnDisable(fn func()) {
+	t.onDisable = append(t.onDisable, fn)
+}
+
+// OnDestroy adds a function that is called when the node is destroyed
+// (before its children are destroyed)
+func (t *Node) OnDestroy(fn func()) {
+	t.onDestroy = append(t.onDestroy, fn)
+}
+
+// activeChanged calls OnEnable/OnDisable of the node and of the children
+// that follow the node state
+func (t *Node) activeChanged(active bool) {
+	fns := t.onDisable
+	if active {
+		fns = t.onEnable
+	}
+	for _, fn := range fns {
+		fn()
+	}
+	for _, child := range t.Children() {
+		if n, ok := child.(*Node); ok && n.wtr != nil && n.Active() {
+			n.activeChanged(active)
+		}
+	}
+}
+
+// Destroy calls the OnDestroy functions and destroys the node and all its
+// children
 func (t *Node) Destroy() {
+	fns := t.onDestroy
+	t.onDestroy = nil
+	for _, fn := range fns {
+		fn()
+	}
+	t.onEnable = nil
+	t.onDisable = nil
 	t.wtr = nil
 	t.mObjectContainer.Destroy()
 }
 
-//TODO: implement Detroy
-
 // type Transform struct {
 // 	*WorldItem
 // 	*TransformItem
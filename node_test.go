@@ -0,0 +1,58 @@
+package primen
+
+import (
+	"testing"
+
+	"github.com/gabstv/ecs/v2"
+	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/core/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeActiveCallbacks(t *testing.T) {
+	e := NewHeadlessEngine(nil)
+	w := e.NewWorldWithDefaults(0)
+	root := NewRootNode(w)
+	child := NewChildNode(root)
+	fn := NewChildFnNode(child)
+	var log []string
+	child.OnEnable(func() { log = append(log, "child enabled") })
+	child.OnDisable(func() { log = append(log, "child disabled") })
+	fn.OnDisable(func() { log = append(log, "fn disabled") })
+	fn.OnDestroy(func() { log = append(log, "fn destroyed") })
+
+	root.SetActive(false)
+	assert.False(t, fn.ActiveInHierarchy())
+	assert.True(t, fn.Active())
+
+	// the child is inactive itself, so it stays disabled
+	child.SetActive(false)
+	root.SetActive(true)
+	child.SetActive(true)
+	assert.Equal(t, []string{"child disabled", "fn disabled", "child enabled"}, log)
+
+	root.Destroy()
+	assert.Equal(t, "fn destroyed", log[len(log)-1])
+	assert.Equal(t, 0, len(root.Children()))
+}
+
+func TestNodeSetParent(t *testing.T) {
+	l := logger.New()
+	e := NewHeadlessEngine(&NewEngineInput{
+		Logger: l,
+	})
+	w := e.NewWorldWithDefaults(0)
+	platform := NewRootNode(w)
+	item := NewChildNode(platform)
+
+	// a failed SetParent is logged and the parent is unchanged
+	assert.NotPanics(t, func() { platform.SetParent(item) })
+	assert.Equal(t, ecs.Entity(0), platform.Transform().Parent())
+	entries := l.Recent(0)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "node", entries[0].Tag)
+	assert.Equal(t, components.ErrCyclicTransform, entries[0].Field("err"))
+	assert.Equal(t, components.ErrCyclicTransform, platform.SetParentKeepWorld(item))
+	assert.NoError(t, item.SetParentKeepWorld(nil))
+	assert.Equal(t, 0, len(platform.Children()))
+}
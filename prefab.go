@@ -0,0 +1,503 @@
+package primen
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gabstv/primen/components"
+	"github.com/gabstv/primen/components/graphics"
+	"github.com/gabstv/primen/dom"
+	"github.com/gabstv/primen/io"
+	"github.com/hajimehoshi/ebiten"
+)
+
+// PrefabOverrides are per-instance prefab property overrides
+// (see io.PrefabOverrides).
+type PrefabOverrides = io.PrefabOverrides
+
+// PrefabLoader instantiates prefabs (and the atlases they reference) loaded
+// from a container. The parsed prefabs and atlases are cached.
+type PrefabLoader struct {
+	c       io.Container
+	l       sync.Mutex
+	prefabs map[string]*io.Prefab
+	atlases map[string]*io.Atlas
+	images  map[string]*ebiten.Image
+}
+
+// NewPrefabLoader creates a prefab loader that reads the prefabs from c
+func NewPrefabLoader(c io.Container) *PrefabLoader {
+	return &PrefabLoader{
+		c:       c,
+		prefabs: make(map[string]*io.Prefab),
+		atlases: make(map[string]*io.Atlas),
+		images:  make(map[string]*ebiten.Image),
+	}
+}
+
+// Container returns the container of the prefab loader
+func (l *PrefabLoader) Container() io.Container {
+	return l.c
+}
+
+// Prefab returns a (cached) prefab definition
+func (l *PrefabLoader) Prefab(name string) (*io.Prefab, error) {
+	l.l.Lock()
+	p, ok := l.prefabs[name]
+	l.l.Unlock()
+	if ok {
+		return p, nil
+	}
+	p, err := l.c.GetPrefab(name)
+	if err != nil {
+		return nil, fmt.Errorf("prefab %s: %w", name, err)
+	}
+	l.l.Lock()
+	l.prefabs[name] = p
+	l.l.Unlock()
+	return p, nil
+}
+
+func (l *PrefabLoader) atlas(name string) (*io.Atlas, error) {
+	if name == "" {
+		return nil, fmt.Errorf("atlas not specified")
+	}
+	l.l.Lock()
+	a, ok := l.atlases[name]
+	l.l.Unlock()
+	if ok {
+		return a, nil
+	}
+	a, err := l.c.GetAtlas(name)
+	if err != nil {
+		return nil, err
+	}
+	l.l.Lock()
+	l.atlases[name] = a
+	l.l.Unlock()
+	return a, nil
+}
+
+func (l *PrefabLoader) image(name string) (*ebiten.Image, error) {
+	l.l.Lock()
+	img, ok := l.images[name]
+	l.l.Unlock()
+	if ok {
+		return img, nil
+	}
+	rawimg, err := l.c.GetImage(name)
+	if err != nil {
+		return nil, err
+	}
+	img, err = ebiten.NewImageFromImage(rawimg, ebiten.FilterDefault)
+	if err != nil {
+		return nil, err
+	}
+	l.l.Lock()
+	l.images[name] = img
+	l.l.Unlock()
+	return img, nil
+}
+
+// NewRoot instantiates a prefab as a root node of the world
+func (l *PrefabLoader) NewRoot(w World, name string, overrides PrefabOverrides) (*Node, error) {
+	return l.instantiate(w, nil, name, overrides, nil)
+}
+
+// NewChild instantiates a prefab as a child node of parent
+func (l *PrefabLoader) NewChild(parent ObjectContainer, name string, overrides PrefabOverrides) (*Node, error) {
+	if parent == nil {
+		panic("parent can't be nil")
+	}
+	return l.instantiate(parent.World(), parent, name, overrides, nil)
+}
+
+func (l *PrefabLoader) instantiate(w World, parent ObjectContainer, name string, overrides PrefabOverrides, stack []string) (*Node, error) {
+	for _, v := range stack {
+		if v == name {
+			return nil, fmt.Errorf("prefab: cyclic reference (%s)", strings.Join(append(stack, name), " > "))
+		}
+	}
+	p, err := l.Prefab(name)
+	if err != nil {
+		return nil, err
+	}
+	if overrides == nil {
+		overrides = make(PrefabOverrides)
+	}
+	return l.newNode(w, parent, p, "", p.Root, overrides, append(stack, name))
+}
+
+func (l *PrefabLoader) newNode(w World, parent ObjectContainer, p *io.Prefab, path string, pn *io.PrefabNode, overrides PrefabOverrides, stack []string) (*Node, error) {
+	var node *Node
+	if pn.Prefab != "" {
+		// nested prefab: the components of this node, the <override> elements
+		// and the overrides of the outer instance are applied (in this order)
+		sub := make(PrefabOverrides)
+		for cname, props := range pn.Components {
+			for k, v := range props {
+				sub.Set("", cname+"."+k, v)
+			}
+		}
+		sub = sub.Merge(pn.Overrides).Merge(overrides.Sub(path))
+		n, err := l.instantiate(w, parent, pn.Prefab, sub, stack)
+		if err != nil {
+			return nil, err
+		}
+		node = n
+	} else {
+		if parent != nil {
+			node = NewChildNode(parent)
+		} else {
+			node = NewRootNode(w)
+		}
+		if err := l.setupNode(node, p, path, pn, overrides[path]); err != nil {
+			node.Destroy()
+			return nil, err
+		}
+	}
+	if pn.ID != "" {
+		node.SetName(pn.ID)
+	}
+	if len(pn.Tags) > 0 {
+		node.AddTags(pn.Tags...)
+	}
+	for i, child := range pn.Children {
+		if _, err := l.newNode(w, node, p, pn.ChildPath(path, i), child, overrides, stack); err != nil {
+			node.Destroy()
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func (l *PrefabLoader) setupNode(node *Node, p *io.Prefab, path string, pn *io.PrefabNode, overrides map[string]string) error {
+	for _, cname := range pn.ComponentNames(overrides) {
+		props := pn.Props(cname, overrides)
+		var err error
+		switch cname {
+		case io.PrefabTransform:
+			err = prefabTransform(node, props)
+		case io.PrefabDrawLayer:
+			err = prefabDrawLayer(node, props)
+		case io.PrefabSprite:
+			err = l.prefabSprite(node, p, props)
+		case io.PrefabSpriteAnimation:
+			err = l.prefabSpriteAnimation(node, p, props)
+		case io.PrefabParticleEmitter:
+			err = l.prefabParticleEmitter(node, p, props)
+		case io.PrefabAudioPlayer:
+			err = l.prefabAudioPlayer(node, props)
+		}
+		if err != nil {
+			return fmt.Errorf("prefab %s: node '%s' <%s>: %w", p.Name, path, cname, err)
+		}
+	}
+	return nil
+}
+
+func prefabTransform(node *Node, props dom.Attributes) error {
+	tr := node.Transform()
+	x, y, angle := tr.X(), tr.Y(), tr.Angle()
+	sx, sy := tr.Scale()
+	if v, ok := props["scale"]; ok {
+		scale, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		sx, sy = scale, scale
+	}
+	if err := firstErr(
+		prefabFloat(props, "x", &x),
+		prefabFloat(props, "y", &y),
+		prefabFloat(props, "angle", &angle),
+		prefabFloat(props, "sx", &sx),
+		prefabFloat(props, "sy", &sy),
+	); err != nil {
+		return err
+	}
+	tr.SetX(x).SetY(y).SetAngle(angle).SetScale(sx, sy)
+	return nil
+}
+
+func prefabDrawLayer(node *Node, props dom.Attributes) error {
+	dl := graphics.DrawLayer{
+		ZIndex: graphics.ZIndexTop,
+	}
+	if v := props.String("layer"); v != "" {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		dl.Layer = Layer(i)
+	}
+	switch v := props.String("z"); v {
+	case "", "top":
+	case "bottom":
+		dl.ZIndex = graphics.ZIndexBottom
+	default:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		dl.ZIndex = i
+	}
+	graphics.SetDrawLayerComponentData(node.World(), node.Entity(), dl)
+	return nil
+}
+
+// ensureDrawLayer adds the default draw layer if the node doesn't have one
+func ensureDrawLayer(node *Node) {
+	if graphics.GetDrawLayerComponentData(node.World(), node.Entity()) != nil {
+		return
+	}
+	graphics.SetDrawLayerComponentData(node.World(), node.Entity(), graphics.DrawLayer{
+		Layer:  Layer0,
+		ZIndex: graphics.ZIndexTop,
+	})
+}
+
+// ensureSprite adds an empty sprite if the node doesn't have one
+func ensureSprite(node *Node) *graphics.Sprite {
+	ensureDrawLayer(node)
+	if spr := graphics.GetSpriteComponentData(node.World(), node.Entity()); spr != nil {
+		return spr
+	}
+	graphics.SetSpriteComponentData(node.World(), node.Entity(), graphics.NewSprite(0, 0, transparentPixel))
+	return graphics.GetSpriteComponentData(node.World(), node.Entity())
+}
+
+func (l *PrefabLoader) atlasOf(p *io.Prefab, props dom.Attributes) (*io.Atlas, error) {
+	if name := props.String("atlas"); name != "" {
+		return l.atlas(name)
+	}
+	return l.atlas(p.Atlas)
+}
+
+func (l *PrefabLoader) prefabSprite(node *Node, p *io.Prefab, props dom.Attributes) error {
+	spr := ensureSprite(node)
+	if frame := props.String("frame"); frame != "" {
+		atlas, err := l.atlasOf(p, props)
+		if err != nil {
+			return err
+		}
+		sub := atlas.GetSubImage(frame)
+		if sub == nil {
+			return fmt.Errorf("frame '%s' not found", frame)
+		}
+		spr.SetImage(sub.Image)
+	} else if name := props.String("image"); name != "" {
+		img, err := l.image(name)
+		if err != nil {
+			return err
+		}
+		spr.SetImage(img)
+	}
+	ox, oy := spr.Origin()
+	var offx, offy float64
+	if err := firstErr(
+		prefabFloat(props, "ox", &ox),
+		prefabFloat(props, "oy", &oy),
+		prefabFloat(props, "offx", &offx),
+		prefabFloat(props, "offy", &offy),
+	); err != nil {
+		return err
+	}
+	spr.SetOrigin(ox, oy).SetOffset(offx, offy).SetEnabled(props.BoolD("enabled", true))
+	return nil
+}
+
+func (l *PrefabLoader) prefabSpriteAnimation(node *Node, p *io.Prefab, props dom.Attributes) error {
+	name := props.String("anim")
+	if name == "" {
+		return fmt.Errorf("anim not specified")
+	}
+	atlas, err := l.atlasOf(p, props)
+	if err != nil {
+		return err
+	}
+	var anim graphics.Animation
+	for _, v := range atlas.GetAnimations() {
+		if v.Name == name {
+			anim = v.Anim
+			break
+		}
+	}
+	if anim == nil {
+		return fmt.Errorf("animation '%s' not found", name)
+	}
+	fps := 12.0
+	if err := prefabFloat(props, "fps", &fps); err != nil {
+		return err
+	}
+	ensureSprite(node)
+	graphics.SetSpriteAnimationComponentData(node.World(), node.Entity(), graphics.NewSpriteAnimation(fps, anim))
+	sa := graphics.GetSpriteAnimationComponentData(node.World(), node.Entity())
+	sa.SetReversed(props.BoolD("reversed", false))
+	if clip := props.String("clip"); clip != "" {
+		if !sa.PlayClip(clip) {
+			return fmt.Errorf("clip '%s' not found", clip)
+		}
+	}
+	return nil
+}
+
+func (l *PrefabLoader) prefabParticleEmitter(node *Node, p *io.Prefab, props dom.Attributes) error {
+	ensureDrawLayer(node)
+	graphics.SetParticleEmitterComponentData(node.World(), node.Entity(), graphics.NewParticleEmitter(node.World()))
+	pe := graphics.GetParticleEmitterComponentData(node.World(), node.Entity())
+	pp := pe.Props()
+	em := pe.EmissionProp()
+	for k, v := range props {
+		switch k {
+		case "max":
+			max, err := strconv.Atoi(v)
+			if err != nil {
+				return err
+			}
+			pe.SetMaxParticles(max)
+		case "strategy":
+			switch v {
+			case "pause":
+				pe.SetStrategy(graphics.SpawnPause)
+			case "replace":
+				pe.SetStrategy(graphics.SpawnReplace)
+			default:
+				return fmt.Errorf("invalid strategy '%s'", v)
+			}
+		case "enabled":
+			pe.SetEnabled(props.BoolD(k, true))
+		case "source":
+			atlas, err := l.atlasOf(p, props)
+			if err != nil {
+				return err
+			}
+			pp.Source = pp.Source[:0]
+			for _, frame := range strings.Split(v, ",") {
+				sub := atlas.GetSubImage(strings.TrimSpace(frame))
+				if sub == nil {
+					return fmt.Errorf("frame '%s' not found", frame)
+				}
+				pp.Source = append(pp.Source, sub.Image)
+			}
+		case "atlas":
+		default:
+			// ParticleProps (e.g. YVelocity="-80") and EmissionProp
+			// (e.g. emission.T0=".1") fields
+			var err error
+			if strings.HasPrefix(k, "emission.") {
+				err = setPrefabField(reflect.ValueOf(&em).Elem(), k[len("emission."):], v)
+			} else {
+				err = setPrefabField(reflect.ValueOf(&pp).Elem(), k, v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	pe.SetProps(pp)
+	pe.SetEmissionProp(em)
+	return nil
+}
+
+func (l *PrefabLoader) prefabAudioPlayer(node *Node, props dom.Attributes) error {
+	src := props.String("src")
+	if src == "" {
+		return fmt.Errorf("src not specified")
+	}
+	raw, err := l.c.GetAudioBytes(src)
+	if err != nil {
+		return err
+	}
+	if ap := components.GetAudioPlayerComponentData(node.World(), node.Entity()); ap != nil {
+		ap.Pause()
+	}
+	components.SetAudioPlayerComponentData(node.World(), node.Entity(), components.NewAudioPlayer(components.NewAudioPlayerInput{
+		RawAudio:      raw,
+		Panning:       props.BoolD("panning", false),
+		StereoPanning: props.BoolD("stereo", false),
+		PitchShift:    props.BoolD("pitchshift", false),
+		Infinite:      props.BoolD("loop", false),
+	}))
+	ap := components.GetAudioPlayerComponentData(node.World(), node.Entity())
+	volume, pan, pitch := ap.Volume(), ap.Pan(), ap.Pitch()
+	if err := firstErr(
+		prefabFloat(props, "volume", &volume),
+		prefabFloat(props, "pan", &pan),
+		prefabFloat(props, "pitch", &pitch),
+	); err != nil {
+		return err
+	}
+	ap.SetVolume(volume)
+	if props.HasAttr("pan") {
+		ap.SetPan(pan)
+	}
+	if props.HasAttr("pitch") {
+		ap.SetPitch(pitch)
+	}
+	if props.BoolD("play", false) {
+		ap.Play()
+	}
+	return nil
+}
+
+func prefabFloat(props dom.Attributes, name string, v *float64) error {
+	s, ok := props[name]
+	if !ok {
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	*v = f
+	return nil
+}
+
+// setPrefabField sets an exported field (float, int, bool or color) of a
+// struct by name
+func setPrefabField(st reflect.Value, name, value string) error {
+	f := st.FieldByName(name)
+	if !f.IsValid() || !f.CanSet() {
+		return fmt.Errorf("unknown property '%s'", name)
+	}
+	switch f.Kind() {
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		f.SetFloat(v)
+	case reflect.Int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		f.SetInt(int64(v))
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		f.SetBool(v)
+	default:
+		if f.Type() != reflect.TypeOf(color.RGBA{}) {
+			return fmt.Errorf("unsupported property '%s'", name)
+		}
+		f.Set(reflect.ValueOf(ColorFromHex(strings.TrimPrefix(value, "#"))))
+	}
+	return nil
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
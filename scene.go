@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/logger"
 	"github.com/gabstv/primen/io"
 )
 
@@ -52,6 +54,8 @@ func (e *engine) loadScenes() {
 	e.sceneldrs = RegisteredScenes()
 }
 
+// LoadScene replaces the top of the scene stack with a new scene. It returns
+// ErrSceneTransition while a PushScene, PopScene or ReplaceScene is pending.
 func (e *engine) LoadScene(name string) (scene Scene, sig chan struct{}, err error) {
 	return e.loadScene(name)
 }
@@ -61,18 +65,31 @@ func (e *engine) loadScene(name string) (scene Scene, sig chan struct{}, err err
 		//TODO: log error
 		return nil, nil, ErrSceneNotFound
 	}
+	e.lock.Lock()
+	pending := e.sceneChange != nil
+	e.lock.Unlock()
+	if pending {
+		return nil, nil, ErrSceneTransition
+	}
 	scene, sig = e.sceneldrs[name](e)
 	e.lock.Lock()
-	if e.lastScn != nil {
-		if scn, ok := e.lastScn.(AutoScene); ok {
+	var lastScn Scene
+	if n := len(e.scenes); n > 0 {
+		// LoadScene replaces the top of the scene stack
+		lastScn = e.scenes[n-1]
+		e.scenes[n-1] = scene
+	} else {
+		e.scenes = append(e.scenes, scene)
+	}
+	e.lock.Unlock()
+	if lastScn != nil {
+		if scn, ok := lastScn.(AutoScene); ok {
 			lscnch := scn.Unload()
 			if nscn, ok := scene.(AutoScene); ok {
 				nscn.PrevSceneCh(lscnch)
 			}
 		}
 	}
-	e.lastScn = scene
-	e.lock.Unlock()
 	return
 }
 
@@ -114,9 +131,25 @@ type SceneBase struct {
 
 func (s *SceneBase) Setup(engine Engine) {
 	s.Engine = engine
-	s.Container = io.NewContainer(context.Background(), engine.FS())
+	s.Container = io.NewContainer(logger.NewContext(context.Background(), engine.Logger()), engine.FS())
+}
+
+// SetupWithContainer is like Setup, but it uses an existing container (the
+// container of a scene registered with RegisterAssetScene).
+func (s *SceneBase) SetupWithContainer(engine Engine, c io.Container) {
+	s.Engine = engine
+	s.Container = c
+}
+
+// AddEventListener adds an engine event listener owned by the scene.
+// The listener is removed when the scene is destroyed.
+func (s *SceneBase) AddEventListener(eventName string, fn core.EventFn) core.EventID {
+	return s.Engine.AddEventListenerWithOptions(eventName, fn, core.ListenerOptions{
+		Owner: s,
+	})
 }
 
 func (s *SceneBase) Destroy() {
+	s.Engine.RemoveEventListenersByOwner(s)
 	s.Container.UnloadAll()
 }
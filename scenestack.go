@@ -0,0 +1,342 @@
+package primen
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+// SuspendableScene is a scene that is notified when another scene is pushed
+// over it (Suspend) and when it's on top of the stack again (Resume). Use it
+// to pause the scene worlds.
+type SuspendableScene interface {
+	Scene
+	Suspend()
+	Resume()
+}
+
+// Transition animates a scene change. The engine draws the frame of the new
+// scene stack and then calls Draw with the last frame of the previous
+// stack (from) and the progress of the transition (0 to 1).
+type Transition interface {
+	Duration() float64
+	Draw(screen, from *ebiten.Image, t float64)
+}
+
+type sceneOp int
+
+const (
+	scenePush    sceneOp = 1
+	scenePop     sceneOp = 2
+	sceneReplace sceneOp = 3
+)
+
+type sceneTransitionPhase int
+
+const (
+	sceneTrCapture sceneTransitionPhase = iota
+	sceneTrLoading
+	sceneTrRunning
+)
+
+type sceneChange struct {
+	op       sceneOp
+	name     string
+	scene    Scene
+	loaded   chan struct{}
+	tr       Transition
+	phase    sceneTransitionPhase
+	t        float64
+	snapshot *ebiten.Image
+	done     chan struct{}
+}
+
+// PushScene loads a scene and puts it on top of the scene stack. The current
+// top scene is suspended (if it implements SuspendableScene) once the new
+// scene is loaded. The returned channel is closed when the transition ends.
+// The scene is built on the main thread after the transition grabs the
+// previous frame; it is on top of SceneStack once the channel is closed.
+func (e *engine) PushScene(name string, tr Transition) (chan struct{}, error) {
+	return e.changeScene(scenePush, name, tr)
+}
+
+// ReplaceScene loads a scene and replaces the top of the scene stack with it.
+// The replaced scene is unloaded.
+func (e *engine) ReplaceScene(name string, tr Transition) (chan struct{}, error) {
+	return e.changeScene(sceneReplace, name, tr)
+}
+
+// PopScene unloads the scene on top of the stack. The scene below it is
+// resumed (if it implements SuspendableScene).
+func (e *engine) PopScene(tr Transition) (chan struct{}, error) {
+	return e.changeScene(scenePop, "", tr)
+}
+
+// SceneStack returns the scenes of the stack (the last one is on top)
+func (e *engine) SceneStack() []Scene {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	out := make([]Scene, len(e.scenes))
+	copy(out, e.scenes)
+	return out
+}
+
+func (e *engine) changeScene(op sceneOp, name string, tr Transition) (chan struct{}, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.sceneChange != nil {
+		return nil, ErrSceneTransition
+	}
+	if op == scenePop && len(e.scenes) < 1 {
+		return nil, ErrSceneStackEmpty
+	}
+	if op != scenePop {
+		if _, ok := e.sceneldrs[name]; !ok {
+			return nil, ErrSceneNotFound
+		}
+	}
+	c := &sceneChange{
+		op:   op,
+		name: name,
+		tr:   tr,
+		done: make(chan struct{}),
+	}
+	if tr == nil || e.headless {
+		// nothing to draw
+		c.tr = nil
+		c.phase = sceneTrLoading
+	}
+	e.sceneChange = c
+	return c.done, nil
+}
+
+// updateSceneChange runs on the main thread (before the modules and worlds
+// are updated)
+func (e *engine) updateSceneChange(delta float64) {
+	e.lock.Lock()
+	c := e.sceneChange
+	e.lock.Unlock()
+	if c == nil {
+		return
+	}
+	switch c.phase {
+	case sceneTrCapture:
+		// waiting for the next Draw to grab the current frame
+		return
+	case sceneTrLoading:
+		if c.op != scenePop && c.scene == nil {
+			// built after the capture, so the worlds of the new scene are
+			// not updated or drawn in the frame of the previous stack
+			c.scene, c.loaded = e.sceneldrs[c.name](e)
+		}
+		if c.loaded != nil {
+			select {
+			case <-c.loaded:
+			default:
+				return
+			}
+		}
+		e.applySceneChange(c)
+		if c.tr == nil {
+			e.endSceneChange(c)
+			return
+		}
+		c.phase = sceneTrRunning
+	case sceneTrRunning:
+		if d := c.tr.Duration(); d > 0 {
+			c.t += delta / d
+		} else {
+			c.t = 1
+		}
+		if c.t >= 1 {
+			e.endSceneChange(c)
+		}
+	}
+}
+
+func (e *engine) applySceneChange(c *sceneChange) {
+	e.lock.Lock()
+	var top, removed Scene
+	if n := len(e.scenes); n > 0 {
+		top = e.scenes[n-1]
+	}
+	switch c.op {
+	case scenePush:
+		e.scenes = append(e.scenes, c.scene)
+	case scenePop:
+		removed = top
+		e.scenes[len(e.scenes)-1] = nil
+		e.scenes = e.scenes[:len(e.scenes)-1]
+	case sceneReplace:
+		removed = top
+		if len(e.scenes) > 0 {
+			e.scenes[len(e.scenes)-1] = c.scene
+		} else {
+			e.scenes = append(e.scenes, c.scene)
+		}
+	}
+	var newtop Scene
+	if n := len(e.scenes); n > 0 {
+		newtop = e.scenes[n-1]
+	}
+	e.lock.Unlock()
+	if c.op == scenePush {
+		if s, ok := top.(SuspendableScene); ok {
+			s.Suspend()
+		}
+	}
+	if removed != nil {
+		ch := removed.Unload()
+		if s, ok := c.scene.(AutoScene); ok {
+			s.PrevSceneCh(ch)
+		}
+	}
+	if c.op == scenePop {
+		if s, ok := newtop.(SuspendableScene); ok {
+			s.Resume()
+		}
+	}
+}
+
+func (e *engine) endSceneChange(c *sceneChange) {
+	e.lock.Lock()
+	e.sceneChange = nil
+	e.lock.Unlock()
+	if c.snapshot != nil {
+		_ = c.snapshot.Dispose()
+	}
+	close(c.done)
+}
+
+// drawSceneChange grabs the previous frame (after the draw targets are
+// composed) and draws the transition
+func (e *engine) drawSceneChange(screen *ebiten.Image) {
+	e.lock.Lock()
+	c := e.sceneChange
+	e.lock.Unlock()
+	if c == nil || c.tr == nil {
+		return
+	}
+	if c.phase == sceneTrCapture {
+		w, h := screen.Size()
+		c.snapshot, _ = ebiten.NewImage(w, h, ebiten.FilterDefault)
+		c.snapshot.Fill(color.RGBA{0, 0, 0, 255})
+		c.snapshot.DrawImage(screen, &ebiten.DrawImageOptions{})
+		c.phase = sceneTrLoading
+	}
+	t := c.t
+	if t > 1 {
+		t = 1
+	}
+	c.tr.Draw(screen, c.snapshot, t)
+}
+
+type fadeTransition struct {
+	d float64
+	c color.RGBA
+}
+
+// FadeTransition fades the previous scene to a color, and then fades the
+// color to the new scene.
+func FadeTransition(duration float64, c color.Color) Transition {
+	r, g, b, _ := c.RGBA()
+	return &fadeTransition{
+		d: duration,
+		c: color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 255},
+	}
+}
+
+func (t *fadeTransition) Duration() float64 {
+	return t.d
+}
+
+func (t *fadeTransition) Draw(screen, from *ebiten.Image, p float64) {
+	var a float64
+	if p < .5 {
+		// fade out (the previous frame is still visible)
+		_ = screen.DrawImage(from, &ebiten.DrawImageOptions{})
+		a = p * 2
+	} else {
+		a = (1 - p) * 2
+	}
+	w, h := screen.Size()
+	c := t.c
+	c.R = uint8(float64(c.R) * a)
+	c.G = uint8(float64(c.G) * a)
+	c.B = uint8(float64(c.B) * a)
+	c.A = uint8(255 * a)
+	ebitenutil.DrawRect(screen, 0, 0, float64(w), float64(h), c)
+}
+
+type crossfadeTransition struct {
+	d float64
+}
+
+// CrossfadeTransition blends the previous scene into the new scene
+func CrossfadeTransition(duration float64) Transition {
+	return &crossfadeTransition{
+		d: duration,
+	}
+}
+
+func (t *crossfadeTransition) Duration() float64 {
+	return t.d
+}
+
+func (t *crossfadeTransition) Draw(screen, from *ebiten.Image, p float64) {
+	opt := &ebiten.DrawImageOptions{}
+	opt.ColorM.Scale(1, 1, 1, 1-p)
+	_ = screen.DrawImage(from, opt)
+}
+
+// WipeDirection is the direction of a wipe transition
+type WipeDirection int
+
+const (
+	WipeLeft  WipeDirection = 0
+	WipeRight WipeDirection = 1
+	WipeUp    WipeDirection = 2
+	WipeDown  WipeDirection = 3
+)
+
+type wipeTransition struct {
+	d   float64
+	dir WipeDirection
+}
+
+// WipeTransition reveals the new scene with an edge that moves in the
+// direction dir
+func WipeTransition(duration float64, dir WipeDirection) Transition {
+	return &wipeTransition{
+		d:   duration,
+		dir: dir,
+	}
+}
+
+func (t *wipeTransition) Duration() float64 {
+	return t.d
+}
+
+func (t *wipeTransition) Draw(screen, from *ebiten.Image, p float64) {
+	w, h := from.Size()
+	// the part of the previous frame that is still visible
+	r := image.Rect(0, 0, w, h)
+	switch t.dir {
+	case WipeLeft:
+		r.Max.X = int(float64(w) * (1 - p))
+	case WipeRight:
+		r.Min.X = int(float64(w) * p)
+	case WipeUp:
+		r.Max.Y = int(float64(h) * (1 - p))
+	case WipeDown:
+		r.Min.Y = int(float64(h) * p)
+	}
+	if r.Empty() {
+		return
+	}
+	opt := &ebiten.DrawImageOptions{}
+	opt.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	_ = screen.DrawImage(from.SubImage(r).(*ebiten.Image), opt)
+}
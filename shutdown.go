@@ -0,0 +1,101 @@
+package primen
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gabstv/primen/core"
+	"github.com/gabstv/primen/core/logger"
+)
+
+// OnShutdown adds a function to be called when the engine exits (after the
+// scenes are unloaded and before the modules are shut down). The hooks are
+// called in the reverse order they were added.
+func (e *engine) OnShutdown(fn func()) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.shutdownHooks = append(e.shutdownHooks, fn)
+}
+
+// Shutdown runs the shutdown sequence of the engine. Run calls it before
+// returning, so it only needs to be called by headless engines stepped
+// manually. It runs only once:
+//
+//  1. the scene stack is unloaded (from top to bottom), waiting for each
+//     Unload channel (up to NewEngineInput.ShutdownTimeout)
+//  2. the OnShutdown hooks are called
+//  3. the modules are shut down (core.ModuleShutdown), in the reverse
+//     order they were added
+//  4. the queued events are delivered, so pending saves dispatched as
+//     events are flushed
+func (e *engine) Shutdown() {
+	e.shutdownOnce.Do(e.shutdown)
+}
+
+func (e *engine) shutdown() {
+	e.lock.Lock()
+	scenes := e.scenes
+	e.scenes = nil
+	e.sceneChange = nil
+	e.lock.Unlock()
+	for i := len(scenes) - 1; i >= 0; i-- {
+		e.waitShutdown(scenes[i].Unload())
+	}
+
+	e.lock.Lock()
+	hooks := e.shutdownHooks
+	e.shutdownHooks = nil
+	modules := make([]moduleContainer, len(e.modules))
+	copy(modules, e.modules)
+	e.modules = nil
+	e.lock.Unlock()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].seq > modules[j].seq
+	})
+	for _, m := range modules {
+		if ms, ok := m.module.(core.ModuleShutdown); ok {
+			ms.Shutdown()
+		}
+	}
+
+	e.eventManager.Flush()
+	e.drainRunFns()
+	if err := e.saveConfig(e.Config()); err != nil {
+		e.log.Tag("engine").Error("saving config failed", logger.F("path", e.configPath), logger.Err(err))
+	}
+}
+
+// waitShutdown waits for ch to be closed while running the functions queued
+// with RunFn (the game loop is no longer running them).
+func (e *engine) waitShutdown(ch chan struct{}) {
+	if ch == nil {
+		return
+	}
+	timeout := time.NewTimer(e.shutdownTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-ch:
+			return
+		case fn := <-e.runfns:
+			fn()
+		case <-timeout.C:
+			return
+		}
+	}
+}
+
+func (e *engine) drainRunFns() {
+	for {
+		select {
+		case fn := <-e.runfns:
+			fn()
+		default:
+			return
+		}
+	}
+}
@@ -14,6 +14,7 @@ type Object interface {
 	World() World
 	Destroy()
 	SetParent(parent ObjectContainer)
+	Parent() ObjectContainer
 }
 
 // ObjectContainer is an object that contains other objects
@@ -47,6 +48,10 @@ func (o *mObject) SetParent(parent ObjectContainer) {
 	o.parent = parent
 }
 
+func (o *mObject) Parent() ObjectContainer {
+	return o.parent
+}
+
 func (o *mObject) Destroy() {
 	if o.parent != nil {
 		o.parent.RemoveChild(o)
@@ -20,6 +20,8 @@ func (a sortedWorldContainer) Less(i, j int) bool { return a[i].priority > a[j].
 type moduleContainer struct {
 	module   core.Module
 	priority int
+	name     string // used by the profiler and by the dependencies
+	seq      int    // the order the module was added (for shutdown)
 }
 
 // sortedModuleContainer implements sort.Interface for []moduleContainer based on